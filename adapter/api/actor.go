@@ -0,0 +1,24 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+const (
+	ActorHeader = "X-Actor"
+)
+
+// actor copies the unauthenticated X-Actor header into the request context.
+// The value is advisory: any client can set it, so it labels audit entries and
+// revisions but must not be trusted as the identity of whoever made a change.
+func actor() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echoCtx echo.Context) error {
+			request := echoCtx.Request()
+			ctx := usecase.WithActor(request.Context(), request.Header.Get(ActorHeader))
+			echoCtx.SetRequest(request.WithContext(ctx))
+			return next(echoCtx)
+		}
+	}
+}
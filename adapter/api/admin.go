@@ -0,0 +1,50 @@
+package api
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+var hiddenDebugVars = map[string]bool{"cmdline": true}
+
+type AdminServer struct {
+	addr string
+}
+
+func NewAdminServer(addr string) *AdminServer {
+	return &AdminServer{addr: addr}
+}
+
+func (as AdminServer) Run() {
+	echoInstance := as.router()
+	echoInstance.Logger.Fatal(echoInstance.Start(as.addr))
+}
+
+func (as AdminServer) router() *echo.Echo {
+	echoInstance := echo.New()
+	echoInstance.GET("/debug/vars", handleDebugVars)
+	return echoInstance
+}
+
+func handleDebugVars(echoCtx echo.Context) error {
+	response := echoCtx.Response()
+	response.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	response.WriteHeader(http.StatusOK)
+
+	separator := "{\n"
+	expvar.Do(func(kv expvar.KeyValue) {
+		if hiddenDebugVars[kv.Key] {
+			return
+		}
+		fmt.Fprintf(response, "%s%q: %s", separator, kv.Key, kv.Value)
+		separator = ",\n"
+	})
+	if separator == "{\n" {
+		fmt.Fprint(response, separator)
+	}
+	fmt.Fprint(response, "\n}\n")
+	return nil
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminServer_router(t *testing.T) {
+	t.Run("Should serve debug vars without the command line", adminDebugVarsWithoutCmdline)
+	t.Run("Should not expose debug vars on the public router", publicDebugVarsNotFound)
+}
+
+func adminDebugVarsWithoutCmdline(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewAdminServer("127.0.0.1:0").router().ServeHTTP(rec,
+		httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+
+	var vars map[string]json.RawMessage
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &vars))
+	assert.Contains(t, vars, "memstats")
+	assert.NotContains(t, vars, "cmdline")
+}
+
+func publicDebugVarsNotFound(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewWebServer("8080", repository.NewProductRepositoryInMemory()).router().ServeHTTP(rec,
+		httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+func customMethod(echoCtx echo.Context, param, method string) (string, error) {
+	value, found := strings.CutSuffix(echoCtx.Param(param), ":"+method)
+	if !found || value == "" {
+		return "", echo.ErrNotFound
+	}
+	return value, nil
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/lbsti/eulabs-challenge/internal/core/entity"
@@ -17,7 +19,17 @@ func Mapping(input error) MappedError {
 		entity.RequiredReferenceErr,
 		entity.InvalidPriceErr,
 		entity.RequiredDescriptionErr,
-		entity.RequiredTitleErr:
+		entity.RequiredTitleErr,
+		entity.InvalidPaginationErr,
+		entity.InvalidPriceRangeErr,
+		entity.UnsupportedCurrencyErr,
+		entity.InvalidFieldErr,
+		entity.InvalidRevisionErr,
+		entity.InvalidWebhookURLErr,
+		entity.PrivateWebhookTargetErr,
+		entity.RequiredWebhookSecretErr,
+		entity.InvalidEventTypeErr,
+		entity.InvalidLastEventIDErr:
 		return MappedError{
 			ResultErr: input,
 			Code:      http.StatusBadRequest,
@@ -27,12 +39,19 @@ func Mapping(input error) MappedError {
 			ResultErr: input,
 			Code:      http.StatusConflict,
 		}
-	case entity.ProductNotFoundErr:
+	case entity.ProductNotFoundErr, entity.RevisionNotFoundErr,
+		entity.WebhookNotFoundErr, entity.DeliveryNotFoundErr:
 		return MappedError{
 			ResultErr: input,
 			Code:      http.StatusNotFound,
 		}
 	}
+	if errors.Is(input, context.DeadlineExceeded) {
+		return MappedError{
+			ResultErr: input,
+			Code:      http.StatusGatewayTimeout,
+		}
+	}
 	return MappedError{
 		ResultErr: input,
 		Code:      http.StatusInternalServerError,
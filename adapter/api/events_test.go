@@ -0,0 +1,154 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/lbsti/eulabs-challenge/internal/infra/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+type sseFrame struct {
+	id      string
+	event   string
+	data    string
+	comment string
+}
+
+func TestWebServer_handleProductEvents(t *testing.T) {
+	t.Run("Should resume after the last event id filtering by code prefix", productEventsResume)
+	t.Run("Should send a reset event when the last event id is not retained", productEventsReset)
+	t.Run("Should push live events with the product payload", productEventsLive)
+	t.Run("Should send heartbeat comments", productEventsHeartbeat)
+	t.Run("Should results bad request if last event id is invalid", productEventsInvalidLastEventIDErr)
+}
+
+func newEventsServer(t *testing.T, eventLog *stream.EventLog, heartbeat time.Duration) *httptest.Server {
+	ws := NewWebServer("8080", repository.NewProductRepositoryInMemory(),
+		usecase.WithEventEmitter(eventLog))
+	ws.EnableProductEvents(eventLog, heartbeat)
+	echoInstance := echo.New()
+	echoInstance.GET("/api/v1/products/events", ws.handleProductEvents)
+	echoInstance.POST("/api/v1/products", ws.handleProductCreate,
+		contentNegotiation(v1MediaTypes...))
+	server := httptest.NewServer(echoInstance)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func openEventStream(t *testing.T, url, lastEventID string) *bufio.Reader {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	assert.NoError(t, err)
+	req.Header.Set(echo.HeaderAccept, mimeTextEventStream)
+	if lastEventID != "" {
+		req.Header.Set(LastEventIDHeader, lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, mimeTextEventStream, resp.Header.Get(echo.HeaderContentType))
+	return bufio.NewReader(resp.Body)
+}
+
+func readFrame(t *testing.T, reader *bufio.Reader) sseFrame {
+	var frame sseFrame
+	for {
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return frame
+		}
+		switch {
+		case strings.HasPrefix(line, ":"):
+			frame.comment = strings.TrimSpace(line[1:])
+		case strings.HasPrefix(line, "id: "):
+			frame.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			frame.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			frame.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func productEventsResume(t *testing.T) {
+	eventLog := stream.NewEventLog(10)
+	for _, code := range []string{"XXCC", "ABCD", "XXDD", "XXEE"} {
+		eventLog.Emit(context.TODO(), usecase.ProductEvent{Type: entity.ProductUpdatedEvent, Code: code})
+	}
+	server := newEventsServer(t, eventLog, time.Minute)
+
+	reader := openEventStream(t, server.URL+"/api/v1/products/events?code=xx", eventLog.Epoch()+"-1")
+	frame := readFrame(t, reader)
+	assert.Equal(t, eventLog.Epoch()+"-3", frame.id)
+	assert.Equal(t, entity.ProductUpdatedEvent, frame.event)
+	assert.Contains(t, frame.data, `"code":"XXDD"`)
+	frame = readFrame(t, reader)
+	assert.Equal(t, eventLog.Epoch()+"-4", frame.id)
+}
+
+func productEventsReset(t *testing.T) {
+	eventLog := stream.NewEventLog(10)
+	eventLog.Emit(context.TODO(), usecase.ProductEvent{Type: entity.ProductUpdatedEvent, Code: "XXCC"})
+	server := newEventsServer(t, eventLog, time.Minute)
+
+	reader := openEventStream(t, server.URL+"/api/v1/products/events", "previous-42")
+	frame := readFrame(t, reader)
+	assert.Equal(t, eventLog.Epoch()+"-1", frame.id)
+	assert.Equal(t, resetEventType, frame.event)
+	assert.JSONEq(t, `{"epoch":"`+eventLog.Epoch()+`","sequence":1}`, frame.data)
+}
+
+func productEventsLive(t *testing.T) {
+	eventLog := stream.NewEventLog(10)
+	server := newEventsServer(t, eventLog, time.Minute)
+	reader := openEventStream(t, server.URL+"/api/v1/products/events", "")
+
+	resp, err := http.Post(server.URL+"/api/v1/products", echo.MIMEApplicationJSON,
+		strings.NewReader(`{"title":"Toy","description":"Description","code":"XXCC",`+
+			`"reference":"XZsdf5tY-AA","priceInCents":2500}`))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	frame := readFrame(t, reader)
+	var event usecase.ProductEvent
+	assert.NoError(t, json.Unmarshal([]byte(frame.data), &event))
+	assert.Equal(t, eventLog.Epoch()+"-1", frame.id)
+	assert.Equal(t, entity.ProductCreatedEvent, frame.event)
+	assert.Equal(t, "XXCC", event.Code)
+	assert.Equal(t, int64(2500), event.Product.PriceInCents)
+}
+
+func productEventsHeartbeat(t *testing.T) {
+	server := newEventsServer(t, stream.NewEventLog(10), 10*time.Millisecond)
+	reader := openEventStream(t, server.URL+"/api/v1/products/events", "")
+	assert.Equal(t, "heartbeat", readFrame(t, reader).comment)
+}
+
+func productEventsInvalidLastEventIDErr(t *testing.T) {
+	ws := NewWebServer("8080", repository.NewProductRepositoryInMemory())
+	ws.EnableProductEvents(stream.NewEventLog(10), time.Minute)
+	echoInstance := echo.New()
+	echoInstance.GET("/api/v1/products/events", ws.handleProductEvents)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/events", nil)
+	req.Header.Set(LastEventIDHeader, "abc")
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"reflect"
+	"strings"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type sparseFieldset struct {
+	value  interface{}
+	fields map[string]bool
+}
+
+type selectedField struct {
+	jsonName string
+	xmlName  string
+	value    interface{}
+}
+
+func parseFields(raw string, representationFields map[string]string) (map[string]bool, []string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil, nil
+	}
+	fields := map[string]bool{}
+	repositoryFields := []string{}
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		repositoryField, ok := representationFields[field]
+		if !ok {
+			return nil, nil, entity.InvalidFieldErr
+		}
+		if fields[field] {
+			continue
+		}
+		fields[field] = true
+		repositoryFields = append(repositoryFields, repositoryField)
+	}
+	return fields, repositoryFields, nil
+}
+
+func project(value interface{}, fields map[string]bool) interface{} {
+	if fields == nil {
+		return value
+	}
+	return sparseFieldset{value: value, fields: fields}
+}
+
+func (s sparseFieldset) selected() []selectedField {
+	value := reflect.ValueOf(s.value)
+	valueType := value.Type()
+	selected := []selectedField{}
+	for index := 0; index < valueType.NumField(); index++ {
+		jsonName := tagName(valueType.Field(index).Tag.Get("json"))
+		if jsonName == "" || jsonName == "-" || !s.fields[jsonName] {
+			continue
+		}
+		selected = append(selected, selectedField{
+			jsonName: jsonName,
+			xmlName:  tagName(valueType.Field(index).Tag.Get("xml")),
+			value:    value.Field(index).Interface(),
+		})
+	}
+	return selected
+}
+
+func (s sparseFieldset) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for index, field := range s.selected() {
+		if index > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(field.jsonName)
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (s sparseFieldset) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	if xmlNameField, ok := reflect.TypeOf(s.value).FieldByName("XMLName"); ok {
+		start = xml.StartElement{Name: xml.Name{Local: tagName(xmlNameField.Tag.Get("xml"))}}
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, field := range s.selected() {
+		if err := encoder.EncodeElement(field.value,
+			xml.StartElement{Name: xml.Name{Local: field.xmlName}}); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+func (s sparseFieldset) EncodeMsgpack(encoder *msgpack.Encoder) error {
+	selected := s.selected()
+	if err := encoder.EncodeMapLen(len(selected)); err != nil {
+		return err
+	}
+	for _, field := range selected {
+		if err := encoder.EncodeString(field.jsonName); err != nil {
+			return err
+		}
+		if err := encoder.Encode(field.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s sparseFieldset) toProto() proto.Message {
+	mapper, ok := s.value.(protoMapper)
+	if !ok {
+		return nil
+	}
+	message := mapper.toProto()
+	reflection := message.ProtoReflect()
+	unselected := []protoreflect.FieldDescriptor{}
+	reflection.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !s.fields[fd.JSONName()] {
+			unselected = append(unselected, fd)
+		}
+		return true
+	})
+	for _, fd := range unselected {
+		reflection.Clear(fd)
+	}
+	return message
+}
+
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/adapter/rpc/pb"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	infrarepository "github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+func newFieldsetServer(productRepo repository.ProductRepository) *echo.Echo {
+	ws := NewWebServer("8080", productRepo)
+	echoInstance := echo.New()
+	v1Group := echoInstance.Group("/api/v1", contentNegotiation(v1MediaTypes...))
+	v1Group.GET("/products", ws.handleProductList)
+	v1Group.GET("/products/:code", ws.handleProductGet)
+	v2Group := echoInstance.Group("/api/v2", contentNegotiation(v2MediaTypes...))
+	v2Group.GET("/products", ws.handleProductListV2)
+	v2Group.GET("/products/:code", ws.handleProductGetV2)
+	return echoInstance
+}
+
+func fieldsetRepo() infrarepository.ProductRepositoryInMemorySpy {
+	return infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedData: repository.ProductRepositoryData{
+			ID:           7,
+			Code:         "XSZ-000741",
+			Title:        "Toy",
+			PriceInCents: 51400,
+		},
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	t.Run("Should results nil when fields are not informed", parseFieldsEmpty)
+	t.Run("Should map and dedupe representation fields", parseFieldsMapped)
+	t.Run("Should results error if a field is unknown", parseFieldsInvalid)
+}
+
+func parseFieldsEmpty(t *testing.T) {
+	fields, repositoryFields, err := parseFields(" ", v1Fields)
+	assert.NoError(t, err)
+	assert.Nil(t, fields)
+	assert.Nil(t, repositoryFields)
+}
+
+func parseFieldsMapped(t *testing.T) {
+	fields, repositoryFields, err := parseFields("code, price,code", v2Fields)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"code": true, "price": true}, fields)
+	assert.Equal(t, []string{repository.ProductFieldCode, repository.ProductFieldPriceInCents}, repositoryFields)
+}
+
+func parseFieldsInvalid(t *testing.T) {
+	_, _, err := parseFields("code,price", v1Fields)
+	assert.Error(t, err)
+}
+
+func TestWebServer_sparseFieldsets(t *testing.T) {
+	t.Run("Should get only the requested fields as json", getProductFieldsJSON)
+	t.Run("Should get only the requested fields as xml", getProductFieldsXML)
+	t.Run("Should get only the requested fields as msgpack", getProductFieldsMsgpack)
+	t.Run("Should get only the requested fields as protobuf", getProductFieldsProtobuf)
+	t.Run("Should get only the requested v2 fields", getProductFieldsV2)
+	t.Run("Should results error if a field is unknown", getProductFieldsInvalid)
+	t.Run("Should list products with the requested fields", listProductsFields)
+	t.Run("Should list v2 products with the requested fields", listProductsFieldsV2)
+}
+
+func getProductFieldsJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741?fields=title,code", nil)
+	newFieldsetServer(fieldsetRepo()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "{\"title\":\"Toy\",\"code\":\"XSZ-000741\"}\n", rec.Body.String())
+}
+
+func getProductFieldsXML(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741?fields=code", nil)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationXML)
+	newFieldsetServer(fieldsetRepo()).ServeHTTP(rec, req)
+
+	var outputDTO ProductResponseV1
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "<product><code>XSZ-000741</code></product>")
+	assert.Empty(t, outputDTO.Title)
+}
+
+func getProductFieldsMsgpack(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741?fields=id,priceInCents", nil)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationMsgpack)
+	newFieldsetServer(fieldsetRepo()).ServeHTTP(rec, req)
+
+	var outputDTO map[string]interface{}
+	assert.NoError(t, msgpack.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, outputDTO, 2)
+	assert.EqualValues(t, 51400, outputDTO["priceInCents"])
+}
+
+func getProductFieldsProtobuf(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741?fields=code", nil)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationProtobuf)
+	newFieldsetServer(fieldsetRepo()).ServeHTTP(rec, req)
+
+	var output pb.Product
+	assert.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &output))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "XSZ-000741", output.GetCode())
+	assert.Empty(t, output.GetTitle())
+	assert.Zero(t, output.GetId())
+}
+
+func getProductFieldsV2(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/products/XSZ-000741?fields=price", nil)
+	newFieldsetServer(fieldsetRepo()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "{\"price\":{\"amount\":51400,\"currency\":\"BRL\"}}\n", rec.Body.String())
+}
+
+func getProductFieldsInvalid(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741?fields=title,secret", nil)
+	newFieldsetServer(fieldsetRepo()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "{\"message\":\"field is invalid\"}\n", rec.Body.String())
+}
+
+func listProductsFields(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products?fields=code&page=1&pageSize=10", nil)
+	newFieldsetServer(fieldsetRepo()).ServeHTTP(rec, req)
+
+	var outputDTO struct {
+		Items    []map[string]interface{} `json:"items"`
+		Page     int                      `json:"page"`
+		PageSize int                      `json:"pageSize"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 10, outputDTO.PageSize)
+	assert.Equal(t, []map[string]interface{}{{"code": "XSZ-000741"}}, outputDTO.Items)
+}
+
+func listProductsFieldsV2(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/products?fields=code,price&minPrice=100", nil)
+	newFieldsetServer(fieldsetRepo()).ServeHTTP(rec, req)
+
+	var outputDTO struct {
+		Items []ProductResponseV2 `json:"items"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, outputDTO.Items, 1)
+	assert.Equal(t, "XSZ-000741", outputDTO.Items[0].Code)
+	assert.Empty(t, outputDTO.Items[0].Title)
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebServer_handleProductHistory(t *testing.T) {
+	t.Run("Should list the product history with the request actor", productHistorySuccess)
+	t.Run("Should results error if pagination is invalid", productHistoryPaginationErr)
+}
+
+func newHistoryServer() *echo.Echo {
+	auditRepo := repository.NewProductAuditRepositoryInMemory()
+	ws := NewWebServer("8080", repository.NewProductRepositoryInMemory(repository.WithAuditTrail(auditRepo)),
+		usecase.WithAuditRepository(auditRepo))
+	echoInstance := echo.New()
+	echoInstance.Use(actor())
+	grApi := echoInstance.Group("/api/v1", contentNegotiation(v1MediaTypes...))
+	grApi.POST("/products", ws.handleProductCreate)
+	grApi.GET("/products/:code/history", ws.handleProductHistory)
+	return echoInstance
+}
+
+func productHistorySuccess(t *testing.T) {
+	echoInstance := newHistoryServer()
+	body, err := json.Marshal(usecase.ProductInputDTO{
+		Title:        "Toy",
+		Description:  "Description",
+		Code:         "XXCC",
+		Reference:    "XZsdf5tY-AA",
+		PriceInCents: int64(2500),
+	})
+	assert.NoError(t, err)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(ActorHeader, "merchandiser@eulabs")
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/products/XXCC/history", nil)
+	echoInstance.ServeHTTP(rec, req)
+
+	var outputDTO ProductHistoryResponseV1
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(1), outputDTO.Total)
+	assert.Equal(t, "create", outputDTO.Items[0].Operation)
+	assert.Equal(t, "merchandiser@eulabs", outputDTO.Items[0].Actor)
+	assert.Nil(t, outputDTO.Items[0].Before)
+	assert.Equal(t, int64(2500), outputDTO.Items[0].After.PriceInCents)
+}
+
+func productHistoryPaginationErr(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XXCC/history?page=-1", nil)
+	newHistoryServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
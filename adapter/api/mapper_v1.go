@@ -0,0 +1,205 @@
+package api
+
+import (
+	"encoding/xml"
+
+	"github.com/lbsti/eulabs-challenge/adapter/rpc/pb"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"google.golang.org/protobuf/proto"
+)
+
+var v1Fields = map[string]string{
+	repository.ProductFieldID:           repository.ProductFieldID,
+	repository.ProductFieldTitle:        repository.ProductFieldTitle,
+	repository.ProductFieldDescription:  repository.ProductFieldDescription,
+	repository.ProductFieldCode:         repository.ProductFieldCode,
+	repository.ProductFieldReference:    repository.ProductFieldReference,
+	repository.ProductFieldPriceInCents: repository.ProductFieldPriceInCents,
+	repository.ProductFieldCreatedAt:    repository.ProductFieldCreatedAt,
+	repository.ProductFieldUpdatedAt:    repository.ProductFieldUpdatedAt,
+}
+
+type ProductCreateResponseV1 struct {
+	XMLName   xml.Name `json:"-" xml:"product"`
+	CreatedAt string   `json:"createdAt" xml:"createdAt"`
+	Reference string   `json:"reference" xml:"reference"`
+	ID        int64    `json:"id" xml:"id"`
+}
+
+type ProductResponseV1 struct {
+	XMLName      xml.Name `json:"-" xml:"product"`
+	CreatedAt    string   `json:"createdAt" xml:"createdAt"`
+	UpdatedAt    string   `json:"updatedAt" xml:"updatedAt"`
+	DeletedAt    string   `json:"deletedAt,omitempty" xml:"deletedAt,omitempty"`
+	Title        string   `json:"title" xml:"title"`
+	Description  string   `json:"description" xml:"description"`
+	Code         string   `json:"code" xml:"code"`
+	Reference    string   `json:"reference" xml:"reference"`
+	PriceInCents int64    `json:"priceInCents" xml:"priceInCents"`
+	ID           int64    `json:"id" xml:"id"`
+}
+
+type ProductListResponseV1 struct {
+	XMLName  xml.Name      `json:"-" xml:"products"`
+	Items    []interface{} `json:"items" xml:"product"`
+	Page     int           `json:"page" xml:"page,attr"`
+	PageSize int           `json:"pageSize" xml:"pageSize,attr"`
+	Total    int64         `json:"total" xml:"total,attr"`
+}
+
+type ProductAuditResponseV1 struct {
+	ID        int64              `json:"id" xml:"id,attr"`
+	Operation string             `json:"operation" xml:"operation"`
+	Actor     string             `json:"actor" xml:"actor"`
+	Before    *ProductResponseV1 `json:"before" xml:"before>product,omitempty"`
+	After     *ProductResponseV1 `json:"after" xml:"after>product,omitempty"`
+	CreatedAt string             `json:"createdAt" xml:"createdAt"`
+}
+
+type ProductHistoryResponseV1 struct {
+	XMLName  xml.Name                 `json:"-" xml:"history"`
+	Items    []ProductAuditResponseV1 `json:"items" xml:"entry"`
+	Page     int                      `json:"page" xml:"page,attr"`
+	PageSize int                      `json:"pageSize" xml:"pageSize,attr"`
+	Total    int64                    `json:"total" xml:"total,attr"`
+}
+
+type ProductFieldChangeResponseV1 struct {
+	Field string      `json:"field" xml:"field,attr"`
+	Old   interface{} `json:"old" xml:"old"`
+	New   interface{} `json:"new" xml:"new"`
+}
+
+type ProductDiffLineResponseV1 struct {
+	Operation string `json:"op" xml:"op,attr"`
+	Text      string `json:"text" xml:",chardata"`
+}
+
+type ProductDiffResponseV1 struct {
+	XMLName         xml.Name                       `json:"-" xml:"diff"`
+	Code            string                         `json:"code" xml:"code,attr"`
+	From            int                            `json:"from" xml:"from,attr"`
+	To              int                            `json:"to" xml:"to,attr"`
+	Changes         []ProductFieldChangeResponseV1 `json:"changes" xml:"changes>change"`
+	DescriptionDiff []ProductDiffLineResponseV1    `json:"descriptionDiff" xml:"descriptionDiff>line"`
+}
+
+func toProductCreateResponseV1(outputDTO usecase.ProductOutputDTO) ProductCreateResponseV1 {
+	return ProductCreateResponseV1{
+		CreatedAt: outputDTO.CreatedAt,
+		Reference: outputDTO.Reference,
+		ID:        outputDTO.ID,
+	}
+}
+
+func toProductResponseV1(outputDTO usecase.ProductGetOutputDTO) ProductResponseV1 {
+	return ProductResponseV1{
+		CreatedAt:    outputDTO.CreatedAt,
+		UpdatedAt:    outputDTO.UpdatedAt,
+		DeletedAt:    outputDTO.DeletedAt,
+		Title:        outputDTO.Title,
+		Description:  outputDTO.Description,
+		Code:         outputDTO.Code,
+		Reference:    outputDTO.Reference,
+		PriceInCents: outputDTO.PriceInCents,
+		ID:           outputDTO.ID,
+	}
+}
+
+func (r ProductCreateResponseV1) toProto() proto.Message {
+	return &pb.CreateProductResponse{
+		Id:        r.ID,
+		Reference: r.Reference,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r ProductResponseV1) toProto() proto.Message {
+	return &pb.Product{
+		Id:           r.ID,
+		Title:        r.Title,
+		Description:  r.Description,
+		Code:         r.Code,
+		Reference:    r.Reference,
+		PriceInCents: r.PriceInCents,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+		DeletedAt:    r.DeletedAt,
+	}
+}
+
+func toProductListResponseV1(outputDTO usecase.ProductListOutputDTO,
+	fields map[string]bool) ProductListResponseV1 {
+	items := make([]interface{}, 0, len(outputDTO.Items))
+	for _, item := range outputDTO.Items {
+		items = append(items, project(toProductResponseV1(item), fields))
+	}
+	return ProductListResponseV1{
+		Items:    items,
+		Page:     outputDTO.Page,
+		PageSize: outputDTO.PageSize,
+		Total:    outputDTO.Total,
+	}
+}
+
+func (r ProductListResponseV1) toProto() proto.Message {
+	items := make([]*pb.Product, 0, len(r.Items))
+	for _, item := range r.Items {
+		if product, ok := item.(protoMapper).toProto().(*pb.Product); ok {
+			items = append(items, product)
+		}
+	}
+	return &pb.ProductList{
+		Items:    items,
+		Page:     int32(r.Page),
+		PageSize: int32(r.PageSize),
+		Total:    r.Total,
+	}
+}
+
+func toProductHistoryResponseV1(outputDTO usecase.ProductHistoryOutputDTO) ProductHistoryResponseV1 {
+	items := make([]ProductAuditResponseV1, 0, len(outputDTO.Items))
+	for _, item := range outputDTO.Items {
+		items = append(items, ProductAuditResponseV1{
+			ID:        item.ID,
+			Operation: item.Operation,
+			Actor:     item.Actor,
+			Before:    toProductSnapshotResponseV1(item.Before),
+			After:     toProductSnapshotResponseV1(item.After),
+			CreatedAt: item.CreatedAt,
+		})
+	}
+	return ProductHistoryResponseV1{
+		Items:    items,
+		Page:     outputDTO.Page,
+		PageSize: outputDTO.PageSize,
+		Total:    outputDTO.Total,
+	}
+}
+
+func toProductSnapshotResponseV1(outputDTO *usecase.ProductGetOutputDTO) *ProductResponseV1 {
+	if outputDTO == nil {
+		return nil
+	}
+	response := toProductResponseV1(*outputDTO)
+	return &response
+}
+
+func toProductDiffResponseV1(outputDTO usecase.ProductDiffOutputDTO) ProductDiffResponseV1 {
+	changes := make([]ProductFieldChangeResponseV1, 0, len(outputDTO.Changes))
+	for _, change := range outputDTO.Changes {
+		changes = append(changes, ProductFieldChangeResponseV1(change))
+	}
+	lines := make([]ProductDiffLineResponseV1, 0, len(outputDTO.DescriptionDiff))
+	for _, line := range outputDTO.DescriptionDiff {
+		lines = append(lines, ProductDiffLineResponseV1(line))
+	}
+	return ProductDiffResponseV1{
+		Code:            outputDTO.Code,
+		From:            outputDTO.From,
+		To:              outputDTO.To,
+		Changes:         changes,
+		DescriptionDiff: lines,
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+const (
+	DefaultCurrency = "BRL"
+)
+
+var repositoryTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05",
+}
+
+var v2Fields = map[string]string{
+	"id":          repository.ProductFieldID,
+	"code":        repository.ProductFieldCode,
+	"title":       repository.ProductFieldTitle,
+	"description": repository.ProductFieldDescription,
+	"reference":   repository.ProductFieldReference,
+	"price":       repository.ProductFieldPriceInCents,
+	"createdAt":   repository.ProductFieldCreatedAt,
+	"updatedAt":   repository.ProductFieldUpdatedAt,
+}
+
+type MoneyV2 struct {
+	Amount   int64  `json:"amount" xml:"amount"`
+	Currency string `json:"currency" xml:"currency"`
+}
+
+type ProductRequestV2 struct {
+	Title       string  `json:"title" xml:"title"`
+	Description string  `json:"description" xml:"description"`
+	Code        string  `json:"code" xml:"code"`
+	Reference   string  `json:"reference" xml:"reference"`
+	Price       MoneyV2 `json:"price" xml:"price"`
+}
+
+type ProductResponseV2 struct {
+	XMLName     xml.Name `json:"-" xml:"product"`
+	ID          int64    `json:"id" xml:"id"`
+	Code        string   `json:"code" xml:"code"`
+	Title       string   `json:"title" xml:"title"`
+	Description string   `json:"description" xml:"description"`
+	Reference   string   `json:"reference" xml:"reference"`
+	Price       MoneyV2  `json:"price" xml:"price"`
+	CreatedAt   string   `json:"createdAt" xml:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt" xml:"updatedAt"`
+}
+
+type ProductListResponseV2 struct {
+	XMLName  xml.Name      `json:"-" xml:"products"`
+	Items    []interface{} `json:"items" xml:"product"`
+	Page     int           `json:"page" xml:"page,attr"`
+	PageSize int           `json:"pageSize" xml:"pageSize,attr"`
+	Total    int64         `json:"total" xml:"total,attr"`
+}
+
+func fromProductRequestV2(request ProductRequestV2) (usecase.ProductInputDTO, error) {
+	if currency := strings.ToUpper(strings.TrimSpace(request.Price.Currency)); currency != DefaultCurrency {
+		return usecase.ProductInputDTO{}, entity.UnsupportedCurrencyErr
+	}
+	return usecase.ProductInputDTO{
+		Title:        request.Title,
+		Description:  request.Description,
+		Code:         request.Code,
+		Reference:    request.Reference,
+		PriceInCents: request.Price.Amount,
+	}, nil
+}
+
+func toProductResponseV2(outputDTO usecase.ProductGetOutputDTO) ProductResponseV2 {
+	return ProductResponseV2{
+		ID:          outputDTO.ID,
+		Code:        outputDTO.Code,
+		Title:       outputDTO.Title,
+		Description: outputDTO.Description,
+		Reference:   outputDTO.Reference,
+		Price: MoneyV2{
+			Amount:   outputDTO.PriceInCents,
+			Currency: DefaultCurrency,
+		},
+		CreatedAt: toRFC3339(outputDTO.CreatedAt),
+		UpdatedAt: toRFC3339(outputDTO.UpdatedAt),
+	}
+}
+
+func toProductListResponseV2(outputDTO usecase.ProductListOutputDTO,
+	fields map[string]bool) ProductListResponseV2 {
+	items := make([]interface{}, 0, len(outputDTO.Items))
+	for _, item := range outputDTO.Items {
+		items = append(items, project(toProductResponseV2(item), fields))
+	}
+	return ProductListResponseV2{
+		Items:    items,
+		Page:     outputDTO.Page,
+		PageSize: outputDTO.PageSize,
+		Total:    outputDTO.Total,
+	}
+}
+
+func toRFC3339(value string) string {
+	for _, layout := range repositoryTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed.UTC().Format(time.RFC3339)
+		}
+	}
+	return value
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/xml"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+type WebhookResponseV1 struct {
+	XMLName    xml.Name `json:"-" xml:"webhook"`
+	ID         int64    `json:"id" xml:"id"`
+	URL        string   `json:"url" xml:"url"`
+	Secret     string   `json:"secret,omitempty" xml:"secret,omitempty"`
+	EventTypes []string `json:"eventTypes" xml:"eventTypes>eventType"`
+	Active     bool     `json:"active" xml:"active"`
+	CreatedAt  string   `json:"createdAt" xml:"createdAt"`
+	UpdatedAt  string   `json:"updatedAt" xml:"updatedAt"`
+}
+
+type WebhookListResponseV1 struct {
+	XMLName xml.Name            `json:"-" xml:"webhooks"`
+	Items   []WebhookResponseV1 `json:"items" xml:"webhook"`
+}
+
+type WebhookDeliveryResponseV1 struct {
+	XMLName   xml.Name `json:"-" xml:"delivery"`
+	ID        int64    `json:"id" xml:"id"`
+	WebhookID int64    `json:"webhookId" xml:"webhookId"`
+	EventID   string   `json:"eventId" xml:"eventId"`
+	EventType string   `json:"eventType" xml:"eventType"`
+	Status    string   `json:"status" xml:"status"`
+	Attempts  int      `json:"attempts" xml:"attempts"`
+	LastError string   `json:"lastError,omitempty" xml:"lastError,omitempty"`
+	CreatedAt string   `json:"createdAt" xml:"createdAt"`
+	UpdatedAt string   `json:"updatedAt" xml:"updatedAt"`
+}
+
+type WebhookDeliveryListResponseV1 struct {
+	XMLName  xml.Name                    `json:"-" xml:"deliveries"`
+	Items    []WebhookDeliveryResponseV1 `json:"items" xml:"delivery"`
+	Page     int                         `json:"page" xml:"page,attr"`
+	PageSize int                         `json:"pageSize" xml:"pageSize,attr"`
+	Total    int64                       `json:"total" xml:"total,attr"`
+}
+
+func toWebhookResponseV1(outputDTO usecase.WebhookOutputDTO) WebhookResponseV1 {
+	return WebhookResponseV1{
+		ID:         outputDTO.ID,
+		URL:        outputDTO.URL,
+		Secret:     outputDTO.Secret,
+		EventTypes: outputDTO.EventTypes,
+		Active:     outputDTO.Active,
+		CreatedAt:  outputDTO.CreatedAt,
+		UpdatedAt:  outputDTO.UpdatedAt,
+	}
+}
+
+func toWebhookListResponseV1(outputDTOs []usecase.WebhookOutputDTO) WebhookListResponseV1 {
+	items := make([]WebhookResponseV1, 0, len(outputDTOs))
+	for _, outputDTO := range outputDTOs {
+		items = append(items, toWebhookResponseV1(outputDTO))
+	}
+	return WebhookListResponseV1{Items: items}
+}
+
+func toWebhookDeliveryResponseV1(outputDTO usecase.WebhookDeliveryOutputDTO) WebhookDeliveryResponseV1 {
+	return WebhookDeliveryResponseV1{
+		ID:        outputDTO.ID,
+		WebhookID: outputDTO.WebhookID,
+		EventID:   outputDTO.EventID,
+		EventType: outputDTO.EventType,
+		Status:    outputDTO.Status,
+		Attempts:  outputDTO.Attempts,
+		LastError: outputDTO.LastError,
+		CreatedAt: outputDTO.CreatedAt,
+		UpdatedAt: outputDTO.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryListResponseV1(outputDTO usecase.WebhookDeliveryListOutputDTO) WebhookDeliveryListResponseV1 {
+	items := make([]WebhookDeliveryResponseV1, 0, len(outputDTO.Items))
+	for _, item := range outputDTO.Items {
+		items = append(items, toWebhookDeliveryResponseV1(item))
+	}
+	return WebhookDeliveryListResponseV1{
+		Items:    items,
+		Page:     outputDTO.Page,
+		PageSize: outputDTO.PageSize,
+		Total:    outputDTO.Total,
+	}
+}
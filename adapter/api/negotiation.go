@@ -0,0 +1,195 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/adapter/rpc/pb"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	mediaTypeKey = "mediaType"
+)
+
+var mediaTypeAliases = map[string]string{
+	echo.MIMEApplicationJSON:     echo.MIMEApplicationJSON,
+	echo.MIMEApplicationXML:      echo.MIMEApplicationXML,
+	echo.MIMETextXML:             echo.MIMEApplicationXML,
+	echo.MIMEApplicationMsgpack:  echo.MIMEApplicationMsgpack,
+	"application/x-msgpack":      echo.MIMEApplicationMsgpack,
+	"application/vnd.msgpack":    echo.MIMEApplicationMsgpack,
+	echo.MIMEApplicationProtobuf: echo.MIMEApplicationProtobuf,
+	"application/x-protobuf":     echo.MIMEApplicationProtobuf,
+}
+
+type acceptedMediaType struct {
+	mediaType string
+	quality   float64
+}
+
+var (
+	v1MediaTypes = []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML,
+		echo.MIMEApplicationMsgpack, echo.MIMEApplicationProtobuf}
+	// history and diff have no protobuf message
+	v1ReportMediaTypes = []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML,
+		echo.MIMEApplicationMsgpack}
+	v2MediaTypes = []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML,
+		echo.MIMEApplicationMsgpack}
+	webhookMediaTypes = []string{echo.MIMEApplicationJSON}
+)
+
+type protoMapper interface {
+	toProto() proto.Message
+}
+
+func contentNegotiation(mediaTypes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echoCtx echo.Context) error {
+			mediaType, ok := negotiate(echoCtx.Request().Header.Get(echo.HeaderAccept), mediaTypes...)
+			if !ok {
+				return echo.NewHTTPError(http.StatusNotAcceptable)
+			}
+			if !isSupportedContentType(echoCtx.Request(), mediaTypes...) {
+				return echo.ErrUnsupportedMediaType
+			}
+			echoCtx.Set(mediaTypeKey, mediaType)
+			return next(echoCtx)
+		}
+	}
+}
+
+func negotiate(accept string, mediaTypes ...string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return echo.MIMEApplicationJSON, true
+	}
+
+	var accepted []acceptedMediaType
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			accepted = append(accepted, acceptedMediaType{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+
+	for _, candidate := range accepted {
+		switch candidate.mediaType {
+		case "*/*", "application/*":
+			return echo.MIMEApplicationJSON, true
+		case "text/*":
+			return echo.MIMEApplicationXML, true
+		}
+		if mediaType, ok := mediaTypeAliases[candidate.mediaType]; ok &&
+			containsMediaType(mediaTypes, mediaType) {
+			return mediaType, true
+		}
+	}
+	return "", false
+}
+
+func isSupportedContentType(req *http.Request, mediaTypes ...string) bool {
+	contentType := req.Header.Get(echo.HeaderContentType)
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	alias, ok := mediaTypeAliases[mediaType]
+	return ok && containsMediaType(mediaTypes, alias)
+}
+
+func containsMediaType(mediaTypes []string, mediaType string) bool {
+	for _, candidate := range mediaTypes {
+		if candidate == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func bind(echoCtx echo.Context, target interface{}) error {
+	contentType := echoCtx.Request().Header.Get(echo.HeaderContentType)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaTypeAliases[mediaType] {
+	case echo.MIMEApplicationMsgpack:
+		decoder := msgpack.NewDecoder(echoCtx.Request().Body)
+		decoder.SetCustomStructTag("json")
+		if err := decoder.Decode(target); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return nil
+	case echo.MIMEApplicationProtobuf:
+		inputDTO, ok := target.(*usecase.ProductInputDTO)
+		if !ok {
+			return echo.ErrUnsupportedMediaType
+		}
+		body, err := io.ReadAll(echoCtx.Request().Body)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		var input pb.ProductInput
+		if err := proto.Unmarshal(body, &input); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		*inputDTO = usecase.ProductInputDTO{
+			Title:        input.GetTitle(),
+			Description:  input.GetDescription(),
+			Code:         input.GetCode(),
+			Reference:    input.GetReference(),
+			PriceInCents: input.GetPriceInCents(),
+		}
+		return nil
+	}
+	return echoCtx.Bind(target)
+}
+
+func respond(echoCtx echo.Context, code int, payload interface{}) error {
+	mediaType, _ := echoCtx.Get(mediaTypeKey).(string)
+
+	switch mediaType {
+	case echo.MIMEApplicationXML:
+		return echoCtx.XML(code, payload)
+	case echo.MIMEApplicationMsgpack:
+		var body bytes.Buffer
+		encoder := msgpack.NewEncoder(&body)
+		encoder.SetCustomStructTag("json")
+		if err := encoder.Encode(payload); err != nil {
+			return err
+		}
+		return echoCtx.Blob(code, echo.MIMEApplicationMsgpack, body.Bytes())
+	case echo.MIMEApplicationProtobuf:
+		mapper, ok := payload.(protoMapper)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotAcceptable)
+		}
+		body, err := proto.Marshal(mapper.toProto())
+		if err != nil {
+			return err
+		}
+		return echoCtx.Blob(code, echo.MIMEApplicationProtobuf, body)
+	}
+	return echoCtx.JSON(code, payload)
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"bytes"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/adapter/rpc/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+func newNegotiationServer() *echo.Echo {
+	ws := NewWebServer("8080", newProductRepositoryWith("XSZ-000741"))
+	echoInstance := echo.New()
+	grApi := echoInstance.Group("/api/v1", contentNegotiation(v1MediaTypes...))
+	grApi.POST("/products", ws.handleProductCreate)
+	grApi.GET("/products/:code", ws.handleProductGet)
+	return echoInstance
+}
+
+func TestNegotiate(t *testing.T) {
+	t.Run("Should default to json when accept is empty", negotiateDefault)
+	t.Run("Should pick the supported media type with highest quality", negotiateQuality)
+	t.Run("Should results false if no media type is supported", negotiateUnsupported)
+	t.Run("Should ignore media types not offered by the route", negotiateNotOffered)
+}
+
+func negotiateDefault(t *testing.T) {
+	mediaType, ok := negotiate("", v1MediaTypes...)
+	assert.True(t, ok)
+	assert.Equal(t, echo.MIMEApplicationJSON, mediaType)
+
+	mediaType, ok = negotiate("*/*", v1MediaTypes...)
+	assert.True(t, ok)
+	assert.Equal(t, echo.MIMEApplicationJSON, mediaType)
+}
+
+func negotiateQuality(t *testing.T) {
+	mediaType, ok := negotiate("application/json;q=0.5, application/x-msgpack;q=0.9, text/html",
+		v1MediaTypes...)
+	assert.True(t, ok)
+	assert.Equal(t, echo.MIMEApplicationMsgpack, mediaType)
+}
+
+func negotiateUnsupported(t *testing.T) {
+	_, ok := negotiate("text/html, application/json;q=0", v1MediaTypes...)
+	assert.False(t, ok)
+}
+
+func negotiateNotOffered(t *testing.T) {
+	_, ok := negotiate(echo.MIMEApplicationProtobuf, v2MediaTypes...)
+	assert.False(t, ok)
+}
+
+func TestWebServer_contentNegotiation(t *testing.T) {
+	t.Run("Should create a product from xml and respond xml", createProductXML)
+	t.Run("Should create a product from msgpack and respond msgpack", createProductMsgpack)
+	t.Run("Should create a product from protobuf and respond protobuf", createProductProtobuf)
+	t.Run("Should get a product as xml", getProductXML)
+	t.Run("Should results not acceptable if accept is unsupported", getProductNotAcceptable)
+	t.Run("Should results unsupported media type if content type is unsupported", createProductUnsupportedMediaType)
+	t.Run("Should results not acceptable if the route has no protobuf representation", historyProtobufNotAcceptable)
+	t.Run("Should reject unknown content types before the handler runs", createProductFormRejected)
+}
+
+func createProductXML(t *testing.T) {
+	body := `<product><title>Toy</title><description>Description</description>` +
+		`<code>XXCC</code><reference>XZsdf5tY-AA</reference><priceInCents>2500</priceInCents></product>`
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader([]byte(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationXML)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationXML)
+	newNegotiationServer().ServeHTTP(rec, req)
+
+	var outputDTO ProductCreateResponseV1
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationXML)
+	assert.Contains(t, rec.Body.String(), "<product>")
+	assert.Equal(t, "XZsdf5tY-AA", outputDTO.Reference)
+}
+
+func createProductMsgpack(t *testing.T) {
+	body, err := msgpack.Marshal(map[string]interface{}{
+		"title":        "Toy",
+		"description":  "Description",
+		"code":         "XXCC",
+		"reference":    "XZsdf5tY-AA",
+		"priceInCents": 2500,
+	})
+	assert.NoError(t, err)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationMsgpack)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationMsgpack)
+	newNegotiationServer().ServeHTTP(rec, req)
+
+	var outputDTO map[string]interface{}
+	assert.NoError(t, msgpack.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, echo.MIMEApplicationMsgpack, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "XZsdf5tY-AA", outputDTO["reference"])
+}
+
+func createProductProtobuf(t *testing.T) {
+	body, err := proto.Marshal(&pb.ProductInput{
+		Title:        "Toy",
+		Description:  "Description",
+		Code:         "XXCC",
+		Reference:    "XZsdf5tY-AA",
+		PriceInCents: 2500,
+	})
+	assert.NoError(t, err)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, "application/x-protobuf")
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationProtobuf)
+	newNegotiationServer().ServeHTTP(rec, req)
+
+	var output pb.CreateProductResponse
+	assert.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &output))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "XZsdf5tY-AA", output.GetReference())
+	assert.Greater(t, output.GetId(), int64(0))
+}
+
+func getProductXML(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741", nil)
+	req.Header.Set(echo.HeaderAccept, "text/xml")
+	newNegotiationServer().ServeHTTP(rec, req)
+
+	var outputDTO ProductResponseV1
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "XSZ-000741", outputDTO.Code)
+}
+
+func getProductNotAcceptable(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741", nil)
+	req.Header.Set(echo.HeaderAccept, "text/html")
+	newNegotiationServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+}
+
+func createProductUnsupportedMediaType(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader([]byte("title=Toy")))
+	req.Header.Set(echo.HeaderContentType, "text/plain")
+	newNegotiationServer().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func historyProtobufNotAcceptable(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741/history", nil)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationProtobuf)
+	NewWebServer("8080", newProductRepositoryWith("XSZ-000741")).router().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+}
+
+func createProductFormRejected(t *testing.T) {
+	echoInstance := NewWebServer("8080", newProductRepositoryWith("XSZ-000741")).router()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products",
+		strings.NewReader("title=Toy&description=Description&code=XXFF&reference=XZsdf5tY-AA&priceInCents=2500"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/products/XXFF", nil)
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+const (
+	ReadSessionCookie = "read-primary-until"
+)
+
+// readSession restores the client's read-your-writes pin from a cookie and sends it back when
+// a write in this request extended it.
+func readSession() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(echoCtx echo.Context) error {
+			var primaryUntil time.Time
+			if cookie, err := echoCtx.Cookie(ReadSessionCookie); err == nil {
+				if millis, err := strconv.ParseInt(cookie.Value, 10, 64); err == nil {
+					primaryUntil = time.UnixMilli(millis)
+				}
+			}
+			session := usecase.NewReadSession(primaryUntil)
+			request := echoCtx.Request()
+			echoCtx.SetRequest(request.WithContext(usecase.WithReadSession(request.Context(), session)))
+			echoCtx.Response().Before(func() {
+				until := session.PrimaryUntil()
+				if !until.After(primaryUntil) {
+					return
+				}
+				echoCtx.SetCookie(&http.Cookie{
+					Name:     ReadSessionCookie,
+					Value:    strconv.FormatInt(until.UnixMilli(), 10),
+					Path:     "/",
+					Expires:  until,
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			})
+			return next(echoCtx)
+		}
+	}
+}
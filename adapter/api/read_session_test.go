@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSession(t *testing.T) {
+	t.Run("Should issue a cookie when a write pins the client", readSessionIssueCookie)
+	t.Run("Should restore the pin from the cookie", readSessionRestoreCookie)
+}
+
+func newReadSessionEcho(handler echo.HandlerFunc) *echo.Echo {
+	echoInstance := echo.New()
+	echoInstance.Use(readSession())
+	echoInstance.GET("/", handler)
+	return echoInstance
+}
+
+func readSessionIssueCookie(t *testing.T) {
+	until := time.Now().Add(time.Second)
+	echoInstance := newReadSessionEcho(func(echoCtx echo.Context) error {
+		session, ok := usecase.ReadSessionFromContext(echoCtx.Request().Context())
+		assert.True(t, ok)
+		session.PinPrimary(until)
+		return echoCtx.NoContent(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	echoInstance.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	cookies := rec.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, ReadSessionCookie, cookies[0].Name)
+	assert.Equal(t, strconv.FormatInt(until.UnixMilli(), 10), cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+}
+
+func readSessionRestoreCookie(t *testing.T) {
+	until := time.Now().Add(time.Second)
+	var restored time.Time
+	echoInstance := newReadSessionEcho(func(echoCtx echo.Context) error {
+		session, _ := usecase.ReadSessionFromContext(echoCtx.Request().Context())
+		restored = session.PrimaryUntil()
+		return echoCtx.NoContent(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: ReadSessionCookie, Value: strconv.FormatInt(until.UnixMilli(), 10)})
+	echoInstance.ServeHTTP(rec, req)
+
+	assert.Equal(t, until.UnixMilli(), restored.UnixMilli())
+	assert.Empty(t, rec.Result().Cookies())
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	coreRepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRevisionServer(revisionRepo coreRepository.ProductRevisionRepository) *echo.Echo {
+	productRepo := repository.NewProductRepositoryInMemory(repository.WithRevisions(revisionRepo))
+	productRepo.Insert(context.TODO(), coreRepository.ProductRepositoryInput{
+		Title:        "Toy",
+		Description:  "Description",
+		Code:         "XSZ-000741",
+		Reference:    "RF009-pods74",
+		PriceInCents: int64(2500),
+	})
+	ws := NewWebServer("8080", productRepo, usecase.WithRevisionRepository(revisionRepo))
+	echoInstance := echo.New()
+	grApi := echoInstance.Group("/api/v1", contentNegotiation(v1MediaTypes...))
+	grApi.POST("/products/:code/revisions/:revision", ws.handleProductRevert)
+	grApi.GET("/products/:code/diff", ws.handleProductDiff)
+	return echoInstance
+}
+
+func TestWebServer_handleProductRevert(t *testing.T) {
+	t.Run("Should revert a product to a revision", revertProductSuccess)
+	t.Run("Should results error if revision is not a number", revertProductInvalidRevisionErr)
+	t.Run("Should results error if revision does not exists", revertProductNotFoundErr)
+}
+
+func revertProductSuccess(t *testing.T) {
+	revisionRepo := repository.NewProductRevisionRepositoryInMemory()
+	echoInstance := newRevisionServer(revisionRepo)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/XSZ-000741/revisions/1:revert", nil)
+	echoInstance.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	revisionData, err := revisionRepo.GetByRevision(context.TODO(), 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "XSZ-000741", revisionData.ProductCode)
+	assert.Equal(t, int64(2500), revisionData.Product.PriceInCents)
+}
+
+func revertProductInvalidRevisionErr(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/XSZ-000741/revisions/one:revert", nil)
+	newRevisionServer(repository.NewProductRevisionRepositoryInMemory()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func revertProductNotFoundErr(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/XSZ-000741/revisions/9:revert", nil)
+	newRevisionServer(repository.NewProductRevisionRepositoryInMemory()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWebServer_handleProductDiff(t *testing.T) {
+	t.Run("Should diff two revisions", diffProductSuccess)
+	t.Run("Should results error if revisions are missing", diffProductInvalidRevisionErr)
+}
+
+func diffProductSuccess(t *testing.T) {
+	revisionRepo := repository.NewProductRevisionRepositoryInMemory()
+	echoInstance := newRevisionServer(revisionRepo)
+	_, err := revisionRepo.Insert(context.TODO(), coreRepository.ProductRevisionData{
+		ProductID:   1,
+		ProductCode: "XSZ-000741",
+		Product: coreRepository.ProductRepositoryData{
+			ID:           1,
+			Title:        "Toy",
+			Code:         "XSZ-000741",
+			Description:  "Description",
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(3000),
+		},
+	})
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741/diff?from=1&to=2", nil)
+	echoInstance.ServeHTTP(rec, req)
+
+	var outputDTO ProductDiffResponseV1
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []ProductFieldChangeResponseV1{
+		{Field: "priceInCents", Old: float64(2500), New: float64(3000)},
+	}, outputDTO.Changes)
+	assert.Equal(t, []ProductDiffLineResponseV1{{Operation: "equal", Text: "Description"}},
+		outputDTO.DescriptionDiff)
+}
+
+func diffProductInvalidRevisionErr(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/XSZ-000741/diff?from=1", nil)
+	newRevisionServer(repository.NewProductRevisionRepositoryInMemory()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
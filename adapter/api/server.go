@@ -3,35 +3,109 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/adapter/graph"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
 	"github.com/lbsti/eulabs-challenge/internal/core/repository"
 	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
 )
 
 type WebServer struct {
-	productRepo repository.ProductRepository
-	port        string
+	productRepo      repository.ProductRepository
+	port             string
+	options          []usecase.ProductOption
+	webhookRepo      repository.WebhookRepository
+	deliveryRepo     repository.WebhookDeliveryRepository
+	webhookDeliverer usecase.WebhookDeliverer
+	webhookOptions   []usecase.WebhookOption
+	eventLog         usecase.ProductEventLog
+	eventHeartbeat   time.Duration
 }
 
-func NewWebServer(port string, productRepo repository.ProductRepository) *WebServer {
-	return &WebServer{productRepo: productRepo, port: port}
+func NewWebServer(port string, productRepo repository.ProductRepository,
+	opts ...usecase.ProductOption) *WebServer {
+	return &WebServer{productRepo: productRepo, port: port, options: opts}
+}
+
+func (ws *WebServer) EnableWebhooks(webhookRepo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository, deliverer usecase.WebhookDeliverer,
+	opts ...usecase.WebhookOption) {
+	ws.webhookRepo = webhookRepo
+	ws.deliveryRepo = deliveryRepo
+	ws.webhookDeliverer = deliverer
+	ws.webhookOptions = opts
+}
+
+func (ws *WebServer) EnableProductEvents(eventLog usecase.ProductEventLog, heartbeat time.Duration) {
+	ws.eventLog = eventLog
+	ws.eventHeartbeat = heartbeat
+	if heartbeat <= 0 {
+		ws.eventHeartbeat = defaultEventHeartbeat
+	}
 }
 
 func (ws WebServer) Run() {
+	echoInstance := ws.router()
+	echoInstance.Logger.Fatal(echoInstance.Start(fmt.Sprintf(":%s", ws.port)))
+}
+
+func (ws WebServer) router() *echo.Echo {
 	echoInstance := echo.New()
+	echoInstance.Use(actor(), readSession())
 	productGroup := echoInstance.Group("/api")
-	productGroup.POST("/v1/products", ws.handleProductCreate)
-	productGroup.GET("/v1/products/:code", ws.handleProductGet)
-	productGroup.DELETE("/v1/products/:code", ws.handleProductDelete)
-	productGroup.PATCH("/v1/products", ws.handleProductUpdate)
-	echoInstance.Logger.Fatal(echoInstance.Start(fmt.Sprintf(":%s", ws.port)))
+
+	if ws.eventLog != nil {
+		productGroup.GET("/v1/products/events", ws.handleProductEvents)
+	}
+
+	v1Group := productGroup.Group("/v1")
+	v1Negotiation := contentNegotiation(v1MediaTypes...)
+	v1ReportNegotiation := contentNegotiation(v1ReportMediaTypes...)
+	v1Group.POST("/products", ws.handleProductCreate, v1Negotiation)
+	v1Group.GET("/products", ws.handleProductList, v1Negotiation)
+	v1Group.GET("/products/trash", ws.handleProductTrash, v1Negotiation)
+	v1Group.POST("/products/:code", ws.handleProductRestore, v1Negotiation)
+	v1Group.GET("/products/:code", ws.handleProductGet, v1Negotiation)
+	v1Group.GET("/products/:code/history", ws.handleProductHistory, v1ReportNegotiation)
+	v1Group.GET("/products/:code/diff", ws.handleProductDiff, v1ReportNegotiation)
+	v1Group.POST("/products/:code/revisions/:revision", ws.handleProductRevert, v1Negotiation)
+	v1Group.DELETE("/products/:code", ws.handleProductDelete, v1Negotiation)
+	v1Group.PATCH("/products", ws.handleProductUpdate, v1Negotiation)
+
+	if ws.webhookRepo != nil {
+		webhookGroup := productGroup.Group("/v1/webhooks", contentNegotiation(webhookMediaTypes...))
+		webhookGroup.POST("", ws.handleWebhookCreate)
+		webhookGroup.GET("", ws.handleWebhookList)
+		webhookGroup.GET("/:id", ws.handleWebhookGet)
+		webhookGroup.PUT("/:id", ws.handleWebhookUpdate)
+		webhookGroup.DELETE("/:id", ws.handleWebhookDelete)
+		webhookGroup.GET("/deliveries/dead", ws.handleWebhookDeadLetters)
+		webhookGroup.POST("/deliveries/:id", ws.handleWebhookRedeliver)
+	}
+
+	v2Group := productGroup.Group("/v2", contentNegotiation(v2MediaTypes...))
+	v2Group.POST("/products", ws.handleProductCreateV2)
+	v2Group.GET("/products", ws.handleProductListV2)
+	v2Group.GET("/products/:code", ws.handleProductGetV2)
+	v2Group.DELETE("/products/:code", ws.handleProductDeleteV2)
+	v2Group.PATCH("/products/:code", ws.handleProductUpdateV2)
+
+	graphHandler, err := graph.NewHandler(ws.productRepo, ws.options...)
+	if err != nil {
+		echoInstance.Logger.Fatal(err)
+	}
+	echoInstance.GET("/graphql", echo.WrapHandler(graphHandler))
+	echoInstance.POST("/graphql", echo.WrapHandler(graphHandler))
+	return echoInstance
 }
 
 func (ws WebServer) handleProductCreate(echoCtx echo.Context) error {
-	productCreate := usecase.NewProductCreate(ws.productRepo)
+	productCreate := usecase.NewProductCreate(ws.productRepo, ws.options...)
 	var inputDTO usecase.ProductInputDTO
-	if err := echoCtx.Bind(&inputDTO); err != nil {
+	if err := bind(echoCtx, &inputDTO); err != nil {
 		return err
 	}
 	ctx := echoCtx.Request().Context()
@@ -41,25 +115,56 @@ func (ws WebServer) handleProductCreate(echoCtx echo.Context) error {
 		wrappedErr := Mapping(err)
 		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
 	}
-	return echoCtx.JSON(http.StatusCreated, outputDTO)
+	return respond(echoCtx, http.StatusCreated, toProductCreateResponseV1(outputDTO))
 }
 
 func (ws WebServer) handleProductGet(echoCtx echo.Context) error {
 	code := echoCtx.Param("code")
-	productGet := usecase.NewProductGet(ws.productRepo)
+	fields, repositoryFields, err := parseFields(echoCtx.QueryParam("fields"), v1Fields)
+	if err != nil {
+		return echo.NewHTTPError(Mapping(err).Code, err.Error())
+	}
+	productGet := usecase.NewProductGet(ws.productRepo, ws.options...)
 	ctx := echoCtx.Request().Context()
-	outputDTO, err := productGet.Execute(ctx, code)
+	outputDTO, err := productGet.ExecuteWithFields(ctx, code, repositoryFields)
 	if err != nil {
 		code := Mapping(err).Code
 		wrappedErr := Mapping(err)
 		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
 	}
-	return echoCtx.JSON(http.StatusOK, outputDTO)
+	return respond(echoCtx, http.StatusOK, project(toProductResponseV1(outputDTO), fields))
+}
+
+func (ws WebServer) handleProductList(echoCtx echo.Context) error {
+	fields, repositoryFields, err := parseFields(echoCtx.QueryParam("fields"), v1Fields)
+	if err != nil {
+		return echo.NewHTTPError(Mapping(err).Code, err.Error())
+	}
+	inputDTO := usecase.ProductListInputDTO{Fields: repositoryFields}
+	if err := echo.QueryParamsBinder(echoCtx).
+		String("code", &inputDTO.Code).
+		String("title", &inputDTO.Title).
+		Int64("minPriceInCents", &inputDTO.MinPriceInCents).
+		Int64("maxPriceInCents", &inputDTO.MaxPriceInCents).
+		Int("page", &inputDTO.Page).
+		Int("pageSize", &inputDTO.PageSize).
+		BindError(); err != nil {
+		return err
+	}
+	productList := usecase.NewProductList(ws.productRepo, ws.options...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productList.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toProductListResponseV1(outputDTO, fields))
 }
 
 func (ws WebServer) handleProductDelete(echoCtx echo.Context) error {
 	code := echoCtx.Param("code")
-	productDelete := usecase.NewProductDelete(ws.productRepo)
+	productDelete := usecase.NewProductDelete(ws.productRepo, ws.options...)
 	ctx := echoCtx.Request().Context()
 	_, err := productDelete.Execute(ctx, code)
 	if err != nil {
@@ -71,17 +176,112 @@ func (ws WebServer) handleProductDelete(echoCtx echo.Context) error {
 }
 
 func (ws WebServer) handleProductUpdate(echoCtx echo.Context) error {
-	productUpdate := usecase.NewProductUpdate(ws.productRepo)
+	productUpdate := usecase.NewProductUpdate(ws.productRepo, ws.options...)
 
 	var inputDTO usecase.ProductInputDTO
-	if err := echoCtx.Bind(&inputDTO); err != nil {
+	if err := bind(echoCtx, &inputDTO); err != nil {
 		return err
 	}
 	ctx := echoCtx.Request().Context()
-	if err := productUpdate.Execute(ctx, inputDTO); err != nil {
+	if _, err := productUpdate.Execute(ctx, inputDTO); err != nil {
 		code := Mapping(err).Code
 		wrappedErr := Mapping(err)
 		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
 	}
 	return echoCtx.NoContent(http.StatusOK)
 }
+
+func (ws WebServer) handleProductRestore(echoCtx echo.Context) error {
+	code, err := customMethod(echoCtx, "code", "restore")
+	if err != nil {
+		return err
+	}
+	productRestore := usecase.NewProductRestore(ws.productRepo, ws.options...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productRestore.Execute(ctx, code)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toProductResponseV1(outputDTO))
+}
+
+func (ws WebServer) handleProductTrash(echoCtx echo.Context) error {
+	var inputDTO usecase.ProductTrashInputDTO
+	if err := echo.QueryParamsBinder(echoCtx).
+		String("code", &inputDTO.Code).
+		Int("page", &inputDTO.Page).
+		Int("pageSize", &inputDTO.PageSize).
+		BindError(); err != nil {
+		return err
+	}
+	productTrash := usecase.NewProductTrash(ws.productRepo, ws.options...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productTrash.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toProductListResponseV1(outputDTO, nil))
+}
+
+func (ws WebServer) handleProductHistory(echoCtx echo.Context) error {
+	inputDTO := usecase.ProductHistoryInputDTO{Code: echoCtx.Param("code")}
+	if err := echo.QueryParamsBinder(echoCtx).
+		Int("page", &inputDTO.Page).
+		Int("pageSize", &inputDTO.PageSize).
+		BindError(); err != nil {
+		return err
+	}
+	productHistory := usecase.NewProductHistory(ws.options...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productHistory.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toProductHistoryResponseV1(outputDTO))
+}
+
+func (ws WebServer) handleProductRevert(echoCtx echo.Context) error {
+	code := echoCtx.Param("code")
+	rawRevision, err := customMethod(echoCtx, "revision", "revert")
+	if err != nil {
+		return err
+	}
+	revision, err := strconv.Atoi(rawRevision)
+	if err != nil {
+		return echo.NewHTTPError(Mapping(entity.InvalidRevisionErr).Code, entity.InvalidRevisionErr.Error())
+	}
+	productRevert := usecase.NewProductRevert(ws.productRepo, ws.options...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productRevert.Execute(ctx, code, revision)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toProductResponseV1(outputDTO))
+}
+
+func (ws WebServer) handleProductDiff(echoCtx echo.Context) error {
+	inputDTO := usecase.ProductDiffInputDTO{Code: echoCtx.Param("code")}
+	if err := echo.QueryParamsBinder(echoCtx).
+		MustInt("from", &inputDTO.From).
+		MustInt("to", &inputDTO.To).
+		BindError(); err != nil {
+		return echo.NewHTTPError(Mapping(entity.InvalidRevisionErr).Code, entity.InvalidRevisionErr.Error())
+	}
+	productDiff := usecase.NewProductDiff(ws.productRepo, ws.options...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productDiff.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toProductDiffResponseV1(outputDTO))
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+const (
+	LastEventIDHeader = "Last-Event-ID"
+
+	mimeTextEventStream   = "text/event-stream"
+	defaultEventHeartbeat = 15 * time.Second
+	resetEventType        = "reset"
+)
+
+func (ws WebServer) handleProductEvents(echoCtx echo.Context) error {
+	epoch, after, replay, err := lastEventID(echoCtx.Request().Header.Get(LastEventIDHeader))
+	if err != nil {
+		return echo.NewHTTPError(Mapping(err).Code, err.Error())
+	}
+	codePrefix := strings.ToLower(strings.ReplaceAll(echoCtx.QueryParam("code"), " ", ""))
+
+	backlog, records, unsubscribe, reset := ws.eventLog.Subscribe(epoch, after, replay)
+	defer unsubscribe()
+
+	response := echoCtx.Response()
+	response.Header().Set(echo.HeaderContentType, mimeTextEventStream)
+	response.Header().Set(echo.HeaderCacheControl, "no-cache")
+	response.Header().Set(echo.HeaderConnection, "keep-alive")
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(http.StatusOK)
+
+	if reset != nil {
+		if _, err := fmt.Fprintf(response, "id: %s\nevent: %s\ndata: {\"epoch\":%q,\"sequence\":%d}\n\n",
+			reset.ID(), resetEventType, reset.Epoch, reset.Sequence); err != nil {
+			return nil
+		}
+	}
+	for _, record := range backlog {
+		if err := writeProductEvent(response, record, codePrefix); err != nil {
+			return nil
+		}
+	}
+	response.Flush()
+
+	heartbeat := time.NewTicker(ws.eventHeartbeat)
+	defer heartbeat.Stop()
+	ctx := echoCtx.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := io.WriteString(response, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case record, ok := <-records:
+			if !ok {
+				return nil
+			}
+			if err := writeProductEvent(response, record, codePrefix); err != nil {
+				return nil
+			}
+		}
+		response.Flush()
+	}
+}
+
+func lastEventID(header string) (string, int64, bool, error) {
+	if header == "" {
+		return "", 0, false, nil
+	}
+	epoch, sequence := "", header
+	if index := strings.LastIndex(header, "-"); index > 0 {
+		epoch, sequence = header[:index], header[index+1:]
+	}
+	after, err := strconv.ParseInt(sequence, 10, 64)
+	if err != nil || after < 0 {
+		return "", 0, false, entity.InvalidLastEventIDErr
+	}
+	return epoch, after, true, nil
+}
+
+func writeProductEvent(w io.Writer, record usecase.ProductEventRecord, codePrefix string) error {
+	if !strings.HasPrefix(strings.ToLower(record.Event.Code), codePrefix) {
+		return nil
+	}
+	data, err := json.Marshal(record.Event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", record.ID(), record.Event.Type, data)
+	return err
+}
@@ -2,18 +2,35 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/labstack/echo/v4"
 	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	coreRepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
 	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
 	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
 	"github.com/stretchr/testify/assert"
 )
 
+func newProductRepositoryWith(codes ...string) coreRepository.ProductRepository {
+	productRepo := repository.NewProductRepositoryInMemory()
+	for _, code := range codes {
+		productRepo.Insert(context.TODO(), coreRepository.ProductRepositoryInput{
+			Title:        "Toy",
+			Description:  "Blahahhs",
+			Code:         code,
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(51400),
+		})
+	}
+	return productRepo
+}
+
 func TestWebServer_handleProductCreate(t *testing.T) {
 	t.Run("Should handle create product request with success", createProductSuccess)
 	t.Run("Should results error if create product request is duplicated", createProductDuplicatedErr)
@@ -124,10 +141,11 @@ func createProductEmptyCodeErr(t *testing.T) {
 func TestWebServer_handleProductGet(t *testing.T) {
 	t.Run("Should handle get product request with success", getProductSuccess)
 	t.Run("Should results error if product code does not exists", getProductNotFoundErr)
+	t.Run("Should results gateway timeout if the deadline is exceeded", getProductDeadlineExceededErr)
 }
 
 func getProductSuccess(t *testing.T) {
-	productInMemoryRepo := repository.NewProductRepositoryInMemory()
+	productInMemoryRepo := newProductRepositoryWith("XSZ-000741")
 	ws := NewWebServer("8080", productInMemoryRepo)
 
 	echoInstance := echo.New()
@@ -184,7 +202,7 @@ func TestWebServer_handleProductDelete(t *testing.T) {
 }
 
 func deleteProductSuccess(t *testing.T) {
-	productInMemoryRepo := repository.NewProductRepositoryInMemory()
+	productInMemoryRepo := newProductRepositoryWith("XSZ-000741")
 	ws := NewWebServer("8080", productInMemoryRepo)
 
 	echoInstance := echo.New()
@@ -237,7 +255,7 @@ func TestWebServer_handleProductUpdate(t *testing.T) {
 }
 
 func updateProductSuccess(t *testing.T) {
-	productInMemoryRepo := repository.NewProductRepositoryInMemory()
+	productInMemoryRepo := newProductRepositoryWith("XXCC")
 	ws := NewWebServer("8080", productInMemoryRepo)
 
 	echoInstance := echo.New()
@@ -312,3 +330,25 @@ func updateProductNotFoundErr(t *testing.T) {
 	assert.NotNil(t, err2)
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
+
+func getProductDeadlineExceededErr(t *testing.T) {
+	productInMemoryRepo := repository.ProductRepositoryInMemorySpy{
+		ExpectedError: fmt.Errorf("query product: %w", context.DeadlineExceeded),
+	}
+	ws := NewWebServer("8080", productInMemoryRepo)
+
+	echoInstance := echo.New()
+	rec := httptest.NewRecorder()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	echoCtx := echoInstance.NewContext(req, rec)
+	echoCtx.SetPath("products/:code")
+	echoCtx.SetParamNames("code")
+	echoCtx.SetParamValues("XSZ-000741")
+
+	err := ws.handleProductGet(echoCtx)
+	var httpErr *echo.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusGatewayTimeout, httpErr.Code)
+}
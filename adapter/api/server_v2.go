@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+func (ws WebServer) handleProductCreateV2(echoCtx echo.Context) error {
+	productCreate := usecase.NewProductCreate(ws.productRepo, ws.options...)
+	var request ProductRequestV2
+	if err := bind(echoCtx, &request); err != nil {
+		return err
+	}
+	inputDTO, err := fromProductRequestV2(request)
+	if err != nil {
+		return echo.NewHTTPError(Mapping(err).Code, err.Error())
+	}
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productCreate.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	echoCtx.Response().Header().Set(echo.HeaderLocation, "/api/v2/products/"+outputDTO.Code)
+	return respond(echoCtx, http.StatusCreated, toProductResponseV2(outputDTO))
+}
+
+func (ws WebServer) handleProductGetV2(echoCtx echo.Context) error {
+	code := echoCtx.Param("code")
+	fields, repositoryFields, err := parseFields(echoCtx.QueryParam("fields"), v2Fields)
+	if err != nil {
+		return echo.NewHTTPError(Mapping(err).Code, err.Error())
+	}
+	productGet := usecase.NewProductGet(ws.productRepo, ws.options...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productGet.ExecuteWithFields(ctx, code, repositoryFields)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, project(toProductResponseV2(outputDTO), fields))
+}
+
+func (ws WebServer) handleProductListV2(echoCtx echo.Context) error {
+	fields, repositoryFields, err := parseFields(echoCtx.QueryParam("fields"), v2Fields)
+	if err != nil {
+		return echo.NewHTTPError(Mapping(err).Code, err.Error())
+	}
+	inputDTO := usecase.ProductListInputDTO{Fields: repositoryFields}
+	if err := echo.QueryParamsBinder(echoCtx).
+		String("code", &inputDTO.Code).
+		String("title", &inputDTO.Title).
+		Int64("minPrice", &inputDTO.MinPriceInCents).
+		Int64("maxPrice", &inputDTO.MaxPriceInCents).
+		Int("page", &inputDTO.Page).
+		Int("pageSize", &inputDTO.PageSize).
+		BindError(); err != nil {
+		return err
+	}
+	productList := usecase.NewProductList(ws.productRepo, ws.options...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productList.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toProductListResponseV2(outputDTO, fields))
+}
+
+func (ws WebServer) handleProductDeleteV2(echoCtx echo.Context) error {
+	code := echoCtx.Param("code")
+	productDelete := usecase.NewProductDelete(ws.productRepo, ws.options...)
+	ctx := echoCtx.Request().Context()
+	if _, err := productDelete.Execute(ctx, code); err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return echoCtx.NoContent(http.StatusNoContent)
+}
+
+func (ws WebServer) handleProductUpdateV2(echoCtx echo.Context) error {
+	productUpdate := usecase.NewProductUpdate(ws.productRepo, ws.options...)
+	var request ProductRequestV2
+	if err := bind(echoCtx, &request); err != nil {
+		return err
+	}
+	request.Code = echoCtx.Param("code")
+	inputDTO, err := fromProductRequestV2(request)
+	if err != nil {
+		return echo.NewHTTPError(Mapping(err).Code, err.Error())
+	}
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := productUpdate.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toProductResponseV2(outputDTO))
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	infrarepository "github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newV2Server(productRepo repository.ProductRepository) *echo.Echo {
+	ws := NewWebServer("8080", productRepo)
+	echoInstance := echo.New()
+	grApi := echoInstance.Group("/api/v2", contentNegotiation(v2MediaTypes...))
+	grApi.POST("/products", ws.handleProductCreateV2)
+	grApi.GET("/products/:code", ws.handleProductGetV2)
+	grApi.DELETE("/products/:code", ws.handleProductDeleteV2)
+	grApi.PATCH("/products/:code", ws.handleProductUpdateV2)
+	return echoInstance
+}
+
+func productRequestV2Body(t *testing.T, currency string) []byte {
+	body, err := json.Marshal(ProductRequestV2{
+		Title:       "Toy",
+		Description: "Description",
+		Code:        "XXCC",
+		Reference:   "XZsdf5tY-AA",
+		Price:       MoneyV2{Amount: 2500, Currency: currency},
+	})
+	assert.NoError(t, err)
+	return body
+}
+
+func TestWebServer_handleProductCreateV2(t *testing.T) {
+	t.Run("Should create a product and return the full representation", createProductV2Success)
+	t.Run("Should results error if currency is not supported", createProductV2CurrencyErr)
+	t.Run("Should results not acceptable if protobuf is requested", createProductV2NotAcceptable)
+}
+
+func createProductV2Success(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/products",
+		bytes.NewReader(productRequestV2Body(t, "brl")))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	newV2Server(infrarepository.NewProductRepositoryInMemory()).ServeHTTP(rec, req)
+
+	var response ProductResponseV2
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "/api/v2/products/XXCC", rec.Header().Get(echo.HeaderLocation))
+	assert.Equal(t, "XXCC", response.Code)
+	assert.Equal(t, "Toy", response.Title)
+	assert.Equal(t, MoneyV2{Amount: 2500, Currency: DefaultCurrency}, response.Price)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`, response.CreatedAt)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`, response.UpdatedAt)
+}
+
+func createProductV2CurrencyErr(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/products",
+		bytes.NewReader(productRequestV2Body(t, "USD")))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	newV2Server(infrarepository.NewProductRepositoryInMemory()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "{\"message\":\"currency is not supported\"}\n", rec.Body.String())
+}
+
+func createProductV2NotAcceptable(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/products",
+		bytes.NewReader(productRequestV2Body(t, "BRL")))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationProtobuf)
+	newV2Server(infrarepository.NewProductRepositoryInMemory()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+}
+
+func TestWebServer_handleProductGetV2(t *testing.T) {
+	t.Run("Should get a product with money and RFC 3339 timestamps", getProductV2Success)
+	t.Run("Should results error if product code does not exists", getProductV2NotFoundErr)
+}
+
+func getProductV2Success(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedData: repository.ProductRepositoryData{
+			ID:           7,
+			Code:         "XSZ-000741",
+			PriceInCents: 51400,
+			CreatedAt:    "2023-12-15 11:19:08",
+			UpdatedAt:    "2023-12-16T08:00:00-03:00",
+		},
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/products/XSZ-000741", nil)
+	newV2Server(productRepo).ServeHTTP(rec, req)
+
+	var response ProductResponseV2
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(7), response.ID)
+	assert.Equal(t, MoneyV2{Amount: 51400, Currency: DefaultCurrency}, response.Price)
+	assert.Equal(t, "2023-12-15T11:19:08Z", response.CreatedAt)
+	assert.Equal(t, "2023-12-16T11:00:00Z", response.UpdatedAt)
+}
+
+func getProductV2NotFoundErr(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedError: entity.ProductNotFoundErr,
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/products/XSZ-000741", nil)
+	newV2Server(productRepo).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWebServer_handleProductUpdateV2(t *testing.T) {
+	t.Run("Should update a product and return the full representation", updateProductV2Success)
+	t.Run("Should results error if product code does not exists", updateProductV2NotFoundErr)
+}
+
+func updateProductV2Success(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/api/v2/products/XSZ-000741",
+		bytes.NewReader(productRequestV2Body(t, "BRL")))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	newV2Server(newProductRepositoryWith("XSZ-000741")).ServeHTTP(rec, req)
+
+	var response ProductResponseV2
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "XSZ-000741", response.Code)
+	assert.Equal(t, DefaultCurrency, response.Price.Currency)
+}
+
+func updateProductV2NotFoundErr(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedError: entity.ProductNotFoundErr,
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/api/v2/products/XSZ-000741",
+		bytes.NewReader(productRequestV2Body(t, "BRL")))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	newV2Server(productRepo).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWebServer_handleProductDeleteV2(t *testing.T) {
+	t.Run("Should delete a product with no content", deleteProductV2Success)
+}
+
+func deleteProductV2Success(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v2/products/XSZ-000741", nil)
+	newV2Server(newProductRepositoryWith("XSZ-000741")).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
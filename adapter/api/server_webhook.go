@@ -0,0 +1,136 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+func (ws WebServer) handleWebhookCreate(echoCtx echo.Context) error {
+	var inputDTO usecase.WebhookInputDTO
+	if err := bind(echoCtx, &inputDTO); err != nil {
+		return err
+	}
+	webhookCreate := usecase.NewWebhookCreate(ws.webhookRepo, ws.webhookOptions...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := webhookCreate.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusCreated, toWebhookResponseV1(outputDTO))
+}
+
+func (ws WebServer) handleWebhookList(echoCtx echo.Context) error {
+	webhookGet := usecase.NewWebhookGet(ws.webhookRepo)
+	ctx := echoCtx.Request().Context()
+	outputDTOs, err := webhookGet.ExecuteAll(ctx)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toWebhookListResponseV1(outputDTOs))
+}
+
+func (ws WebServer) handleWebhookGet(echoCtx echo.Context) error {
+	id, err := webhookID(echoCtx.Param("id"), entity.WebhookNotFoundErr)
+	if err != nil {
+		return err
+	}
+	webhookGet := usecase.NewWebhookGet(ws.webhookRepo)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := webhookGet.Execute(ctx, id)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toWebhookResponseV1(outputDTO))
+}
+
+func (ws WebServer) handleWebhookUpdate(echoCtx echo.Context) error {
+	id, err := webhookID(echoCtx.Param("id"), entity.WebhookNotFoundErr)
+	if err != nil {
+		return err
+	}
+	var inputDTO usecase.WebhookInputDTO
+	if err := bind(echoCtx, &inputDTO); err != nil {
+		return err
+	}
+	webhookUpdate := usecase.NewWebhookUpdate(ws.webhookRepo, ws.webhookOptions...)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := webhookUpdate.Execute(ctx, id, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toWebhookResponseV1(outputDTO))
+}
+
+func (ws WebServer) handleWebhookDelete(echoCtx echo.Context) error {
+	id, err := webhookID(echoCtx.Param("id"), entity.WebhookNotFoundErr)
+	if err != nil {
+		return err
+	}
+	webhookDelete := usecase.NewWebhookDelete(ws.webhookRepo)
+	ctx := echoCtx.Request().Context()
+	if err := webhookDelete.Execute(ctx, id); err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return echoCtx.NoContent(http.StatusNoContent)
+}
+
+func (ws WebServer) handleWebhookDeadLetters(echoCtx echo.Context) error {
+	var inputDTO usecase.WebhookDeliveryListInputDTO
+	if err := echo.QueryParamsBinder(echoCtx).
+		Int("page", &inputDTO.Page).
+		Int("pageSize", &inputDTO.PageSize).
+		BindError(); err != nil {
+		return err
+	}
+	deliveryList := usecase.NewWebhookDeliveryList(ws.deliveryRepo)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := deliveryList.Execute(ctx, inputDTO)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toWebhookDeliveryListResponseV1(outputDTO))
+}
+
+func (ws WebServer) handleWebhookRedeliver(echoCtx echo.Context) error {
+	rawID, err := customMethod(echoCtx, "id", "redeliver")
+	if err != nil {
+		return err
+	}
+	id, err := webhookID(rawID, entity.DeliveryNotFoundErr)
+	if err != nil {
+		return err
+	}
+	webhookRedeliver := usecase.NewWebhookRedeliver(ws.deliveryRepo, ws.webhookDeliverer)
+	ctx := echoCtx.Request().Context()
+	outputDTO, err := webhookRedeliver.Execute(ctx, id)
+	if err != nil {
+		code := Mapping(err).Code
+		wrappedErr := Mapping(err)
+		return echo.NewHTTPError(code, wrappedErr.ResultErr.Error())
+	}
+	return respond(echoCtx, http.StatusOK, toWebhookDeliveryResponseV1(outputDTO))
+}
+
+func webhookID(raw string, notFoundErr error) (int64, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, echo.NewHTTPError(Mapping(notFoundErr).Code, notFoundErr.Error())
+	}
+	return id, nil
+}
@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	infrarepository "github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type restoreCodeSpy struct {
+	infrarepository.ProductRepositoryInMemorySpy
+	codes *[]string
+}
+
+func (spyRepo restoreCodeSpy) RestoreByCode(ctx context.Context, code string) error {
+	*spyRepo.codes = append(*spyRepo.codes, code)
+	return spyRepo.ProductRepositoryInMemorySpy.RestoreByCode(ctx, code)
+}
+
+func newTrashServer(productRepo repository.ProductRepository) *echo.Echo {
+	ws := NewWebServer("8080", productRepo)
+	echoInstance := echo.New()
+	grApi := echoInstance.Group("/api/v1", contentNegotiation(v1MediaTypes...))
+	grApi.GET("/products/trash", ws.handleProductTrash)
+	grApi.GET("/products/:code", ws.handleProductGet)
+	grApi.POST("/products/:code", ws.handleProductRestore)
+	return echoInstance
+}
+
+func TestWebServer_handleProductRestore(t *testing.T) {
+	t.Run("Should restore a deleted product", restoreProductSuccess)
+	t.Run("Should results error if there is no deleted product", restoreProductNotFoundErr)
+	t.Run("Should results error if an active product has the same code", restoreProductDuplicatedErr)
+	t.Run("Should results not found if the custom method is unknown", restoreProductUnknownMethodErr)
+}
+
+func TestWebServer_router(t *testing.T) {
+	t.Run("Should route the restore custom method with the product code", routeRestoreCustomMethod)
+	t.Run("Should not route a bare POST on a product to restore", routeBareProductPost)
+}
+
+func routeRestoreCustomMethod(t *testing.T) {
+	var codes []string
+	productRepo := restoreCodeSpy{
+		ProductRepositoryInMemorySpy: infrarepository.ProductRepositoryInMemorySpy{
+			ExpectedData: repository.ProductRepositoryData{Code: "ABC"},
+		},
+		codes: &codes,
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/ABC:restore", nil)
+	NewWebServer("8080", productRepo).router().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"ABC"}, codes)
+}
+
+func routeBareProductPost(t *testing.T) {
+	var codes []string
+	productRepo := restoreCodeSpy{codes: &codes}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/ABC", nil)
+	NewWebServer("8080", productRepo).router().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, codes)
+}
+
+func restoreProductSuccess(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedData: repository.ProductRepositoryData{ID: 7, Code: "XSZ-000741"},
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/XSZ-000741:restore", nil)
+	newTrashServer(productRepo).ServeHTTP(rec, req)
+
+	var outputDTO ProductResponseV1
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "XSZ-000741", outputDTO.Code)
+	assert.Empty(t, outputDTO.DeletedAt)
+}
+
+func restoreProductNotFoundErr(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedError: entity.ProductNotFoundErr,
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/XSZ-000741:restore", nil)
+	newTrashServer(productRepo).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func restoreProductDuplicatedErr(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedError: entity.DuplicatedProductCodeErr,
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/XSZ-000741:restore", nil)
+	newTrashServer(productRepo).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func restoreProductUnknownMethodErr(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/XSZ-000741:archive", nil)
+	newTrashServer(infrarepository.NewProductRepositoryInMemory()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestWebServer_handleProductTrash(t *testing.T) {
+	t.Run("Should list deleted products", trashProductsSuccess)
+	t.Run("Should results error if pagination is invalid", trashProductsPaginationErr)
+}
+
+func trashProductsSuccess(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedData: repository.ProductRepositoryData{
+			Code:      "XSZ-000741",
+			DeletedAt: "2024-03-01 09:00:00",
+		},
+	}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/trash?page=1", nil)
+	newTrashServer(productRepo).ServeHTTP(rec, req)
+
+	var outputDTO struct {
+		Items []ProductResponseV1 `json:"items"`
+		Total int64               `json:"total"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(1), outputDTO.Total)
+	assert.Equal(t, "2024-03-01 09:00:00", outputDTO.Items[0].DeletedAt)
+}
+
+func trashProductsPaginationErr(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/trash?pageSize=1000", nil)
+	newTrashServer(infrarepository.NewProductRepositoryInMemory()).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
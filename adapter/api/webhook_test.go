@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	corerepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type webhookDelivererStub struct{}
+
+func (webhookDelivererStub) Deliver(_ context.Context,
+	delivery corerepository.WebhookDeliveryData) corerepository.WebhookDeliveryData {
+	delivery.Attempts++
+	delivery.Status = corerepository.WebhookDeliveryDelivered
+	delivery.LastError = ""
+	return delivery
+}
+
+func TestWebServer_handleWebhooks(t *testing.T) {
+	t.Run("Should create a webhook returning its secret", webhookCreateSuccess)
+	t.Run("Should results bad request if webhook url is invalid", webhookCreateInvalidURLErr)
+	t.Run("Should results not found if webhook does not exists", webhookGetNotFoundErr)
+	t.Run("Should list dead letters and redeliver them", webhookRedeliverSuccess)
+	t.Run("Should only accept and produce json", webhookNonJSONErr)
+}
+
+func newWebhookServer(deliveryRepo corerepository.WebhookDeliveryRepository) *echo.Echo {
+	ws := NewWebServer("8080", repository.NewProductRepositoryInMemory())
+	ws.EnableWebhooks(repository.NewWebhookRepositoryInMemory(), deliveryRepo, webhookDelivererStub{})
+	return ws.router()
+}
+
+func webhookCreateSuccess(t *testing.T) {
+	echoInstance := newWebhookServer(repository.NewWebhookDeliveryRepositoryInMemory())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks",
+		strings.NewReader(`{"url":"https://hooks.example.com","eventTypes":["product.created"]}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	echoInstance.ServeHTTP(rec, req)
+
+	var outputDTO WebhookResponseV1
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &outputDTO))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, int64(1), outputDTO.ID)
+	assert.NotEmpty(t, outputDTO.Secret)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/1", nil)
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "secret")
+}
+
+func webhookCreateInvalidURLErr(t *testing.T) {
+	echoInstance := newWebhookServer(repository.NewWebhookDeliveryRepositoryInMemory())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks",
+		strings.NewReader(`{"url":"hooks.example.com"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func webhookGetNotFoundErr(t *testing.T) {
+	echoInstance := newWebhookServer(repository.NewWebhookDeliveryRepositoryInMemory())
+	for _, path := range []string{"/api/v1/webhooks/42", "/api/v1/webhooks/abc"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		echoInstance.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	}
+}
+
+func webhookRedeliverSuccess(t *testing.T) {
+	deliveryRepo := repository.NewWebhookDeliveryRepositoryInMemory()
+	_, err := deliveryRepo.Insert(context.TODO(), corerepository.WebhookDeliveryData{
+		WebhookID: 1,
+		EventID:   "3f0b5a9e-4a7c-4a53-9a53-0b4c0cc1f2a1",
+		EventType: "product.updated",
+		Payload:   []byte(`{}`),
+		Status:    corerepository.WebhookDeliveryDead,
+		Attempts:  5,
+		LastError: "unexpected status 500",
+	})
+	assert.NoError(t, err)
+	echoInstance := newWebhookServer(deliveryRepo)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/deliveries/dead", nil)
+	echoInstance.ServeHTTP(rec, req)
+	var deadLetters WebhookDeliveryListResponseV1
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &deadLetters))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(1), deadLetters.Total)
+	assert.Equal(t, "unexpected status 500", deadLetters.Items[0].LastError)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/deliveries/1:redeliver", nil)
+	echoInstance.ServeHTTP(rec, req)
+	var delivery WebhookDeliveryResponseV1
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &delivery))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, corerepository.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 6, delivery.Attempts)
+}
+
+func webhookNonJSONErr(t *testing.T) {
+	echoInstance := newWebhookServer(repository.NewWebhookDeliveryRepositoryInMemory())
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks",
+		strings.NewReader(`{"url":"https://hooks.example.com"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationProtobuf)
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/webhooks",
+		strings.NewReader(`<webhook><url>https://hooks.example.com</url></webhook>`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationXML)
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/webhooks/1", nil)
+	echoInstance.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+)
+
+type MappedError struct {
+	ResultErr error
+	Code      string
+}
+
+func (m MappedError) Error() string {
+	return m.ResultErr.Error()
+}
+
+func (m MappedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": m.Code}
+}
+
+func Mapping(input error) MappedError {
+	switch input {
+	case entity.InvalidCodeErr,
+		entity.RequiredReferenceErr,
+		entity.InvalidPriceErr,
+		entity.RequiredDescriptionErr,
+		entity.RequiredTitleErr,
+		entity.InvalidPaginationErr,
+		entity.InvalidPriceRangeErr,
+		entity.UnsupportedCurrencyErr,
+		entity.InvalidFieldErr,
+		entity.InvalidRevisionErr,
+		entity.InvalidWebhookURLErr,
+		entity.PrivateWebhookTargetErr,
+		entity.RequiredWebhookSecretErr,
+		entity.InvalidEventTypeErr,
+		entity.InvalidLastEventIDErr:
+		return MappedError{
+			ResultErr: input,
+			Code:      "BAD_USER_INPUT",
+		}
+	case entity.DuplicatedProductCodeErr:
+		return MappedError{
+			ResultErr: input,
+			Code:      "CONFLICT",
+		}
+	case entity.ProductNotFoundErr, entity.RevisionNotFoundErr,
+		entity.WebhookNotFoundErr, entity.DeliveryNotFoundErr:
+		return MappedError{
+			ResultErr: input,
+			Code:      "NOT_FOUND",
+		}
+	}
+	if errors.Is(input, context.DeadlineExceeded) {
+		return MappedError{
+			ResultErr: input,
+			Code:      "GATEWAY_TIMEOUT",
+		}
+	}
+	return MappedError{
+		ResultErr: input,
+		Code:      "INTERNAL_SERVER_ERROR",
+	}
+}
@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+const (
+	ActorHeader = "X-Actor"
+)
+
+var errMutationOverGet = errors.New("mutations must be sent with POST")
+
+type Handler struct {
+	schema      graphql.Schema
+	productRepo repository.ProductRepository
+	options     []usecase.ProductOption
+}
+
+type requestBody struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func NewHandler(productRepo repository.ProductRepository,
+	opts ...usecase.ProductOption) (*Handler, error) {
+	schema, err := NewSchema(productRepo, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, productRepo: productRepo, options: opts}, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body requestBody
+	switch r.Method {
+	case http.MethodGet:
+		body.Query = r.URL.Query().Get("query")
+		body.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &body.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		if isMutation(body.Query, body.OperationName) {
+			w.Header().Set("Allow", "POST")
+			writeError(w, http.StatusMethodNotAllowed, errMutationOverGet)
+			return
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := usecase.WithActor(r.Context(), r.Header.Get(ActorHeader))
+	ctx = withLoader(ctx, newProductLoader(h.productRepo, h.options...))
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  body.Query,
+		OperationName:  body.OperationName,
+		VariableValues: body.Variables,
+		Context:        ctx,
+	})
+	for index, formattedErr := range result.Errors {
+		if formattedErr.Extensions == nil {
+			result.Errors[index].Extensions = extensionsFrom(formattedErr)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func isMutation(query, operationName string) bool {
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false
+	}
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok || operation.Operation != ast.OperationTypeMutation {
+			continue
+		}
+		if operationName == "" || (operation.Name != nil && operation.Name.Value == operationName) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"message": err.Error()}},
+	})
+}
+
+func extensionsFrom(err error) map[string]interface{} {
+	for err != nil {
+		switch current := err.(type) {
+		case MappedError:
+			return current.Extensions()
+		case *gqlerrors.Error:
+			err = current.OriginalError
+		case interface{ OriginalError() error }:
+			err = current.OriginalError()
+		default:
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	infrarepository "github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingRepository struct {
+	repository.ProductRepository
+	getByCodesCalls int32
+}
+
+func (r *countingRepository) GetByCodes(ctx context.Context,
+	codes []string) ([]repository.ProductRepositoryData, error) {
+	atomic.AddInt32(&r.getByCodesCalls, 1)
+	return r.ProductRepository.GetByCodes(ctx, codes)
+}
+
+func newProductRepositoryWith(t *testing.T, codes ...string) repository.ProductRepository {
+	productRepo := infrarepository.NewProductRepositoryInMemory()
+	for _, code := range codes {
+		_, err := productRepo.Insert(context.TODO(), repository.ProductRepositoryInput{
+			Title:        "Toy",
+			Description:  "Blahahhs",
+			Code:         code,
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(51400),
+		})
+		assert.NoError(t, err)
+	}
+	return productRepo
+}
+
+type graphResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func doGraphRequest(t *testing.T, productRepo repository.ProductRepository,
+	query string, variables map[string]interface{}) (int, graphResponse) {
+	handler, err := NewHandler(productRepo)
+	assert.NoError(t, err)
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	assert.NoError(t, err)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	handler.ServeHTTP(rec, req)
+
+	var response graphResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return rec.Code, response
+}
+
+func TestHandler_Query(t *testing.T) {
+	t.Run("Should query a product by code with success", queryProductSuccess)
+	t.Run("Should batch product lookups into a single repository call", queryProductBatched)
+	t.Run("Should results not found error if product code does not exists", queryProductNotFoundErr)
+	t.Run("Should list products with pagination", queryProductsSuccess)
+	t.Run("Should results error if pagination is invalid", queryProductsInvalidPaginationErr)
+}
+
+func queryProductSuccess(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedData: repository.ProductRepositoryData{Code: "XSZ-000741", Title: "Toy", PriceInCents: 51400},
+	}
+	code, response := doGraphRequest(t, productRepo,
+		`{ product(code: "xsz-000741") { code title priceInCents } }`, nil)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"code":"XSZ-000741","title":"Toy","priceInCents":51400}`,
+		string(response.Data["product"]))
+}
+
+func queryProductBatched(t *testing.T) {
+	productRepo := &countingRepository{ProductRepository: newProductRepositoryWith(t, "AAA-1", "BBB-2", "CCC-3")}
+	_, response := doGraphRequest(t, productRepo, `{
+		first: product(code: "AAA-1") { code }
+		second: product(code: "BBB-2") { code }
+		third: product(code: "CCC-3") { code }
+	}`, nil)
+
+	assert.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"code":"AAA-1"}`, string(response.Data["first"]))
+	assert.JSONEq(t, `{"code":"CCC-3"}`, string(response.Data["third"]))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&productRepo.getByCodesCalls))
+}
+
+func queryProductNotFoundErr(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedData: repository.ProductRepositoryData{Code: "OTHER"},
+	}
+	_, response := doGraphRequest(t, productRepo,
+		`{ product(code: "XSZ-000741") { code } }`, nil)
+
+	assert.Len(t, response.Errors, 1)
+	assert.Equal(t, entity.ProductNotFoundErr.Error(), response.Errors[0].Message)
+	assert.Equal(t, "NOT_FOUND", response.Errors[0].Extensions["code"])
+	assert.Equal(t, "null", string(response.Data["product"]))
+}
+
+func queryProductsSuccess(t *testing.T) {
+	_, response := doGraphRequest(t, newProductRepositoryWith(t, "XSZ-000741", "ABC-000001"),
+		`query($filter: ProductFilter) {
+			products(filter: $filter, page: 1, pageSize: 10) { page pageSize total items { code } }
+		}`, map[string]interface{}{"filter": map[string]interface{}{"code": "XSZ"}})
+
+	assert.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"page":1,"pageSize":10,"total":1,"items":[{"code":"XSZ-000741"}]}`,
+		string(response.Data["products"]))
+}
+
+func queryProductsInvalidPaginationErr(t *testing.T) {
+	_, response := doGraphRequest(t, infrarepository.NewProductRepositoryInMemory(),
+		`{ products(pageSize: 1000) { total } }`, nil)
+
+	assert.Len(t, response.Errors, 1)
+	assert.Equal(t, entity.InvalidPaginationErr.Error(), response.Errors[0].Message)
+	assert.Equal(t, "BAD_USER_INPUT", response.Errors[0].Extensions["code"])
+}
+
+func TestHandler_Mutation(t *testing.T) {
+	t.Run("Should create a product and return it", mutationCreateProductSuccess)
+	t.Run("Should results conflict error if product code is duplicated", mutationCreateProductDuplicatedErr)
+	t.Run("Should delete a product with success", mutationDeleteProductSuccess)
+	t.Run("Should keep prices beyond the 32-bit range", mutationCreateProductLongPrice)
+	t.Run("Should reject mutations sent with GET", mutationOverGetErr)
+	t.Run("Should run queries sent with GET", queryOverGetSuccess)
+}
+
+func mutationCreateProductSuccess(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedData: repository.ProductRepositoryData{Code: "XSZ-000741"},
+	}
+	_, response := doGraphRequest(t, productRepo,
+		`mutation($input: ProductInput!) { createProduct(input: $input) { code } }`,
+		map[string]interface{}{"input": map[string]interface{}{
+			"title":        "Toy",
+			"description":  "Description",
+			"code":         "XSZ-000741",
+			"reference":    "XZsdf5tY-AA",
+			"priceInCents": 2500,
+		}})
+
+	assert.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"code":"XSZ-000741"}`, string(response.Data["createProduct"]))
+}
+
+func mutationCreateProductDuplicatedErr(t *testing.T) {
+	productRepo := infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedError: entity.DuplicatedProductCodeErr,
+	}
+	_, response := doGraphRequest(t, productRepo,
+		`mutation($input: ProductInput!) { createProduct(input: $input) { code } }`,
+		map[string]interface{}{"input": map[string]interface{}{
+			"title":        "Toy",
+			"description":  "Description",
+			"code":         "XSZ-000741",
+			"reference":    "XZsdf5tY-AA",
+			"priceInCents": 2500,
+		}})
+
+	assert.Len(t, response.Errors, 1)
+	assert.Equal(t, "CONFLICT", response.Errors[0].Extensions["code"])
+}
+
+func mutationDeleteProductSuccess(t *testing.T) {
+	_, response := doGraphRequest(t, newProductRepositoryWith(t, "XSZ-000741"),
+		`mutation { deleteProduct(code: "XSZ-000741") }`, nil)
+
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, "true", string(response.Data["deleteProduct"]))
+}
+
+func mutationCreateProductLongPrice(t *testing.T) {
+	productRepo := infrarepository.NewProductRepositoryInMemory()
+	_, response := doGraphRequest(t, productRepo,
+		`mutation($input: ProductInput!) { createProduct(input: $input) { priceInCents } }`,
+		map[string]interface{}{"input": map[string]interface{}{
+			"title":        "Toy",
+			"description":  "Description",
+			"code":         "XSZ-000741",
+			"reference":    "XZsdf5tY-AA",
+			"priceInCents": 5000000000,
+		}})
+
+	assert.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"priceInCents":5000000000}`, string(response.Data["createProduct"]))
+
+	_, response = doGraphRequest(t, productRepo,
+		`{ products(filter: {minPriceInCents: 4000000000}) { items { priceInCents } } }`, nil)
+
+	assert.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"items":[{"priceInCents":5000000000}]}`, string(response.Data["products"]))
+}
+
+func doGraphGetRequest(t *testing.T, productRepo repository.ProductRepository,
+	query, operationName string) *httptest.ResponseRecorder {
+	handler, err := NewHandler(productRepo)
+	assert.NoError(t, err)
+
+	params := url.Values{"query": {query}, "operationName": {operationName}}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/graphql?"+params.Encode(), nil)
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func mutationOverGetErr(t *testing.T) {
+	productRepo := newProductRepositoryWith(t, "XSZ-000741")
+	rec := doGraphGetRequest(t, productRepo,
+		`query Read { product(code: "XSZ-000741") { code } }
+		mutation Remove { deleteProduct(code: "XSZ-000741") }`, "Remove")
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Equal(t, "POST", rec.Header().Get("Allow"))
+	_, err := productRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+}
+
+func queryOverGetSuccess(t *testing.T) {
+	rec := doGraphGetRequest(t, newProductRepositoryWith(t, "XSZ-000741"),
+		`query Read { product(code: "XSZ-000741") { code } }
+		mutation Remove { deleteProduct(code: "XSZ-000741") }`, "Read")
+
+	var response graphResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, response.Errors)
+	assert.JSONEq(t, `{"code":"XSZ-000741"}`, string(response.Data["product"]))
+}
+
+func TestMapping(t *testing.T) {
+	t.Run("Should map deadline exceeded to gateway timeout", mappingDeadlineExceeded)
+}
+
+func mappingDeadlineExceeded(t *testing.T) {
+	mapped := Mapping(fmt.Errorf("product list: %w", context.DeadlineExceeded))
+
+	assert.Equal(t, "GATEWAY_TIMEOUT", mapped.Code)
+}
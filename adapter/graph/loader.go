@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+const (
+	loaderWait = time.Millisecond * 2
+)
+
+type loadersKey struct{}
+
+type productLoader = dataloader.Loader[string, usecase.ProductGetOutputDTO]
+
+func newProductLoader(productRepo repository.ProductRepository, opts ...usecase.ProductOption) *productLoader {
+	productGet := usecase.NewProductGet(productRepo, opts...)
+
+	batchFn := func(ctx context.Context, codes []string) []*dataloader.Result[usecase.ProductGetOutputDTO] {
+		results := make([]*dataloader.Result[usecase.ProductGetOutputDTO], len(codes))
+		outputDTOs, err := productGet.ExecuteMany(ctx, codes)
+		if err != nil {
+			for index := range codes {
+				results[index] = &dataloader.Result[usecase.ProductGetOutputDTO]{Error: err}
+			}
+			return results
+		}
+
+		productsByCode := make(map[string]usecase.ProductGetOutputDTO, len(outputDTOs))
+		for _, outputDTO := range outputDTOs {
+			productsByCode[normalizeCode(outputDTO.Code)] = outputDTO
+		}
+		for index, code := range codes {
+			if outputDTO, ok := productsByCode[code]; ok {
+				results[index] = &dataloader.Result[usecase.ProductGetOutputDTO]{Data: outputDTO}
+				continue
+			}
+			results[index] = &dataloader.Result[usecase.ProductGetOutputDTO]{Error: entity.ProductNotFoundErr}
+		}
+		return results
+	}
+	return dataloader.NewBatchedLoader(batchFn, dataloader.WithWait[string, usecase.ProductGetOutputDTO](loaderWait))
+}
+
+func withLoader(ctx context.Context, loader *productLoader) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loader)
+}
+
+func loaderFrom(ctx context.Context) *productLoader {
+	return ctx.Value(loadersKey{}).(*productLoader)
+}
+
+func normalizeCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, " ", ""))
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+type resolver struct {
+	productRepo repository.ProductRepository
+	options     []usecase.ProductOption
+}
+
+func (r *resolver) product(params graphql.ResolveParams) (interface{}, error) {
+	code, _ := params.Args["code"].(string)
+	return r.loadProduct(params, code), nil
+}
+
+func (r *resolver) products(params graphql.ResolveParams) (interface{}, error) {
+	input := usecase.ProductListInputDTO{}
+	input.Page, _ = params.Args["page"].(int)
+	input.PageSize, _ = params.Args["pageSize"].(int)
+	if filter, ok := params.Args["filter"].(map[string]interface{}); ok {
+		input.Code, _ = filter["code"].(string)
+		input.Title, _ = filter["title"].(string)
+		input.MinPriceInCents, _ = filter["minPriceInCents"].(int64)
+		input.MaxPriceInCents, _ = filter["maxPriceInCents"].(int64)
+	}
+
+	productList := usecase.NewProductList(r.productRepo, r.options...)
+	outputDTO, err := productList.Execute(params.Context, input)
+	if err != nil {
+		return nil, Mapping(err)
+	}
+	return outputDTO, nil
+}
+
+func (r *resolver) createProduct(params graphql.ResolveParams) (interface{}, error) {
+	inputDTO := toProductInputDTO(params.Args["input"])
+	productCreate := usecase.NewProductCreate(r.productRepo, r.options...)
+	if _, err := productCreate.Execute(params.Context, inputDTO); err != nil {
+		return nil, Mapping(err)
+	}
+	return r.reloadProduct(params, inputDTO.Code), nil
+}
+
+func (r *resolver) updateProduct(params graphql.ResolveParams) (interface{}, error) {
+	inputDTO := toProductInputDTO(params.Args["input"])
+	productUpdate := usecase.NewProductUpdate(r.productRepo, r.options...)
+	if _, err := productUpdate.Execute(params.Context, inputDTO); err != nil {
+		return nil, Mapping(err)
+	}
+	return r.reloadProduct(params, inputDTO.Code), nil
+}
+
+func (r *resolver) deleteProduct(params graphql.ResolveParams) (interface{}, error) {
+	code, _ := params.Args["code"].(string)
+	productDelete := usecase.NewProductDelete(r.productRepo, r.options...)
+	isDeleted, err := productDelete.Execute(params.Context, code)
+	if err != nil {
+		return nil, Mapping(err)
+	}
+	loaderFrom(params.Context).Clear(params.Context, normalizeCode(code))
+	return isDeleted, nil
+}
+
+func (r *resolver) loadProduct(params graphql.ResolveParams, code string) func() (interface{}, error) {
+	thunk := loaderFrom(params.Context).Load(params.Context, normalizeCode(code))
+	return func() (interface{}, error) {
+		outputDTO, err := thunk()
+		if err != nil {
+			return nil, Mapping(err)
+		}
+		return outputDTO, nil
+	}
+}
+
+func (r *resolver) reloadProduct(params graphql.ResolveParams, code string) func() (interface{}, error) {
+	loaderFrom(params.Context).Clear(params.Context, normalizeCode(code))
+	return r.loadProduct(params, code)
+}
+
+func toProductInputDTO(arg interface{}) usecase.ProductInputDTO {
+	input, _ := arg.(map[string]interface{})
+	inputDTO := usecase.ProductInputDTO{}
+	inputDTO.Title, _ = input["title"].(string)
+	inputDTO.Description, _ = input["description"].(string)
+	inputDTO.Code, _ = input["code"].(string)
+	inputDTO.Reference, _ = input["reference"].(string)
+	inputDTO.PriceInCents, _ = input["priceInCents"].(int64)
+	return inputDTO
+}
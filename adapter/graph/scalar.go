@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// longType carries 64-bit integers such as prices in cents, which overflow the
+// 32-bit Int of the GraphQL spec.
+var longType = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "Long",
+	Description:  "A signed 64-bit integer.",
+	Serialize:    coerceLong,
+	ParseValue:   coerceLong,
+	ParseLiteral: parseLongLiteral,
+})
+
+func coerceLong(value interface{}) interface{} {
+	switch value := value.(type) {
+	case int64:
+		return value
+	case *int64:
+		if value == nil {
+			return nil
+		}
+		return *value
+	case int:
+		return int64(value)
+	case int32:
+		return int64(value)
+	case float64:
+		if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+			return nil
+		}
+		return int64(value)
+	}
+	return nil
+}
+
+func parseLongLiteral(valueAST ast.Value) interface{} {
+	intValue, ok := valueAST.(*ast.IntValue)
+	if !ok {
+		return nil
+	}
+	value, err := strconv.ParseInt(intValue.Value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return value
+}
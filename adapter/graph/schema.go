@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+var productType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Product",
+	Fields: graphql.Fields{
+		"id":           &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"title":        &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"description":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"code":         &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"reference":    &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"priceInCents": &graphql.Field{Type: graphql.NewNonNull(longType)},
+		"createdAt":    &graphql.Field{Type: graphql.String},
+		"updatedAt":    &graphql.Field{Type: graphql.String},
+	},
+})
+
+var productPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ProductPage",
+	Fields: graphql.Fields{
+		"items":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productType)))},
+		"page":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"pageSize": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"total":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+var productFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ProductFilter",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"code":            &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"title":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"minPriceInCents": &graphql.InputObjectFieldConfig{Type: longType},
+		"maxPriceInCents": &graphql.InputObjectFieldConfig{Type: longType},
+	},
+})
+
+var productInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ProductInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"description":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"code":         &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"reference":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"priceInCents": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(longType)},
+	},
+})
+
+func NewSchema(productRepo repository.ProductRepository,
+	opts ...usecase.ProductOption) (graphql.Schema, error) {
+	resolver := &resolver{productRepo: productRepo, options: opts}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"product": &graphql.Field{
+				Type: productType,
+				Args: graphql.FieldConfigArgument{
+					"code": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolver.product,
+			},
+			"products": &graphql.Field{
+				Type: graphql.NewNonNull(productPageType),
+				Args: graphql.FieldConfigArgument{
+					"filter":   &graphql.ArgumentConfig{Type: productFilterType},
+					"page":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+					"pageSize": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: usecase.ProductListDefaultPageSize},
+				},
+				Resolve: resolver.products,
+			},
+		},
+	})
+
+	mutationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createProduct": &graphql.Field{
+				Type: graphql.NewNonNull(productType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(productInputType)},
+				},
+				Resolve: resolver.createProduct,
+			},
+			"updateProduct": &graphql.Field{
+				Type: graphql.NewNonNull(productType),
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(productInputType)},
+				},
+				Resolve: resolver.updateProduct,
+			},
+			"deleteProduct": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"code": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: resolver.deleteProduct,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    queryType,
+		Mutation: mutationType,
+	})
+}
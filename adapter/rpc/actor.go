@@ -0,0 +1,44 @@
+package rpc
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	ActorMetadataKey       = "x-actor"
+	ReadSessionMetadataKey = "x-read-primary-until"
+)
+
+func actorInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ActorMetadataKey); len(values) > 0 {
+			ctx = usecase.WithActor(ctx, values[0])
+		}
+	}
+	return handler(ctx, req)
+}
+
+func readSessionInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	var primaryUntil time.Time
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ReadSessionMetadataKey); len(values) > 0 {
+			if millis, err := strconv.ParseInt(values[0], 10, 64); err == nil {
+				primaryUntil = time.UnixMilli(millis)
+			}
+		}
+	}
+	session := usecase.NewReadSession(primaryUntil)
+	response, err := handler(usecase.WithReadSession(ctx, session), req)
+	if until := session.PrimaryUntil(); until.After(primaryUntil) {
+		grpc.SetHeader(ctx, metadata.Pairs(ReadSessionMetadataKey, strconv.FormatInt(until.UnixMilli(), 10)))
+	}
+	return response, err
+}
@@ -0,0 +1,40 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Mapping(input error) error {
+	switch input {
+	case entity.InvalidCodeErr,
+		entity.RequiredReferenceErr,
+		entity.InvalidPriceErr,
+		entity.RequiredDescriptionErr,
+		entity.RequiredTitleErr,
+		entity.InvalidPaginationErr,
+		entity.InvalidPriceRangeErr,
+		entity.UnsupportedCurrencyErr,
+		entity.InvalidFieldErr,
+		entity.InvalidRevisionErr,
+		entity.InvalidWebhookURLErr,
+		entity.PrivateWebhookTargetErr,
+		entity.RequiredWebhookSecretErr,
+		entity.InvalidEventTypeErr,
+		entity.InvalidLastEventIDErr:
+		return status.Error(codes.InvalidArgument, input.Error())
+	case entity.DuplicatedProductCodeErr:
+		return status.Error(codes.AlreadyExists, input.Error())
+	case entity.ProductNotFoundErr, entity.RevisionNotFoundErr,
+		entity.WebhookNotFoundErr, entity.DeliveryNotFoundErr:
+		return status.Error(codes.NotFound, input.Error())
+	}
+	if errors.Is(input, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, input.Error())
+	}
+	return status.Error(codes.Internal, input.Error())
+}
@@ -0,0 +1,114 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/lbsti/eulabs-challenge/adapter/rpc/pb"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type GrpcServer struct {
+	pb.UnimplementedProductServiceServer
+	productRepo repository.ProductRepository
+	port        string
+	options     []usecase.ProductOption
+}
+
+func NewGrpcServer(port string, productRepo repository.ProductRepository,
+	opts ...usecase.ProductOption) *GrpcServer {
+	return &GrpcServer{productRepo: productRepo, port: port, options: opts}
+}
+
+func (gs *GrpcServer) Run() {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", gs.port))
+	if err != nil {
+		log.Fatalf("unable to listen grpc port %s: %v", gs.port, err)
+	}
+	if err := gs.Register().Serve(listener); err != nil {
+		log.Fatalf("unable to serve grpc: %v", err)
+	}
+}
+
+func (gs *GrpcServer) Register() *grpc.Server {
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(actorInterceptor, readSessionInterceptor))
+	pb.RegisterProductServiceServer(grpcServer, gs)
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(pb.ProductService_ServiceDesc.ServiceName,
+		healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	reflection.Register(grpcServer)
+	return grpcServer
+}
+
+func (gs *GrpcServer) CreateProduct(ctx context.Context,
+	req *pb.CreateProductRequest) (*pb.CreateProductResponse, error) {
+	productCreate := usecase.NewProductCreate(gs.productRepo, gs.options...)
+	outputDTO, err := productCreate.Execute(ctx, toProductInputDTO(req.GetProduct()))
+	if err != nil {
+		return nil, Mapping(err)
+	}
+	return &pb.CreateProductResponse{
+		Id:        outputDTO.ID,
+		Reference: outputDTO.Reference,
+		CreatedAt: outputDTO.CreatedAt,
+	}, nil
+}
+
+func (gs *GrpcServer) GetProduct(ctx context.Context,
+	req *pb.GetProductRequest) (*pb.Product, error) {
+	productGet := usecase.NewProductGet(gs.productRepo, gs.options...)
+	outputDTO, err := productGet.Execute(ctx, req.GetCode())
+	if err != nil {
+		return nil, Mapping(err)
+	}
+	return &pb.Product{
+		Id:           outputDTO.ID,
+		Title:        outputDTO.Title,
+		Description:  outputDTO.Description,
+		Code:         outputDTO.Code,
+		Reference:    outputDTO.Reference,
+		PriceInCents: outputDTO.PriceInCents,
+		CreatedAt:    outputDTO.CreatedAt,
+		UpdatedAt:    outputDTO.UpdatedAt,
+	}, nil
+}
+
+func (gs *GrpcServer) UpdateProduct(ctx context.Context,
+	req *pb.UpdateProductRequest) (*pb.UpdateProductResponse, error) {
+	productUpdate := usecase.NewProductUpdate(gs.productRepo, gs.options...)
+	if _, err := productUpdate.Execute(ctx, toProductInputDTO(req.GetProduct())); err != nil {
+		return nil, Mapping(err)
+	}
+	return &pb.UpdateProductResponse{}, nil
+}
+
+func (gs *GrpcServer) DeleteProduct(ctx context.Context,
+	req *pb.DeleteProductRequest) (*pb.DeleteProductResponse, error) {
+	productDelete := usecase.NewProductDelete(gs.productRepo, gs.options...)
+	isDeleted, err := productDelete.Execute(ctx, req.GetCode())
+	if err != nil {
+		return nil, Mapping(err)
+	}
+	return &pb.DeleteProductResponse{Deleted: isDeleted}, nil
+}
+
+func toProductInputDTO(input *pb.ProductInput) usecase.ProductInputDTO {
+	return usecase.ProductInputDTO{
+		Title:        input.GetTitle(),
+		Description:  input.GetDescription(),
+		Code:         input.GetCode(),
+		Reference:    input.GetReference(),
+		PriceInCents: input.GetPriceInCents(),
+	}
+}
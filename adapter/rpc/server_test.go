@@ -0,0 +1,180 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/adapter/rpc/pb"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	infrarepository "github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T, productRepo repository.ProductRepository) *grpc.ClientConn {
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := NewGrpcServer("9090", productRepo).Register()
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func newProductRepositoryWith(t *testing.T, codes ...string) repository.ProductRepository {
+	productRepo := infrarepository.NewProductRepositoryInMemory()
+	for _, code := range codes {
+		_, err := productRepo.Insert(context.TODO(), repository.ProductRepositoryInput{
+			Title:        "Toy",
+			Description:  "Blahahhs",
+			Code:         code,
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(51400),
+		})
+		assert.NoError(t, err)
+	}
+	return productRepo
+}
+
+func validProductInput() *pb.ProductInput {
+	return &pb.ProductInput{
+		Code:         "XXCC",
+		Description:  "Description",
+		PriceInCents: int64(2500),
+		Reference:    "XZsdf5tY-AA",
+		Title:        "Toy",
+	}
+}
+
+func TestGrpcServer_CreateProduct(t *testing.T) {
+	t.Run("Should handle create product request with success", grpcCreateProductSuccess)
+	t.Run("Should results already exists if product code is duplicated", grpcCreateProductDuplicatedErr)
+	t.Run("Should results invalid argument if product code is empty", grpcCreateProductEmptyCodeErr)
+}
+
+func grpcCreateProductSuccess(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		infrarepository.NewProductRepositoryInMemory()))
+
+	output, err := client.CreateProduct(context.TODO(),
+		&pb.CreateProductRequest{Product: validProductInput()})
+	assert.NoError(t, err)
+	assert.Equal(t, "XZsdf5tY-AA", output.GetReference())
+	assert.Greater(t, output.GetId(), int64(0))
+}
+
+func grpcCreateProductDuplicatedErr(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		infrarepository.ProductRepositoryInMemorySpy{ExpectedError: entity.DuplicatedProductCodeErr}))
+
+	_, err := client.CreateProduct(context.TODO(),
+		&pb.CreateProductRequest{Product: validProductInput()})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Equal(t, entity.DuplicatedProductCodeErr.Error(), status.Convert(err).Message())
+}
+
+func grpcCreateProductEmptyCodeErr(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		infrarepository.NewProductRepositoryInMemory()))
+
+	input := validProductInput()
+	input.Code = ""
+	_, err := client.CreateProduct(context.TODO(), &pb.CreateProductRequest{Product: input})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, entity.InvalidCodeErr.Error(), status.Convert(err).Message())
+}
+
+func TestGrpcServer_GetProduct(t *testing.T) {
+	t.Run("Should handle get product request with success", grpcGetProductSuccess)
+	t.Run("Should results not found if product code does not exists", grpcGetProductNotFoundErr)
+}
+
+func grpcGetProductSuccess(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		newProductRepositoryWith(t, "XSZ-000741")))
+
+	output, err := client.GetProduct(context.TODO(), &pb.GetProductRequest{Code: "XSZ-000741"})
+	assert.NoError(t, err)
+	assert.Equal(t, "XSZ-000741", output.GetCode())
+}
+
+func grpcGetProductNotFoundErr(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		infrarepository.ProductRepositoryInMemorySpy{ExpectedError: entity.ProductNotFoundErr}))
+
+	_, err := client.GetProduct(context.TODO(), &pb.GetProductRequest{Code: "XSZ-000741"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGrpcServer_UpdateProduct(t *testing.T) {
+	t.Run("Should handle update product request with success", grpcUpdateProductSuccess)
+	t.Run("Should results not found if product code does not exists", grpcUpdateProductNotFoundErr)
+}
+
+func grpcUpdateProductSuccess(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		newProductRepositoryWith(t, "XXCC")))
+
+	_, err := client.UpdateProduct(context.TODO(),
+		&pb.UpdateProductRequest{Product: validProductInput()})
+	assert.NoError(t, err)
+}
+
+func grpcUpdateProductNotFoundErr(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		infrarepository.ProductRepositoryInMemorySpy{ExpectedError: entity.ProductNotFoundErr}))
+
+	_, err := client.UpdateProduct(context.TODO(),
+		&pb.UpdateProductRequest{Product: validProductInput()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGrpcServer_DeleteProduct(t *testing.T) {
+	t.Run("Should handle delete product request with success", grpcDeleteProductSuccess)
+	t.Run("Should results not found if product code does not exists", grpcDeleteProductNotFoundErr)
+}
+
+func grpcDeleteProductSuccess(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		newProductRepositoryWith(t, "XSZ-000741")))
+
+	output, err := client.DeleteProduct(context.TODO(), &pb.DeleteProductRequest{Code: "XSZ-000741"})
+	assert.NoError(t, err)
+	assert.True(t, output.GetDeleted())
+}
+
+func grpcDeleteProductNotFoundErr(t *testing.T) {
+	client := pb.NewProductServiceClient(newTestClient(t,
+		infrarepository.ProductRepositoryInMemorySpy{ExpectedError: entity.ProductNotFoundErr}))
+
+	_, err := client.DeleteProduct(context.TODO(), &pb.DeleteProductRequest{Code: "XSZ-000741"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGrpcServer_Health(t *testing.T) {
+	t.Run("Should report product service as serving", grpcHealthServing)
+}
+
+func grpcHealthServing(t *testing.T) {
+	client := healthpb.NewHealthClient(newTestClient(t,
+		infrarepository.NewProductRepositoryInMemory()))
+
+	output, err := client.Check(context.TODO(), &healthpb.HealthCheckRequest{
+		Service: pb.ProductService_ServiceDesc.ServiceName,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, output.GetStatus())
+}
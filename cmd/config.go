@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/lbsti/eulabs-challenge/internal/infra/config"
+)
+
+func configCommand(args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return fmt.Errorf("missing or unknown config command\n%s", usage)
+	}
+	flags := flag.NewFlagSet("config print", flag.ContinueOnError)
+	configFlags := config.RegisterFlags(flags)
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	settings, err := config.Settings(configFlags.Options())
+	if err != nil {
+		return err
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, setting := range settings {
+		source := setting.Source
+		if source == "" {
+			source = "unset"
+		}
+		fmt.Fprintf(writer, "%s=%s\t# %s\n", setting.Key, setting.Value, source)
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+
+	_, err = config.Load(configFlags.Options())
+	return err
+}
@@ -1,34 +1,74 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
+	"strings"
+	"time"
 
-	"github.com/lbsti/eulabs-challenge/adapter/api"
-	migrate "github.com/lbsti/eulabs-challenge/db"
 	"github.com/lbsti/eulabs-challenge/internal/infra/config"
 	"github.com/lbsti/eulabs-challenge/internal/infra/database"
-	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
 )
 
+const usage = `usage:
+  api serve [--auto-migrate] [--migrations-dir DIR] [CONFIG FLAGS]
+  api migrate [--migrations-dir DIR] [CONFIG FLAGS] up|down|status|redo|version
+  api migrate [--migrations-dir DIR] [CONFIG FLAGS] create NAME [sql|go]
+  api config print [CONFIG FLAGS]
+
+config flags: --config FILE, --env-file FILE and one --<setting> per setting, e.g. --database-host`
+
 func main() {
-	cfg := config.Load()
-	dbPool := database.NewDBPool(database.DBConfig{
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	command := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		return serve(args)
+	case "migrate":
+		return migrateCommand(args)
+	case "config":
+		return configCommand(args)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", command, usage)
+	}
+}
+
+func newDBPool(cfg config.Config) (database.DBPool, error) {
+	return database.NewDBPool(database.DBConfig{
+		Driver:             cfg.Database.Driver,
 		Host:               cfg.Database.Host,
+		Path:               cfg.Database.Path,
 		Port:               cfg.Database.Port,
 		User:               cfg.Database.User,
 		Password:           cfg.Database.Password,
 		DBName:             cfg.Database.Name,
 		MaxConnections:     cfg.Database.MaxConnections,
 		MaxIdleConnections: cfg.Database.MaxIdleConnections,
+		ReplicaHosts:       cfg.Database.ReplicaHosts,
+		ConnMaxLifetime:    time.Duration(cfg.Database.ConnMaxLifetimeSecs) * time.Second,
+		ConnMaxIdleTime:    time.Duration(cfg.Database.ConnMaxIdleTimeSecs) * time.Second,
+		TLSMode:            cfg.Database.TLSMode,
+		TLSCAFile:          cfg.Database.TLSCAFile,
+		TLSCertFile:        cfg.Database.TLSCertFile,
+		TLSKeyFile:         cfg.Database.TLSKeyFile,
+		TLSServerName:      cfg.Database.TLSServerName,
+		DialTimeout:        time.Duration(cfg.Database.DialTimeoutSecs) * time.Second,
+		ReadTimeout:        time.Duration(cfg.Database.ReadTimeoutSecs) * time.Second,
+		WriteTimeout:       time.Duration(cfg.Database.WriteTimeoutSecs) * time.Second,
+		ParseTime:          cfg.Database.ParseTime,
+		PasswordFunc: func(ctx context.Context) (string, error) {
+			return cfg.Secrets.Secret(ctx, config.DatabasePasswordKey)
+		},
 	})
-	db := dbPool.GetDB()
-	defer db.Close()
-
-	err := migrate.RunMigrate("up", dbPool.GetDSN(), "db/migrations", []string{}...)
-	if err != nil {
-		log.Default().Printf("failure when execute migration %v", err)
-	}
-	productRepo := repository.NewProductRepositorySQL(db)
-	webServer := api.NewWebServer(cfg.AppServerPort, productRepo)
-	webServer.Run()
 }
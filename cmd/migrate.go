@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+
+	migrate "github.com/lbsti/eulabs-challenge/db"
+	"github.com/lbsti/eulabs-challenge/internal/infra/config"
+)
+
+var migrateCommands = map[string]bool{
+	"up":      true,
+	"down":    true,
+	"status":  true,
+	"redo":    true,
+	"version": true,
+	"create":  true,
+}
+
+func migrateCommand(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	migrationsDir := flags.String("migrations-dir", "", "load migrations from this directory instead of the binary")
+	configFlags := config.RegisterFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+	if len(args) == 0 || !migrateCommands[args[0]] {
+		return fmt.Errorf("missing or unknown migrate command\n%s", usage)
+	}
+	command, args := args[0], args[1:]
+
+	cfg, err := config.Load(configFlags.Options())
+	if err != nil {
+		return err
+	}
+	dbPool, err := newDBPool(cfg)
+	if err != nil {
+		return err
+	}
+	db := dbPool.GetDB()
+	defer db.Close()
+
+	if command == "create" {
+		if len(args) == 0 {
+			return fmt.Errorf("migrate create requires a NAME\n%s", usage)
+		}
+		if len(args) == 1 {
+			args = append(args, "sql")
+		}
+		sourceDir := *migrationsDir
+		if sourceDir == "" {
+			sourceDir = migrate.SourceMigrationsDir
+		}
+		return migrate.RunMigrate(db, nil, dbPool.GetDriver(), command,
+			filepath.Join(sourceDir, migrate.MigrationsDir(dbPool.GetDriver())), args...)
+	}
+	return migrate.RunMigrate(db, migrate.Migrations(*migrationsDir), dbPool.GetDriver(), command,
+		migrate.MigrationsDir(dbPool.GetDriver()), args...)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"flag"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/adapter/api"
+	"github.com/lbsti/eulabs-challenge/adapter/rpc"
+	migrate "github.com/lbsti/eulabs-challenge/db"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/cache"
+	"github.com/lbsti/eulabs-challenge/internal/infra/config"
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
+	"github.com/lbsti/eulabs-challenge/internal/infra/job"
+	"github.com/lbsti/eulabs-challenge/internal/infra/outbox"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/lbsti/eulabs-challenge/internal/infra/stream"
+	"github.com/lbsti/eulabs-challenge/internal/infra/webhook"
+)
+
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	autoMigrate := flags.Bool("auto-migrate", false, "apply pending migrations before serving")
+	migrationsDir := flags.String("migrations-dir", "", "load migrations from this directory instead of the binary")
+	configFlags := config.RegisterFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.Load(configFlags.Options())
+	if err != nil {
+		return err
+	}
+	dbPool, err := newDBPool(cfg)
+	if err != nil {
+		return err
+	}
+	db := dbPool.GetDB()
+	defer db.Close()
+
+	if *autoMigrate {
+		if err := migrate.RunMigrate(db, migrate.Migrations(*migrationsDir), dbPool.GetDriver(), "up",
+			migrate.MigrationsDir(dbPool.GetDriver())); err != nil {
+			return err
+		}
+	}
+	dbRouter := database.NewRouter(db, dbPool.GetReplicaDBs(),
+		database.WithReadYourWrites(time.Duration(cfg.Database.ReadYourWritesMillis)*time.Millisecond))
+	go dbRouter.CheckHealth(context.Background(),
+		time.Duration(cfg.Database.ReplicaHealthCheckSecs)*time.Second)
+	queryTimeout := repository.WithQueryTimeout(time.Duration(cfg.Database.DefaultQueryTimeout) * time.Second)
+	productRepo := repository.NewProductRepositorySQL(dbRouter, queryTimeout)
+	outboxRepo := repository.NewOutboxRepositorySQL(db)
+	auditRepo := repository.NewProductAuditRepositorySQL(db)
+	revisionRepo := repository.NewProductRevisionRepositorySQL(db)
+	webhookRepo := repository.NewWebhookRepositorySQL(db)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepositorySQL(db)
+	switch dbPool.GetDriver() {
+	case database.PostgresDriver:
+		productRepo = repository.NewProductRepositoryPostgres(dbRouter, queryTimeout)
+		outboxRepo = repository.NewOutboxRepositoryPostgres(db)
+		auditRepo = repository.NewProductAuditRepositoryPostgres(db)
+		revisionRepo = repository.NewProductRevisionRepositoryPostgres(db)
+		webhookRepo = repository.NewWebhookRepositoryPostgres(db)
+		webhookDeliveryRepo = repository.NewWebhookDeliveryRepositoryPostgres(db)
+	case database.SQLiteDriver:
+		productRepo = repository.NewProductRepositorySQLite(dbRouter, queryTimeout)
+	}
+	if cfg.Cache.Enabled {
+		var productStore cache.ProductStore = cache.NewLRU(cfg.Cache.Size,
+			time.Duration(cfg.Cache.TTLSecs)*time.Second)
+		if cfg.Cache.Backend == cache.RedisBackend {
+			localStore := cache.NewLRU(cfg.Cache.Size, time.Duration(cfg.Cache.LocalTTLSecs)*time.Second)
+			redisStore := cache.NewRedisStore(cache.RedisOptions{
+				Addr:     cfg.Cache.RedisAddr,
+				Password: cfg.Cache.RedisPassword,
+				DB:       cfg.Cache.RedisDB,
+				Timeout:  time.Duration(cfg.Cache.RedisTimeoutMillis) * time.Millisecond,
+				TTL:      time.Duration(cfg.Cache.TTLSecs) * time.Second,
+			})
+			defer redisStore.Close()
+			redisStore.Subscribe(context.Background(), localStore)
+			productStore = cache.NewLayeredStore(localStore, redisStore)
+		}
+		cachedProductRepo := cache.NewProductRepository(productRepo, productStore)
+		expvar.Publish("productCache", expvar.Func(func() any { return cachedProductRepo.Stats() }))
+		productRepo = cachedProductRepo
+	}
+	productOptions := []usecase.ProductOption{usecase.WithTimeouts(usecase.ProductTimeouts{
+		Create: time.Duration(cfg.Timeouts.CreateSecs) * time.Second,
+		Get:    time.Duration(cfg.Timeouts.GetSecs) * time.Second,
+		Update: time.Duration(cfg.Timeouts.UpdateSecs) * time.Second,
+		Delete: time.Duration(cfg.Timeouts.DeleteSecs) * time.Second,
+		List:   time.Duration(cfg.Timeouts.ListSecs) * time.Second,
+	}), usecase.WithAuditRepository(auditRepo), usecase.WithRevisionRepository(revisionRepo)}
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, webhookDeliveryRepo,
+		webhook.NewHTTPClient(time.Duration(cfg.Webhook.TimeoutSecs)*time.Second,
+			cfg.Webhook.AllowedNetworks),
+		cfg.Webhook.MaxAttempts,
+		time.Duration(cfg.Webhook.BackoffMillis)*time.Millisecond,
+		cfg.Webhook.Workers, time.Duration(cfg.Webhook.IntervalMillis)*time.Millisecond)
+	go webhookDispatcher.Run(context.Background())
+	eventLog := stream.NewEventLog(cfg.Stream.LogSize)
+	productOptions = append(productOptions, usecase.WithEventEmitter(eventLog))
+	purgeJob := job.NewPurgeJob(productRepo,
+		time.Duration(cfg.Purge.RetentionDays)*24*time.Hour,
+		time.Duration(cfg.Purge.IntervalMinutes)*time.Minute)
+	go purgeJob.Run(context.Background())
+	eventPublisher, err := outbox.NewPublisher(cfg.Outbox.Publisher, cfg.Outbox.FilePath,
+		cfg.Outbox.HTTPURL, &http.Client{Timeout: time.Duration(cfg.Outbox.TimeoutSecs) * time.Second})
+	if err != nil {
+		return fmt.Errorf("unable to create outbox publisher: %w", err)
+	}
+	eventPublisher = outbox.NewFanoutPublisher(webhookDispatcher, eventPublisher)
+	outboxRelay := outbox.NewRelay(outboxRepo, eventPublisher,
+		cfg.Outbox.BatchSize, time.Duration(cfg.Outbox.IntervalMillis)*time.Millisecond,
+		cfg.Outbox.MaxAttempts, time.Duration(cfg.Outbox.BackoffMillis)*time.Millisecond,
+		outbox.WithMaxBackoff(time.Duration(cfg.Outbox.MaxBackoffSecs)*time.Second))
+	go outboxRelay.Run(context.Background())
+	grpcServer := rpc.NewGrpcServer(cfg.GrpcServerPort, productRepo, productOptions...)
+	go grpcServer.Run()
+	if cfg.AdminAddr != "" {
+		go api.NewAdminServer(cfg.AdminAddr).Run()
+	}
+	webServer := api.NewWebServer(cfg.AppServerPort, productRepo, productOptions...)
+	webServer.EnableWebhooks(webhookRepo, webhookDeliveryRepo, webhookDispatcher,
+		usecase.WithAllowedNetworks(cfg.Webhook.AllowedNetworks))
+	webServer.EnableProductEvents(eventLog, time.Duration(cfg.Stream.HeartbeatSecs)*time.Second)
+	webServer.Run()
+	return nil
+}
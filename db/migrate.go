@@ -1,31 +1,108 @@
 package db
 
 import (
-	"log"
-
-	_ "github.com/go-sql-driver/mysql"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
 
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
 	"github.com/pressly/goose/v3"
 )
 
 const (
-	dialect = "mysql"
+	SourceMigrationsDir  = "db/migrations"
+	migrationsRoot       = "migrations"
+	migrationLockName    = "eulabs_migrations"
+	migrationLockKey     = int64(7318092114)
+	migrationLockTimeout = time.Minute
 )
 
-func RunMigrate(command, dbString, dir string, args ...string) error {
-	db, err := goose.OpenDBWithDriver(dialect, dbString)
+//go:embed migrations
+var embeddedMigrations embed.FS
+
+var ErrMigrationLocked = errors.New("migrations are locked by another instance")
+
+var lockedCommands = map[string]bool{
+	"up":        true,
+	"up-by-one": true,
+	"up-to":     true,
+	"down":      true,
+	"down-to":   true,
+	"redo":      true,
+	"reset":     true,
+}
+
+func Migrations(sourceDir string) fs.FS {
+	if sourceDir != "" {
+		return os.DirFS(sourceDir)
+	}
+	migrations, err := fs.Sub(embeddedMigrations, migrationsRoot)
 	if err != nil {
-		log.Fatalf(err.Error())
-		return err
+		panic(err.Error())
+	}
+	return migrations
+}
+
+func MigrationsDir(driver string) string {
+	if driver == "" || driver == database.MySQLDriver {
+		return "."
+	}
+	return driver
+}
+
+func RunMigrate(db *sql.DB, migrations fs.FS, driver, command, dir string, args ...string) error {
+	goose.SetBaseFS(migrations)
+	if err := goose.SetDialect(database.SQLDriverName(driver)); err != nil {
+		return fmt.Errorf("migrate %v: %w", command, err)
 	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			log.Fatalf(err.Error())
+	if lockedCommands[command] {
+		unlock, err := lockMigrations(context.Background(), db, driver)
+		if err != nil {
+			return fmt.Errorf("migrate %v: %w", command, err)
 		}
-	}()
+		defer unlock()
+	}
 	if err := goose.Run(command, db, dir, args...); err != nil {
-		log.Fatalf("migrate %v: %v", command, err)
-		return err
+		return fmt.Errorf("migrate %v: %w", command, err)
 	}
 	return nil
 }
+
+func lockMigrations(ctx context.Context, db *sql.DB, driver string) (func(), error) {
+	if driver == database.SQLiteDriver {
+		return func() {}, nil
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lockQuery, unlockQuery := `SELECT GET_LOCK(?, ?)`, `SELECT RELEASE_LOCK(?)`
+	lockArgs, unlockArgs := []any{migrationLockName, int(migrationLockTimeout.Seconds())}, []any{migrationLockName}
+	if driver == database.PostgresDriver {
+		lockQuery, unlockQuery = `SELECT pg_advisory_lock($1) IS NOT NULL`, `SELECT pg_advisory_unlock($1)`
+		lockArgs, unlockArgs = []any{migrationLockKey}, []any{migrationLockKey}
+	}
+
+	var acquired sql.NullBool
+	if err := conn.QueryRowContext(ctx, lockQuery, lockArgs...).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired.Bool {
+		conn.Close()
+		return nil, ErrMigrationLocked
+	}
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), unlockQuery, unlockArgs...); err != nil {
+			slog.Error("impossible to release migration lock", slog.Any("msg", err))
+		}
+		conn.Close()
+	}, nil
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSQLiteTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open(database.SQLDriverName(database.SQLiteDriver), "file::memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRunMigrate(t *testing.T) {
+	t.Run("Should apply and roll back migrations", shouldApplyAndRollBackMigrations)
+	t.Run("Should load migrations from disk when a source dir is given", shouldLoadMigrationsFromDisk)
+	t.Run("Should return migration errors instead of exiting", shouldReturnMigrationErrors)
+}
+
+func shouldApplyAndRollBackMigrations(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	dir := MigrationsDir(database.SQLiteDriver)
+
+	assert.Nil(t, RunMigrate(db, Migrations(""), database.SQLiteDriver, "up", dir))
+	assert.Nil(t, RunMigrate(db, Migrations(""), database.SQLiteDriver, "status", dir))
+	assert.Nil(t, RunMigrate(db, Migrations(""), database.SQLiteDriver, "down", dir))
+
+	var indexes int
+	assert.Nil(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master
+	WHERE name = 'ukey_webhook_delivery_event'`).Scan(&indexes))
+	assert.Equal(t, 0, indexes)
+}
+
+func shouldLoadMigrationsFromDisk(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	sourceDir := t.TempDir()
+	assert.Nil(t, os.Mkdir(filepath.Join(sourceDir, database.SQLiteDriver), 0o755))
+	assert.Nil(t, os.WriteFile(filepath.Join(sourceDir, database.SQLiteDriver, "00001_create_drafts.sql"),
+		[]byte("-- +goose Up\nCREATE TABLE drafts (id INTEGER);\n-- +goose Down\nDROP TABLE drafts;\n"), 0o644))
+
+	assert.Nil(t, RunMigrate(db, Migrations(sourceDir), database.SQLiteDriver, "up",
+		MigrationsDir(database.SQLiteDriver)))
+
+	var tables int
+	assert.Nil(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master
+	WHERE type = 'table' AND name IN ('drafts', 'products')`).Scan(&tables))
+	assert.Equal(t, 1, tables)
+}
+
+func shouldReturnMigrationErrors(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	dir := MigrationsDir(database.SQLiteDriver)
+
+	assert.ErrorContains(t, RunMigrate(db, Migrations(""), database.SQLiteDriver, "up", "missing"), "migrate up")
+	assert.Error(t, RunMigrate(db, Migrations(""), database.SQLiteDriver, "sideways", dir))
+	assert.Error(t, RunMigrate(db, Migrations(""), "oracle", "up", dir))
+}
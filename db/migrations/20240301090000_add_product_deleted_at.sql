@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE products
+     ADD COLUMN deleted_at TIMESTAMP NULL DEFAULT NULL,
+     ADD COLUMN active_code VARCHAR(80) GENERATED ALWAYS AS (IF(deleted_at IS NULL, LOWER(code), NULL)) STORED,
+     DROP INDEX ukey_product_code,
+     ADD CONSTRAINT ukey_product_active_code UNIQUE (active_code),
+     ADD INDEX idx_product_deleted_at (deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM products WHERE deleted_at IS NOT NULL;
+ALTER TABLE products
+     DROP INDEX idx_product_deleted_at,
+     DROP INDEX ukey_product_active_code,
+     DROP COLUMN active_code,
+     DROP COLUMN deleted_at,
+     ADD CONSTRAINT ukey_product_code UNIQUE (code);
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE product_audit (
+     id BIGINT NOT NULL AUTO_INCREMENT,
+     product_code VARCHAR(80) NOT NULL,
+     operation VARCHAR(20) NOT NULL,
+     actor VARCHAR(255) NOT NULL,
+     before_data JSON NULL,
+     after_data JSON NULL,
+     created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     PRIMARY KEY (id),
+     INDEX idx_product_audit_code (product_code, id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_product_audit_no_update BEFORE UPDATE ON product_audit
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'product_audit is immutable';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_product_audit_no_delete BEFORE DELETE ON product_audit
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'product_audit is immutable';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_audit;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE product_revisions (
+     id BIGINT NOT NULL AUTO_INCREMENT,
+     product_code VARCHAR(80) NOT NULL,
+     revision INT UNSIGNED NOT NULL,
+     actor VARCHAR(255) NOT NULL,
+     data JSON NOT NULL,
+     created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+     PRIMARY KEY (id),
+     CONSTRAINT ukey_product_revision UNIQUE (product_code, revision)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO product_revisions (product_code, revision, actor, data)
+SELECT p.code, 1, 'migration', JSON_OBJECT(
+     'id', p.id,
+     'title', p.title,
+     'description', p.description,
+     'code', p.code,
+     'reference', p.reference,
+     'priceInCents', p.price_in_cents,
+     'createdAt', CAST(p.created_at AS CHAR),
+     'updatedAt', CAST(p.updated_at AS CHAR))
+FROM products p WHERE p.deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhooks (
+     id BIGINT NOT NULL AUTO_INCREMENT,
+     url VARCHAR(2048) NOT NULL,
+     secret VARCHAR(255) NOT NULL,
+     event_types JSON NOT NULL,
+     active BOOLEAN NOT NULL DEFAULT TRUE,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+     PRIMARY KEY (id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE webhook_deliveries (
+     id BIGINT NOT NULL AUTO_INCREMENT,
+     webhook_id BIGINT NOT NULL,
+     event_id VARCHAR(36) NOT NULL,
+     event_type VARCHAR(50) NOT NULL,
+     payload JSON NOT NULL,
+     status VARCHAR(20) NOT NULL,
+     attempts INT NOT NULL DEFAULT 0,
+     last_error TEXT NULL,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+     PRIMARY KEY (id),
+     INDEX idx_webhook_delivery_status (status, id),
+     CONSTRAINT fk_webhook_delivery_webhook FOREIGN KEY (webhook_id)
+          REFERENCES webhooks (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
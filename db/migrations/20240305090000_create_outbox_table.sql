@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox (
+     id BIGINT NOT NULL AUTO_INCREMENT,
+     event_id VARCHAR(36) NOT NULL,
+     event_type VARCHAR(50) NOT NULL,
+     aggregate_code VARCHAR(80) NOT NULL,
+     payload JSON NOT NULL,
+     attempts INT NOT NULL DEFAULT 0,
+     last_error TEXT NULL,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     delivered_at TIMESTAMP NULL,
+     PRIMARY KEY (id),
+     CONSTRAINT ukey_outbox_event_id UNIQUE (event_id),
+     INDEX idx_outbox_pending (delivered_at, id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
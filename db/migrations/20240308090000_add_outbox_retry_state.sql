@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE outbox ADD COLUMN next_attempt_at BIGINT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE outbox ADD COLUMN dead_at TIMESTAMP NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE outbox DROP COLUMN dead_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE outbox DROP COLUMN next_attempt_at;
+-- +goose StatementEnd
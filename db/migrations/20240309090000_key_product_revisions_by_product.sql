@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE product_revisions ADD COLUMN product_id BIGINT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE product_revisions SET product_id = CAST(JSON_UNQUOTE(JSON_EXTRACT(data, '$.id')) AS SIGNED);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE product_revisions
+     MODIFY COLUMN product_id BIGINT NOT NULL,
+     DROP INDEX ukey_product_revision,
+     ADD CONSTRAINT ukey_product_id_revision UNIQUE (product_id, revision);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE product_revisions
+     DROP INDEX ukey_product_id_revision,
+     ADD CONSTRAINT ukey_product_revision UNIQUE (product_code, revision),
+     DROP COLUMN product_id;
+-- +goose StatementEnd
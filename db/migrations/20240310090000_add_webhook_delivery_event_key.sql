@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE UNIQUE INDEX ukey_webhook_delivery_event ON webhook_deliveries (webhook_id, event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX ukey_webhook_delivery_event ON webhook_deliveries;
+-- +goose StatementEnd
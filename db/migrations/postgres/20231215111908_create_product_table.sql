@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE products (
+     id BIGSERIAL NOT NULL,
+     title VARCHAR(100) NOT NULL,
+     code VARCHAR(80) NOT NULL,
+     description TEXT NOT NULL,
+     price_in_cents BIGINT NOT NULL CHECK (price_in_cents >= 0),
+     reference VARCHAR(255) NOT NULL,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     deleted_at TIMESTAMP NULL DEFAULT NULL,
+     PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX ukey_product_active_code ON products (LOWER(code)) WHERE deleted_at IS NULL;
+CREATE INDEX idx_product_deleted_at ON products (deleted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS products;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE outbox (
+     id BIGSERIAL NOT NULL,
+     event_id VARCHAR(36) NOT NULL,
+     event_type VARCHAR(50) NOT NULL,
+     aggregate_code VARCHAR(80) NOT NULL,
+     payload JSONB NOT NULL,
+     attempts INT NOT NULL DEFAULT 0,
+     last_error TEXT NULL,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     delivered_at TIMESTAMP NULL,
+     PRIMARY KEY (id),
+     CONSTRAINT ukey_outbox_event_id UNIQUE (event_id)
+);
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE delivered_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE product_audit (
+     id BIGSERIAL NOT NULL,
+     product_code VARCHAR(80) NOT NULL,
+     operation VARCHAR(20) NOT NULL,
+     actor VARCHAR(255) NOT NULL,
+     before_data JSONB NULL,
+     after_data JSONB NULL,
+     created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+     PRIMARY KEY (id)
+);
+CREATE INDEX idx_product_audit_code ON product_audit (LOWER(product_code), id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION product_audit_immutable() RETURNS TRIGGER AS $$
+BEGIN
+     RAISE EXCEPTION 'product_audit is immutable';
+END;
+$$ LANGUAGE plpgsql;
+CREATE TRIGGER trg_product_audit_no_update BEFORE UPDATE ON product_audit
+FOR EACH ROW EXECUTE FUNCTION product_audit_immutable();
+CREATE TRIGGER trg_product_audit_no_delete BEFORE DELETE ON product_audit
+FOR EACH ROW EXECUTE FUNCTION product_audit_immutable();
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE product_revisions (
+     id BIGSERIAL NOT NULL,
+     product_id BIGINT NOT NULL,
+     product_code VARCHAR(80) NOT NULL,
+     revision INT NOT NULL CHECK (revision > 0),
+     actor VARCHAR(255) NOT NULL,
+     data JSONB NOT NULL,
+     created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+     PRIMARY KEY (id),
+     CONSTRAINT ukey_product_id_revision UNIQUE (product_id, revision)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO product_revisions (product_id, product_code, revision, actor, data)
+SELECT p.id, p.code, 1, 'migration', jsonb_build_object(
+     'id', p.id,
+     'title', p.title,
+     'description', p.description,
+     'code', p.code,
+     'reference', p.reference,
+     'priceInCents', p.price_in_cents,
+     'createdAt', to_char(p.created_at, 'YYYY-MM-DD HH24:MI:SS'),
+     'updatedAt', to_char(p.updated_at, 'YYYY-MM-DD HH24:MI:SS'))
+FROM products p WHERE p.deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_revisions;
+DROP TABLE IF EXISTS product_audit;
+DROP FUNCTION IF EXISTS product_audit_immutable();
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhooks (
+     id BIGSERIAL NOT NULL,
+     url VARCHAR(2048) NOT NULL,
+     secret VARCHAR(255) NOT NULL,
+     event_types JSONB NOT NULL,
+     active BOOLEAN NOT NULL DEFAULT TRUE,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     PRIMARY KEY (id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE webhook_deliveries (
+     id BIGSERIAL NOT NULL,
+     webhook_id BIGINT NOT NULL,
+     event_id VARCHAR(36) NOT NULL,
+     event_type VARCHAR(50) NOT NULL,
+     payload JSONB NOT NULL,
+     status VARCHAR(20) NOT NULL,
+     attempts INT NOT NULL DEFAULT 0,
+     last_error TEXT NULL,
+     next_attempt_at BIGINT NOT NULL DEFAULT 0,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     PRIMARY KEY (id),
+     CONSTRAINT fk_webhook_delivery_webhook FOREIGN KEY (webhook_id)
+          REFERENCES webhooks (id) ON DELETE CASCADE,
+     CONSTRAINT ukey_webhook_delivery_event UNIQUE (webhook_id, event_id)
+);
+CREATE INDEX idx_webhook_delivery_status ON webhook_deliveries (status, id);
+CREATE INDEX idx_webhook_delivery_due ON webhook_deliveries (status, next_attempt_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
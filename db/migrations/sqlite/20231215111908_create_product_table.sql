@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE products (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     title VARCHAR(100) NOT NULL,
+     code VARCHAR(80) NOT NULL,
+     description TEXT NOT NULL,
+     price_in_cents INTEGER NOT NULL CHECK (price_in_cents >= 0),
+     reference VARCHAR(255) NOT NULL,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     deleted_at TIMESTAMP NULL DEFAULT NULL
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE UNIQUE INDEX ukey_product_active_code ON products (LOWER(code)) WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_product_deleted_at ON products (deleted_at);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER trg_products_updated_at AFTER UPDATE OF title, description, reference, price_in_cents, deleted_at
+ON products FOR EACH ROW
+BEGIN
+     UPDATE products SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS products;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE product_audit (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     product_code VARCHAR(80) NOT NULL,
+     operation VARCHAR(20) NOT NULL,
+     actor VARCHAR(255) NOT NULL,
+     before_data TEXT NULL,
+     after_data TEXT NULL,
+     created_at TIMESTAMP NOT NULL DEFAULT (STRFTIME('%Y-%m-%d %H:%M:%f', 'now'))
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_product_audit_code ON product_audit (product_code, id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER trg_product_audit_no_update BEFORE UPDATE ON product_audit
+BEGIN
+     SELECT RAISE(ABORT, 'product_audit is immutable');
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER trg_product_audit_no_delete BEFORE DELETE ON product_audit
+BEGIN
+     SELECT RAISE(ABORT, 'product_audit is immutable');
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_audit;
+-- +goose StatementEnd
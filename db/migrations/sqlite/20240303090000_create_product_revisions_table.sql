@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE product_revisions (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     product_code VARCHAR(80) NOT NULL,
+     revision INTEGER NOT NULL CHECK (revision > 0),
+     actor VARCHAR(255) NOT NULL,
+     data TEXT NOT NULL,
+     created_at TIMESTAMP NOT NULL DEFAULT (STRFTIME('%Y-%m-%d %H:%M:%f', 'now')),
+     CONSTRAINT ukey_product_revision UNIQUE (product_code, revision)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS product_revisions;
+-- +goose StatementEnd
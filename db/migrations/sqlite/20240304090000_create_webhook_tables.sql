@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhooks (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     url VARCHAR(2048) NOT NULL,
+     secret VARCHAR(255) NOT NULL,
+     event_types TEXT NOT NULL,
+     active BOOLEAN NOT NULL DEFAULT TRUE,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER trg_webhooks_updated_at AFTER UPDATE OF url, secret, event_types, active
+ON webhooks FOR EACH ROW
+BEGIN
+     UPDATE webhooks SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TABLE webhook_deliveries (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     webhook_id INTEGER NOT NULL,
+     event_id VARCHAR(36) NOT NULL,
+     event_type VARCHAR(50) NOT NULL,
+     payload TEXT NOT NULL,
+     status VARCHAR(20) NOT NULL,
+     attempts INTEGER NOT NULL DEFAULT 0,
+     last_error TEXT NULL,
+     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+     CONSTRAINT fk_webhook_delivery_webhook FOREIGN KEY (webhook_id)
+          REFERENCES webhooks (id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_webhook_delivery_status ON webhook_deliveries (status, id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE TRIGGER trg_webhook_deliveries_updated_at AFTER UPDATE OF status, attempts, last_error
+ON webhook_deliveries FOR EACH ROW
+BEGIN
+     UPDATE webhook_deliveries SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
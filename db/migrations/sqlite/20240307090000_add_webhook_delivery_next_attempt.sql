@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries ADD COLUMN next_attempt_at INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX idx_webhook_delivery_due ON webhook_deliveries (status, next_attempt_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_webhook_delivery_due;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE webhook_deliveries DROP COLUMN next_attempt_at;
+-- +goose StatementEnd
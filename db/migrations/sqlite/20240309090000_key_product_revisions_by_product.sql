@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE product_revisions_by_product (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     product_id INTEGER NOT NULL,
+     product_code VARCHAR(80) NOT NULL,
+     revision INTEGER NOT NULL CHECK (revision > 0),
+     actor VARCHAR(255) NOT NULL,
+     data TEXT NOT NULL,
+     created_at TIMESTAMP NOT NULL DEFAULT (STRFTIME('%Y-%m-%d %H:%M:%f', 'now')),
+     CONSTRAINT ukey_product_id_revision UNIQUE (product_id, revision)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO product_revisions_by_product (id, product_id, product_code, revision, actor, data, created_at)
+SELECT id, JSON_EXTRACT(data, '$.id'), product_code, revision, actor, data, created_at
+FROM product_revisions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE product_revisions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE product_revisions_by_product RENAME TO product_revisions;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE product_revisions_by_code (
+     id INTEGER PRIMARY KEY AUTOINCREMENT,
+     product_code VARCHAR(80) NOT NULL,
+     revision INTEGER NOT NULL CHECK (revision > 0),
+     actor VARCHAR(255) NOT NULL,
+     data TEXT NOT NULL,
+     created_at TIMESTAMP NOT NULL DEFAULT (STRFTIME('%Y-%m-%d %H:%M:%f', 'now')),
+     CONSTRAINT ukey_product_revision UNIQUE (product_code, revision)
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+INSERT INTO product_revisions_by_code (id, product_code, revision, actor, data, created_at)
+SELECT id, product_code, revision, actor, data, created_at FROM product_revisions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+DROP TABLE product_revisions;
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE product_revisions_by_code RENAME TO product_revisions;
+-- +goose StatementEnd
@@ -10,4 +10,18 @@ var (
 	InvalidPriceErr          = fmt.Errorf("price is invalid")
 	DuplicatedProductCodeErr = fmt.Errorf("a product with this code already exists")
 	ProductNotFoundErr       = fmt.Errorf("product doesn't exists")
+	InvalidPaginationErr     = fmt.Errorf("pagination is invalid")
+	InvalidPriceRangeErr     = fmt.Errorf("price range is invalid")
+	UnsupportedCurrencyErr   = fmt.Errorf("currency is not supported")
+	InvalidFieldErr          = fmt.Errorf("field is invalid")
+	InvalidRevisionErr       = fmt.Errorf("revision is invalid")
+	RevisionNotFoundErr      = fmt.Errorf("product revision doesn't exists")
+	InvalidWebhookURLErr     = fmt.Errorf("webhook url is invalid")
+	PrivateWebhookTargetErr  = fmt.Errorf("webhook url targets a private or reserved address")
+	RequiredWebhookSecretErr = fmt.Errorf("webhook secret is required")
+	InvalidEventTypeErr      = fmt.Errorf("event type is invalid")
+	WebhookNotFoundErr       = fmt.Errorf("webhook doesn't exists")
+	DeliveryNotFoundErr      = fmt.Errorf("webhook delivery doesn't exists")
+	DuplicatedDeliveryErr    = fmt.Errorf("a webhook delivery for this event already exists")
+	InvalidLastEventIDErr    = fmt.Errorf("last event id is invalid")
 )
@@ -0,0 +1,15 @@
+package entity
+
+const (
+	ProductCreatedEvent  = "product.created"
+	ProductUpdatedEvent  = "product.updated"
+	ProductDeletedEvent  = "product.deleted"
+	ProductRestoredEvent = "product.restored"
+)
+
+var ProductEventTypes = []string{
+	ProductCreatedEvent,
+	ProductUpdatedEvent,
+	ProductDeletedEvent,
+	ProductRestoredEvent,
+}
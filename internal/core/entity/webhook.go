@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+var reservedWebhookNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+type Webhook struct {
+	URL             string
+	Secret          string
+	EventTypes      []string
+	ID              int64
+	Active          bool
+	AllowedNetworks []netip.Prefix
+}
+
+func NewWebhook() *Webhook {
+	return &Webhook{Active: true}
+}
+
+func (w Webhook) IsValid() error {
+	parsedURL, err := url.Parse(w.URL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return InvalidWebhookURLErr
+	}
+	if !permitsWebhookHost(parsedURL.Hostname(), w.AllowedNetworks) {
+		return PrivateWebhookTargetErr
+	}
+	if isEmpty(w.Secret) {
+		return RequiredWebhookSecretErr
+	}
+	for _, eventType := range w.EventTypes {
+		if !slices.Contains(ProductEventTypes, eventType) {
+			return InvalidEventTypeErr
+		}
+	}
+	return nil
+}
+
+func (w Webhook) Accepts(eventType string) bool {
+	return w.Active && (len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType))
+}
+
+// PermitsWebhookTarget reports whether a webhook may be delivered to addr.
+// Loopback, private, link-local and other non-public addresses are refused
+// unless they fall inside one of the allowed networks.
+func PermitsWebhookTarget(addr netip.Addr, allowedNetworks []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, network := range allowedNetworks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, network := range reservedWebhookNetworks {
+		if network.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func permitsWebhookHost(host string, allowedNetworks []netip.Prefix) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return PermitsWebhookTarget(netip.IPv6Loopback(), allowedNetworks) &&
+			PermitsWebhookTarget(netip.AddrFrom4([4]byte{127, 0, 0, 1}), allowedNetworks)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return true
+	}
+	return PermitsWebhookTarget(addr, allowedNetworks)
+}
@@ -0,0 +1,74 @@
+package entity_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook_IsValid(t *testing.T) {
+	t.Run("Should validate with success a webhook", webhookIsValid)
+	t.Run("Should results error if url is not http", webhookURLInvalid)
+	t.Run("Should results error if secret is empty", webhookSecretEmpty)
+	t.Run("Should results error if event type is unknown", webhookEventTypeInvalid)
+	t.Run("Should results error if url targets a private address", webhookPrivateTarget)
+	t.Run("Should validate private targets inside allowed networks", webhookAllowedNetwork)
+}
+
+func validWebhook() *entity.Webhook {
+	webhook := entity.NewWebhook()
+	webhook.URL = "https://indexer.eulabs.com/hooks/products"
+	webhook.Secret = "s3cr3t"
+	webhook.EventTypes = []string{entity.ProductCreatedEvent, entity.ProductDeletedEvent}
+	return webhook
+}
+
+func webhookIsValid(t *testing.T) {
+	webhook := validWebhook()
+	assert.Nil(t, webhook.IsValid())
+	assert.True(t, webhook.Accepts(entity.ProductCreatedEvent))
+	assert.False(t, webhook.Accepts(entity.ProductUpdatedEvent))
+}
+
+func webhookURLInvalid(t *testing.T) {
+	webhook := validWebhook()
+	webhook.URL = "ftp://indexer.eulabs.com"
+	assert.EqualError(t, webhook.IsValid(), entity.InvalidWebhookURLErr.Error())
+}
+
+func webhookSecretEmpty(t *testing.T) {
+	webhook := validWebhook()
+	webhook.Secret = " "
+	assert.EqualError(t, webhook.IsValid(), entity.RequiredWebhookSecretErr.Error())
+}
+
+func webhookEventTypeInvalid(t *testing.T) {
+	webhook := validWebhook()
+	webhook.EventTypes = []string{"product.archived"}
+	assert.EqualError(t, webhook.IsValid(), entity.InvalidEventTypeErr.Error())
+}
+
+func webhookPrivateTarget(t *testing.T) {
+	for _, url := range []string{
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.7/hooks",
+		"http://192.168.1.10:8080/hooks",
+		"http://127.0.0.1/hooks",
+		"http://[::1]/hooks",
+		"http://[::ffff:172.16.0.1]/hooks",
+		"http://localhost:8080/hooks",
+	} {
+		webhook := validWebhook()
+		webhook.URL = url
+		assert.EqualError(t, webhook.IsValid(), entity.PrivateWebhookTargetErr.Error(), url)
+	}
+}
+
+func webhookAllowedNetwork(t *testing.T) {
+	webhook := validWebhook()
+	webhook.URL = "http://10.0.0.7/hooks"
+	webhook.AllowedNetworks = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")}
+	assert.Nil(t, webhook.IsValid())
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type OutboxEventData struct {
+	ID            int64
+	EventID       string
+	EventType     string
+	AggregateCode string
+	Payload       []byte
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     string
+	DeliveredAt   string
+	DeadAt        string
+}
+
+type OutboxRepository interface {
+	Insert(ctx context.Context, in OutboxEventData) (OutboxEventData, error)
+	ListPending(ctx context.Context, limit int) ([]OutboxEventData, error)
+	ClaimPending(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]OutboxEventData, error)
+	Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time) error
+	MarkDelivered(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, lastError string, nextAttemptAt time.Time) error
+	MarkDead(ctx context.Context, id int64, lastError string) error
+}
@@ -1,6 +1,31 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+const (
+	ProductFieldID           = "id"
+	ProductFieldTitle        = "title"
+	ProductFieldDescription  = "description"
+	ProductFieldCode         = "code"
+	ProductFieldReference    = "reference"
+	ProductFieldPriceInCents = "priceInCents"
+	ProductFieldCreatedAt    = "createdAt"
+	ProductFieldUpdatedAt    = "updatedAt"
+)
+
+var ProductFields = []string{
+	ProductFieldID,
+	ProductFieldTitle,
+	ProductFieldDescription,
+	ProductFieldCode,
+	ProductFieldReference,
+	ProductFieldPriceInCents,
+	ProductFieldCreatedAt,
+	ProductFieldUpdatedAt,
+}
 
 type ProductRepositoryData struct {
 	Title        string
@@ -9,6 +34,7 @@ type ProductRepositoryData struct {
 	Reference    string
 	CreatedAt    string
 	UpdatedAt    string
+	DeletedAt    string
 	PriceInCents int64
 	ID           int64
 }
@@ -18,12 +44,29 @@ type ProductRepositoryInput struct {
 	Description  string
 	Code         string
 	Reference    string
+	Operation    string
 	PriceInCents int64
 }
 
+type ProductRepositoryFilter struct {
+	Code            string
+	Title           string
+	MinPriceInCents int64
+	MaxPriceInCents int64
+	Offset          int
+	Limit           int
+	Fields          []string
+}
+
 type ProductRepository interface {
 	Insert(ctx context.Context, in ProductRepositoryInput) (ProductRepositoryData, error)
 	GetByCode(ctx context.Context, code string) (ProductRepositoryData, error)
+	GetByCodeWithFields(ctx context.Context, code string, fields []string) (ProductRepositoryData, error)
 	DeleteByCode(ctx context.Context, code string) (bool, error)
 	Update(ctx context.Context, in ProductRepositoryInput) error
+	List(ctx context.Context, filter ProductRepositoryFilter) ([]ProductRepositoryData, int64, error)
+	GetByCodes(ctx context.Context, codes []string) ([]ProductRepositoryData, error)
+	RestoreByCode(ctx context.Context, code string) error
+	ListDeleted(ctx context.Context, filter ProductRepositoryFilter) ([]ProductRepositoryData, int64, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
@@ -0,0 +1,32 @@
+package repository
+
+import "context"
+
+const (
+	ProductAuditOperationCreate  = "create"
+	ProductAuditOperationUpdate  = "update"
+	ProductAuditOperationDelete  = "delete"
+	ProductAuditOperationRestore = "restore"
+	ProductAuditOperationRevert  = "revert"
+)
+
+type ProductAuditData struct {
+	ID          int64
+	ProductCode string
+	Operation   string
+	Actor       string
+	Before      *ProductRepositoryData
+	After       *ProductRepositoryData
+	CreatedAt   string
+}
+
+type ProductAuditFilter struct {
+	Code   string
+	Offset int
+	Limit  int
+}
+
+type ProductAuditRepository interface {
+	Insert(ctx context.Context, in ProductAuditData) error
+	ListByCode(ctx context.Context, filter ProductAuditFilter) ([]ProductAuditData, int64, error)
+}
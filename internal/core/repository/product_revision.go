@@ -0,0 +1,17 @@
+package repository
+
+import "context"
+
+type ProductRevisionData struct {
+	ProductID   int64
+	ProductCode string
+	Revision    int
+	Actor       string
+	Product     ProductRepositoryData
+	CreatedAt   string
+}
+
+type ProductRevisionRepository interface {
+	Insert(ctx context.Context, in ProductRevisionData) (ProductRevisionData, error)
+	GetByRevision(ctx context.Context, productID int64, revision int) (ProductRevisionData, error)
+}
@@ -0,0 +1,323 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+const timestampLayout = "2006-01-02 15:04:05"
+
+type productRepositoryContract struct {
+	name string
+	run  func(t *testing.T, productRepo repository.ProductRepository)
+}
+
+func RunProductRepositoryContract(t *testing.T,
+	newProductRepository func(t *testing.T) repository.ProductRepository) {
+	contract := []productRepositoryContract{
+		{"Should insert and read back a product", shouldInsertAndReadBackProduct},
+		{"Should reject duplicated codes ignoring case", shouldRejectDuplicatedCodesIgnoringCase},
+		{"Should find products ignoring case and spaces", shouldFindProductsIgnoringCaseAndSpaces},
+		{"Should report missing products as not found", shouldReportMissingProductsAsNotFound},
+		{"Should keep timestamps consistent across writes", shouldKeepTimestampsConsistent},
+		{"Should stop on a canceled context", shouldStopOnCanceledContext},
+		{"Should soft delete, restore and purge products", shouldSoftDeleteRestoreAndPurgeProducts},
+		{"Should filter and paginate listings", shouldFilterAndPaginateListings},
+	}
+	for _, clause := range contract {
+		t.Run(clause.name, func(t *testing.T) {
+			clause.run(t, newProductRepository(t))
+		})
+	}
+}
+
+func newProductInput(code string) repository.ProductRepositoryInput {
+	return repository.ProductRepositoryInput{
+		Title:        "Toy",
+		Description:  "Blahahhs",
+		Code:         code,
+		Reference:    "RF009-pods74",
+		PriceInCents: int64(51400),
+	}
+}
+
+func insertProduct(t *testing.T, productRepo repository.ProductRepository,
+	in repository.ProductRepositoryInput) repository.ProductRepositoryData {
+	product, err := productRepo.Insert(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return product
+}
+
+func parseTimestamp(t *testing.T, value string) time.Time {
+	parsed, err := time.Parse(timestampLayout, value)
+	if err != nil {
+		t.Fatalf("timestamp %q does not match %q: %v", value, timestampLayout, err)
+	}
+	return parsed
+}
+
+func productCodes(products []repository.ProductRepositoryData) []string {
+	codes := make([]string, 0, len(products))
+	for _, product := range products {
+		codes = append(codes, product.Code)
+	}
+	return codes
+}
+
+func shouldInsertAndReadBackProduct(t *testing.T, productRepo repository.ProductRepository) {
+	ctx := context.Background()
+
+	inserted, err := productRepo.Insert(ctx, newProductInput("XSZ-000741"))
+	assert.Nil(t, err)
+	assert.Positive(t, inserted.ID)
+	assert.Equal(t, "RF009-pods74", inserted.Reference)
+
+	product, err := productRepo.GetByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+	assert.Equal(t, inserted.ID, product.ID)
+	assert.Equal(t, "Toy", product.Title)
+	assert.Equal(t, "Blahahhs", product.Description)
+	assert.Equal(t, "XSZ-000741", product.Code)
+	assert.Equal(t, "RF009-pods74", product.Reference)
+	assert.Equal(t, int64(51400), product.PriceInCents)
+	assert.Equal(t, inserted.CreatedAt, product.CreatedAt)
+	assert.Equal(t, inserted.UpdatedAt, product.UpdatedAt)
+	assert.Empty(t, product.DeletedAt)
+
+	second := insertProduct(t, productRepo, newProductInput("XSZ-000742"))
+	assert.Greater(t, second.ID, inserted.ID)
+}
+
+func shouldRejectDuplicatedCodesIgnoringCase(t *testing.T, productRepo repository.ProductRepository) {
+	ctx := context.Background()
+	insertProduct(t, productRepo, newProductInput("XSZ-000741"))
+
+	_, err := productRepo.Insert(ctx, newProductInput("XSZ-000741"))
+	assert.Equal(t, entity.DuplicatedProductCodeErr, err)
+	_, err = productRepo.Insert(ctx, newProductInput("xsz-000741"))
+	assert.Equal(t, entity.DuplicatedProductCodeErr, err)
+	_, err = productRepo.Insert(ctx, newProductInput("Xsz-000742"))
+	assert.Nil(t, err)
+	product, err := productRepo.GetByCode(ctx, "XSZ-000742")
+	assert.Nil(t, err)
+	assert.Equal(t, "Xsz-000742", product.Code)
+
+	_, total, err := productRepo.List(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+}
+
+func shouldFindProductsIgnoringCaseAndSpaces(t *testing.T, productRepo repository.ProductRepository) {
+	ctx := context.Background()
+	insertProduct(t, productRepo, newProductInput("XSZ-000741"))
+
+	for _, code := range []string{"xsz-000741", " XSZ - 000741 ", "xSz-000741"} {
+		product, err := productRepo.GetByCode(ctx, code)
+		assert.Nil(t, err)
+		assert.Equal(t, "XSZ-000741", product.Code)
+		assert.Equal(t, "Toy", product.Title)
+	}
+
+	product, err := productRepo.GetByCodeWithFields(ctx, "xsz - 000741",
+		[]string{repository.ProductFieldTitle})
+	assert.Nil(t, err)
+	assert.Equal(t, "XSZ-000741", product.Code)
+	assert.Equal(t, "Toy", product.Title)
+	assert.Empty(t, product.Description)
+	assert.Zero(t, product.PriceInCents)
+
+	products, err := productRepo.GetByCodes(ctx, []string{"xsz - 000741", "ABC-000001"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"XSZ-000741"}, productCodes(products))
+
+	products, total, err := productRepo.List(ctx, repository.ProductRepositoryFilter{
+		Code: "xsz - 000", Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"XSZ-000741"}, productCodes(products))
+}
+
+func shouldReportMissingProductsAsNotFound(t *testing.T, productRepo repository.ProductRepository) {
+	ctx := context.Background()
+
+	_, err := productRepo.GetByCode(ctx, "XSZ-000741")
+	assert.Equal(t, entity.ProductNotFoundErr, err)
+	_, err = productRepo.GetByCodeWithFields(ctx, "XSZ-000741", []string{repository.ProductFieldTitle})
+	assert.Equal(t, entity.ProductNotFoundErr, err)
+	assert.Equal(t, entity.ProductNotFoundErr, productRepo.Update(ctx, newProductInput("XSZ-000741")))
+	deleted, err := productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Equal(t, entity.ProductNotFoundErr, err)
+	assert.False(t, deleted)
+	assert.Equal(t, entity.ProductNotFoundErr, productRepo.RestoreByCode(ctx, "XSZ-000741"))
+
+	products, err := productRepo.GetByCodes(ctx, []string{"XSZ-000741"})
+	assert.Nil(t, err)
+	assert.Empty(t, products)
+
+	products, total, err := productRepo.List(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, products)
+
+	purged, err := productRepo.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Zero(t, purged)
+}
+
+func shouldKeepTimestampsConsistent(t *testing.T, productRepo repository.ProductRepository) {
+	ctx := context.Background()
+	inserted := insertProduct(t, productRepo, newProductInput("XSZ-000741"))
+
+	createdAt := parseTimestamp(t, inserted.CreatedAt)
+	updatedAt := parseTimestamp(t, inserted.UpdatedAt)
+	assert.False(t, updatedAt.Before(createdAt))
+
+	in := newProductInput("XSZ-000741")
+	in.Title = "Truck"
+	assert.Nil(t, productRepo.Update(ctx, in))
+
+	product, err := productRepo.GetByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+	assert.Equal(t, "Truck", product.Title)
+	assert.Equal(t, inserted.CreatedAt, product.CreatedAt)
+	assert.False(t, parseTimestamp(t, product.UpdatedAt).Before(updatedAt))
+
+	_, err = productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+	trash, _, err := productRepo.ListDeleted(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.Nil(t, err)
+	if assert.Len(t, trash, 1) {
+		assert.False(t, parseTimestamp(t, trash[0].DeletedAt).Before(createdAt))
+	}
+}
+
+func shouldStopOnCanceledContext(t *testing.T, productRepo repository.ProductRepository) {
+	insertProduct(t, productRepo, newProductInput("XSZ-000741"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := productRepo.Insert(ctx, newProductInput("XSZ-000742"))
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = productRepo.GetByCode(ctx, "XSZ-000741")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = productRepo.GetByCodeWithFields(ctx, "XSZ-000741", []string{repository.ProductFieldTitle})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = productRepo.GetByCodes(ctx, []string{"XSZ-000741"})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, _, err = productRepo.List(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, _, err = productRepo.ListDeleted(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	in := newProductInput("XSZ-000741")
+	in.Title = "Truck"
+	assert.ErrorIs(t, productRepo.Update(ctx, in), context.Canceled)
+	_, err = productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, productRepo.RestoreByCode(ctx, "XSZ-000741"), context.Canceled)
+	_, err = productRepo.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	product, err := productRepo.GetByCode(context.Background(), "XSZ-000741")
+	assert.Nil(t, err)
+	assert.Equal(t, "Toy", product.Title)
+	_, err = productRepo.GetByCode(context.Background(), "XSZ-000742")
+	assert.Equal(t, entity.ProductNotFoundErr, err)
+}
+
+func shouldSoftDeleteRestoreAndPurgeProducts(t *testing.T, productRepo repository.ProductRepository) {
+	ctx := context.Background()
+	insertProduct(t, productRepo, newProductInput("XSZ-000741"))
+
+	deleted, err := productRepo.DeleteByCode(ctx, "xsz-000741")
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+	_, err = productRepo.GetByCode(ctx, "XSZ-000741")
+	assert.Equal(t, entity.ProductNotFoundErr, err)
+
+	trash, total, err := productRepo.ListDeleted(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{"XSZ-000741"}, productCodes(trash))
+
+	insertProduct(t, productRepo, newProductInput("XSZ-000741"))
+	assert.Equal(t, entity.DuplicatedProductCodeErr, productRepo.RestoreByCode(ctx, "XSZ-000741"))
+
+	_, err = productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+	assert.Nil(t, productRepo.RestoreByCode(ctx, "xsz - 000741"))
+	_, err = productRepo.GetByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+
+	_, total, err = productRepo.ListDeleted(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	purged, err := productRepo.PurgeDeleted(ctx, time.Now().Add(-time.Hour))
+	assert.Nil(t, err)
+	assert.Zero(t, purged)
+	purged, err = productRepo.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	_, total, err = productRepo.ListDeleted(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Equal(t, entity.ProductNotFoundErr, productRepo.RestoreByCode(ctx, "XSZ-000741"))
+	_, err = productRepo.GetByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+}
+
+func shouldFilterAndPaginateListings(t *testing.T, productRepo repository.ProductRepository) {
+	ctx := context.Background()
+	for _, in := range []repository.ProductRepositoryInput{
+		{Title: "Red car", Description: "Toy car", Code: "ABC-000001", Reference: "ref", PriceInCents: 1000},
+		{Title: "Blue car", Description: "Toy car", Code: "ABC-000002", Reference: "ref", PriceInCents: 2000},
+		{Title: "Red truck", Description: "Toy truck", Code: "XYZ-000001", Reference: "ref", PriceInCents: 3000},
+	} {
+		insertProduct(t, productRepo, in)
+	}
+
+	products, total, err := productRepo.List(ctx, repository.ProductRepositoryFilter{Title: "RED", Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"ABC-000001", "XYZ-000001"}, productCodes(products))
+
+	products, total, err = productRepo.List(ctx, repository.ProductRepositoryFilter{Code: "abc", Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"ABC-000001", "ABC-000002"}, productCodes(products))
+
+	products, total, err = productRepo.List(ctx, repository.ProductRepositoryFilter{
+		MinPriceInCents: 1500, MaxPriceInCents: 3000, Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{"ABC-000002", "XYZ-000001"}, productCodes(products))
+
+	products, total, err = productRepo.List(ctx, repository.ProductRepositoryFilter{Offset: 1, Limit: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []string{"ABC-000002"}, productCodes(products))
+
+	products, total, err = productRepo.List(ctx, repository.ProductRepositoryFilter{Offset: 5, Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Empty(t, products)
+
+	products, _, err = productRepo.List(ctx, repository.ProductRepositoryFilter{Limit: 1,
+		Fields: []string{repository.ProductFieldCode, repository.ProductFieldTitle}})
+	assert.Nil(t, err)
+	if assert.Len(t, products, 1) {
+		assert.Equal(t, "ABC-000001", products[0].Code)
+		assert.Equal(t, "Red car", products[0].Title)
+		assert.Empty(t, products[0].Description)
+		assert.Zero(t, products[0].PriceInCents)
+	}
+}
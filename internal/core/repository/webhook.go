@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+type WebhookData struct {
+	ID         int64
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	CreatedAt  string
+	UpdatedAt  string
+}
+
+type WebhookInput struct {
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+}
+
+type WebhookDeliveryData struct {
+	ID            int64
+	WebhookID     int64
+	EventID       string
+	EventType     string
+	Payload       []byte
+	Status        string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     string
+	UpdatedAt     string
+}
+
+type WebhookDeliveryFilter struct {
+	Status string
+	Offset int
+	Limit  int
+}
+
+type WebhookRepository interface {
+	Insert(ctx context.Context, in WebhookInput) (WebhookData, error)
+	GetByID(ctx context.Context, id int64) (WebhookData, error)
+	List(ctx context.Context) ([]WebhookData, error)
+	Update(ctx context.Context, id int64, in WebhookInput) (WebhookData, error)
+	DeleteByID(ctx context.Context, id int64) error
+}
+
+type WebhookDeliveryRepository interface {
+	Insert(ctx context.Context, in WebhookDeliveryData) (WebhookDeliveryData, error)
+	GetByID(ctx context.Context, id int64) (WebhookDeliveryData, error)
+	Update(ctx context.Context, in WebhookDeliveryData) error
+	List(ctx context.Context, filter WebhookDeliveryFilter) ([]WebhookDeliveryData, int64, error)
+	ClaimDue(ctx context.Context, now time.Time, leaseUntil time.Time, limit int) ([]WebhookDeliveryData, error)
+}
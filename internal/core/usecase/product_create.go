@@ -15,25 +15,23 @@ const (
 
 type ProductCreate struct {
 	repository repository.ProductRepository
+	options    productOptions
 }
 
 type ProductInputDTO struct {
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	Code         string `json:"code"`
-	Reference    string `json:"reference"`
-	PriceInCents int64  `json:"priceInCents"`
+	Title        string `json:"title" xml:"title"`
+	Description  string `json:"description" xml:"description"`
+	Code         string `json:"code" xml:"code"`
+	Reference    string `json:"reference" xml:"reference"`
+	PriceInCents int64  `json:"priceInCents" xml:"priceInCents"`
 }
 
-type ProductOutputDTO struct {
-	CreatedAt string `json:"createdAt"`
-	Reference string `json:"reference"`
-	ID        int64  `json:"id"`
-}
+type ProductOutputDTO = ProductGetOutputDTO
 
-func NewProductCreate(productRepo repository.ProductRepository) *ProductCreate {
+func NewProductCreate(productRepo repository.ProductRepository, opts ...ProductOption) *ProductCreate {
 	return &ProductCreate{
 		repository: productRepo,
+		options:    newProductOptions(opts...),
 	}
 }
 
@@ -43,7 +41,7 @@ func (p *ProductCreate) Execute(ctx context.Context, input ProductInputDTO) (Pro
 		return ProductOutputDTO{}, err
 	}
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.options.timeouts.Create)
 	defer cancel()
 
 	productData, err := p.repository.Insert(ctxWithTimeout, repository.ProductRepositoryInput{
@@ -59,11 +57,19 @@ func (p *ProductCreate) Execute(ctx context.Context, input ProductInputDTO) (Pro
 		return ProductOutputDTO{}, err
 	}
 
-	return ProductOutputDTO{
-		CreatedAt: productData.CreatedAt,
-		Reference: productData.Reference,
-		ID:        productData.ID,
-	}, nil
+	createdData := repository.ProductRepositoryData{
+		ID:           productData.ID,
+		Title:        input.Title,
+		Description:  input.Description,
+		Code:         input.Code,
+		Reference:    productData.Reference,
+		PriceInCents: input.PriceInCents,
+		CreatedAt:    productData.CreatedAt,
+		UpdatedAt:    productData.UpdatedAt,
+	}
+	p.options.emit(ctxWithTimeout, repository.ProductAuditOperationCreate, input.Code, createdData)
+
+	return toProductGetOutputDTO(createdData), nil
 }
 
 func validate(input ProductInputDTO) error {
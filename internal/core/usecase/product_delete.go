@@ -3,29 +3,37 @@ package usecase
 import (
 	"context"
 	"log/slog"
-	"time"
 
 	"github.com/lbsti/eulabs-challenge/internal/core/repository"
 )
 
 type ProductDelete struct {
 	repository repository.ProductRepository
+	options    productOptions
 }
 
-func NewProductDelete(productRepo repository.ProductRepository) *ProductDelete {
+func NewProductDelete(productRepo repository.ProductRepository, opts ...ProductOption) *ProductDelete {
 	return &ProductDelete{
 		repository: productRepo,
+		options:    newProductOptions(opts...),
 	}
 }
 
 func (p *ProductDelete) Execute(ctx context.Context, code string) (bool, error) {
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	ctxWithTimeout, cancel := context.WithTimeout(WithPrimaryRead(ctx), p.options.timeouts.Delete)
 	defer cancel()
-	isDeleted, err := p.repository.DeleteByCode(ctxWithTimeout, code)
 
+	productData, err := p.repository.GetByCode(ctxWithTimeout, code)
+	if err != nil {
+		slog.Error("impossible to delete product", slog.Any("msg", err))
+		return false, err
+	}
+
+	isDeleted, err := p.repository.DeleteByCode(ctxWithTimeout, code)
 	if err != nil {
 		slog.Error("impossible to delete product", slog.Any("msg", err))
 		return false, err
 	}
+	p.options.emit(ctxWithTimeout, repository.ProductAuditOperationDelete, productData.Code, productData)
 	return isDeleted, nil
 }
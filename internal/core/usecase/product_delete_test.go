@@ -16,7 +16,7 @@ func TestProductDelete_Execute(t *testing.T) {
 }
 
 func productDeleteSuccess(t *testing.T) {
-	productRepoInMemory := repository.NewProductRepositoryInMemory()
+	productRepoInMemory := newProductRepositoryWith(t, "XSZ-000741")
 	productDelete := usecase.NewProductDelete(productRepoInMemory)
 
 	isDeleted, err := productDelete.Execute(context.TODO(), "XSZ-000741")
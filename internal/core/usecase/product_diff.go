@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	DiffLineEqual  = "equal"
+	DiffLineInsert = "insert"
+	DiffLineDelete = "delete"
+)
+
+type ProductDiff struct {
+	repository repository.ProductRepository
+	options    productOptions
+}
+
+type ProductDiffInputDTO struct {
+	Code string
+	From int
+	To   int
+}
+
+type ProductFieldChangeDTO struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type ProductDiffLineDTO struct {
+	Operation string `json:"op"`
+	Text      string `json:"text"`
+}
+
+type ProductDiffOutputDTO struct {
+	Code            string                  `json:"code"`
+	From            int                     `json:"from"`
+	To              int                     `json:"to"`
+	Changes         []ProductFieldChangeDTO `json:"changes"`
+	DescriptionDiff []ProductDiffLineDTO    `json:"descriptionDiff"`
+}
+
+func NewProductDiff(productRepo repository.ProductRepository, opts ...ProductOption) *ProductDiff {
+	return &ProductDiff{
+		repository: productRepo,
+		options:    newProductOptions(opts...),
+	}
+}
+
+func (p *ProductDiff) Execute(ctx context.Context, input ProductDiffInputDTO) (ProductDiffOutputDTO, error) {
+	if input.From < 1 || input.To < 1 {
+		return ProductDiffOutputDTO{}, entity.InvalidRevisionErr
+	}
+	if p.options.revisionRepository == nil {
+		return ProductDiffOutputDTO{}, entity.RevisionNotFoundErr
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.options.timeouts.Get)
+	defer cancel()
+
+	productData, err := p.repository.GetByCode(ctxWithTimeout, input.Code)
+	if err != nil {
+		slog.Error("impossible to diff product revisions", slog.Any("msg", err))
+		return ProductDiffOutputDTO{}, err
+	}
+	from, err := p.options.revisionRepository.GetByRevision(ctxWithTimeout, productData.ID, input.From)
+	if err != nil {
+		slog.Error("impossible to get product revision", slog.Any("msg", err))
+		return ProductDiffOutputDTO{}, err
+	}
+	to, err := p.options.revisionRepository.GetByRevision(ctxWithTimeout, productData.ID, input.To)
+	if err != nil {
+		slog.Error("impossible to get product revision", slog.Any("msg", err))
+		return ProductDiffOutputDTO{}, err
+	}
+
+	descriptionDiff, err := diffLines(ctxWithTimeout, from.Product.Description, to.Product.Description)
+	if err != nil {
+		slog.Error("impossible to diff product descriptions", slog.Any("msg", err))
+		return ProductDiffOutputDTO{}, err
+	}
+
+	return ProductDiffOutputDTO{
+		Code:            to.ProductCode,
+		From:            from.Revision,
+		To:              to.Revision,
+		Changes:         diffFields(from.Product, to.Product),
+		DescriptionDiff: descriptionDiff,
+	}, nil
+}
+
+func diffFields(from, to repository.ProductRepositoryData) []ProductFieldChangeDTO {
+	fields := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{repository.ProductFieldTitle, from.Title, to.Title},
+		{repository.ProductFieldDescription, from.Description, to.Description},
+		{repository.ProductFieldCode, from.Code, to.Code},
+		{repository.ProductFieldReference, from.Reference, to.Reference},
+		{repository.ProductFieldPriceInCents, from.PriceInCents, to.PriceInCents},
+	}
+	changes := []ProductFieldChangeDTO{}
+	for _, field := range fields {
+		if field.name == repository.ProductFieldCode && sameProductCode(from.Code, to.Code) {
+			continue
+		}
+		if field.old != field.new {
+			changes = append(changes, ProductFieldChangeDTO{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+	return changes
+}
+
+// sameProductCode matches codes the way lookups do, so snapshots taken with
+// the caller's casing of the same code are not reported as a change.
+func sameProductCode(from, to string) bool {
+	return strings.EqualFold(strings.ReplaceAll(from, " ", ""), strings.ReplaceAll(to, " ", ""))
+}
+
+// diffLines compares descriptions line by line with Myers' bisection, which
+// keeps memory linear in the number of lines. The context bounds the time
+// spent on descriptions with many differences.
+func diffLines(ctx context.Context, from, to string) ([]ProductDiffLineDTO, error) {
+	differ := lineDiffer{
+		ctx:      ctx,
+		oldLines: strings.Split(from, "\n"),
+		newLines: strings.Split(to, "\n"),
+		lines:    []ProductDiffLineDTO{},
+	}
+	if err := differ.diff(0, len(differ.oldLines), 0, len(differ.newLines)); err != nil {
+		return nil, err
+	}
+	return differ.lines, nil
+}
+
+type lineDiffer struct {
+	ctx      context.Context
+	oldLines []string
+	newLines []string
+	lines    []ProductDiffLineDTO
+}
+
+func (d *lineDiffer) diff(oldStart, oldEnd, newStart, newEnd int) error {
+	for oldStart < oldEnd && newStart < newEnd && d.oldLines[oldStart] == d.newLines[newStart] {
+		d.emit(DiffLineEqual, d.oldLines[oldStart])
+		oldStart++
+		newStart++
+	}
+	suffix := 0
+	for oldEnd-suffix > oldStart && newEnd-suffix > newStart &&
+		d.oldLines[oldEnd-suffix-1] == d.newLines[newEnd-suffix-1] {
+		suffix++
+	}
+	oldEnd -= suffix
+	newEnd -= suffix
+
+	switch {
+	case oldStart == oldEnd:
+		for _, line := range d.newLines[newStart:newEnd] {
+			d.emit(DiffLineInsert, line)
+		}
+	case newStart == newEnd:
+		for _, line := range d.oldLines[oldStart:oldEnd] {
+			d.emit(DiffLineDelete, line)
+		}
+	default:
+		oldSplit, newSplit, err := d.bisect(oldStart, oldEnd, newStart, newEnd)
+		if err != nil {
+			return err
+		}
+		if err := d.diff(oldStart, oldSplit, newStart, newSplit); err != nil {
+			return err
+		}
+		if err := d.diff(oldSplit, oldEnd, newSplit, newEnd); err != nil {
+			return err
+		}
+	}
+
+	for _, line := range d.oldLines[oldEnd : oldEnd+suffix] {
+		d.emit(DiffLineEqual, line)
+	}
+	return nil
+}
+
+// bisect finds the middle snake of the shortest edit script between the two
+// ranges by walking it forward from the start and backward from the end.
+func (d *lineDiffer) bisect(oldStart, oldEnd, newStart, newEnd int) (int, int, error) {
+	oldLen, newLen := oldEnd-oldStart, newEnd-newStart
+	maxD := (oldLen + newLen + 1) / 2
+	offset := maxD
+	forward := make([]int, 2*maxD+2)
+	backward := make([]int, 2*maxD+2)
+	for index := range forward {
+		forward[index], backward[index] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := oldLen - newLen
+	front := delta%2 != 0
+	var forwardStart, forwardEnd, backwardStart, backwardEnd int
+
+	for step := 0; step < maxD; step++ {
+		if err := d.ctx.Err(); err != nil {
+			return 0, 0, err
+		}
+		for k := -step + forwardStart; k <= step-forwardEnd; k += 2 {
+			index := offset + k
+			var x int
+			if k == -step || (k != step && forward[index-1] < forward[index+1]) {
+				x = forward[index+1]
+			} else {
+				x = forward[index-1] + 1
+			}
+			y := x - k
+			for x < oldLen && y < newLen && d.oldLines[oldStart+x] == d.newLines[newStart+y] {
+				x++
+				y++
+			}
+			forward[index] = x
+			switch {
+			case x > oldLen:
+				forwardEnd += 2
+			case y > newLen:
+				forwardStart += 2
+			case front:
+				reverse := offset + delta - k
+				if reverse >= 0 && reverse < len(backward) && backward[reverse] != -1 &&
+					x >= oldLen-backward[reverse] {
+					return oldStart + x, newStart + y, nil
+				}
+			}
+		}
+		for k := -step + backwardStart; k <= step-backwardEnd; k += 2 {
+			index := offset + k
+			var x int
+			if k == -step || (k != step && backward[index-1] < backward[index+1]) {
+				x = backward[index+1]
+			} else {
+				x = backward[index-1] + 1
+			}
+			y := x - k
+			for x < oldLen && y < newLen &&
+				d.oldLines[oldEnd-x-1] == d.newLines[newEnd-y-1] {
+				x++
+				y++
+			}
+			backward[index] = x
+			switch {
+			case x > oldLen:
+				backwardEnd += 2
+			case y > newLen:
+				backwardStart += 2
+			case !front:
+				reverse := offset + delta - k
+				if reverse >= 0 && reverse < len(forward) && forward[reverse] != -1 {
+					forwardX := forward[reverse]
+					if forwardX >= oldLen-x {
+						return oldStart + forwardX, newStart + forwardX - (reverse - offset), nil
+					}
+				}
+			}
+		}
+	}
+	return oldEnd, newStart, nil
+}
+
+func (d *lineDiffer) emit(operation, text string) {
+	d.lines = append(d.lines, ProductDiffLineDTO{Operation: operation, Text: text})
+}
@@ -0,0 +1,165 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	coreRepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDiffProductRepository(t *testing.T) coreRepository.ProductRepository {
+	productRepo := repository.NewProductRepositoryInMemory()
+	_, err := productRepo.Insert(context.TODO(), coreRepository.ProductRepositoryInput{
+		Title:        "Toy car",
+		Code:         "XSZ-000741",
+		Reference:    "RF009",
+		PriceInCents: 3000,
+	})
+	assert.Nil(t, err)
+	return productRepo
+}
+
+func newDiffRevisionRepository(t *testing.T) coreRepository.ProductRevisionRepository {
+	revisionRepo := repository.NewProductRevisionRepositoryInMemory()
+	for _, product := range []coreRepository.ProductRepositoryData{
+		{Title: "Toy", Code: "XSZ-000741", Reference: "RF009", PriceInCents: 2500,
+			Description: "Red car\nFour wheels\nBattery included"},
+		{Title: "Toy car", Code: "xsz-000741", Reference: "RF009", PriceInCents: 3000,
+			Description: "Red car\nBattery included\nAges 3+"},
+	} {
+		_, err := revisionRepo.Insert(context.TODO(), coreRepository.ProductRevisionData{
+			ProductID:   1,
+			ProductCode: "XSZ-000741",
+			Product:     product,
+		})
+		assert.Nil(t, err)
+	}
+	return revisionRepo
+}
+
+func TestProductDiff_Execute(t *testing.T) {
+	t.Run("Should diff fields and description lines between revisions", productDiffSuccess)
+	t.Run("Should ignore the casing of the code between revisions", productDiffCodeCasing)
+	t.Run("Should results an empty diff for the same revision", productDiffSameRevision)
+	t.Run("Should results a error if revision is invalid", productDiffInvalidRevisionErr)
+	t.Run("Should results a error if revision not found", productDiffNotFoundErr)
+	t.Run("Should diff descriptions with many lines", productDiffManyLines)
+	t.Run("Should not diff revisions of a deleted product reusing the code", productDiffReusedCode)
+}
+
+func productDiffSuccess(t *testing.T) {
+	productDiff := usecase.NewProductDiff(newDiffProductRepository(t),
+		usecase.WithRevisionRepository(newDiffRevisionRepository(t)))
+	outputDTO, err := productDiff.Execute(context.TODO(), usecase.ProductDiffInputDTO{
+		Code: "XSZ-000741",
+		From: 1,
+		To:   2,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []usecase.ProductFieldChangeDTO{
+		{Field: "title", Old: "Toy", New: "Toy car"},
+		{Field: "description", Old: "Red car\nFour wheels\nBattery included",
+			New: "Red car\nBattery included\nAges 3+"},
+		{Field: "priceInCents", Old: int64(2500), New: int64(3000)},
+	}, outputDTO.Changes)
+	assert.Equal(t, []usecase.ProductDiffLineDTO{
+		{Operation: usecase.DiffLineEqual, Text: "Red car"},
+		{Operation: usecase.DiffLineDelete, Text: "Four wheels"},
+		{Operation: usecase.DiffLineEqual, Text: "Battery included"},
+		{Operation: usecase.DiffLineInsert, Text: "Ages 3+"},
+	}, outputDTO.DescriptionDiff)
+}
+
+func productDiffCodeCasing(t *testing.T) {
+	productDiff := usecase.NewProductDiff(newDiffProductRepository(t),
+		usecase.WithRevisionRepository(newDiffRevisionRepository(t)))
+	outputDTO, err := productDiff.Execute(context.TODO(), usecase.ProductDiffInputDTO{
+		Code: "XSZ-000741",
+		From: 1,
+		To:   2,
+	})
+	assert.Nil(t, err)
+	for _, change := range outputDTO.Changes {
+		assert.NotEqual(t, "code", change.Field)
+	}
+}
+
+func productDiffSameRevision(t *testing.T) {
+	productDiff := usecase.NewProductDiff(newDiffProductRepository(t),
+		usecase.WithRevisionRepository(newDiffRevisionRepository(t)))
+	outputDTO, err := productDiff.Execute(context.TODO(), usecase.ProductDiffInputDTO{
+		Code: "XSZ-000741",
+		From: 2,
+		To:   2,
+	})
+	assert.Nil(t, err)
+	assert.Empty(t, outputDTO.Changes)
+	assert.Len(t, outputDTO.DescriptionDiff, 3)
+}
+
+func productDiffInvalidRevisionErr(t *testing.T) {
+	productDiff := usecase.NewProductDiff(newDiffProductRepository(t),
+		usecase.WithRevisionRepository(newDiffRevisionRepository(t)))
+	_, err := productDiff.Execute(context.TODO(), usecase.ProductDiffInputDTO{Code: "XSZ-000741", To: 2})
+	assert.ErrorIs(t, err, entity.InvalidRevisionErr)
+}
+
+func productDiffNotFoundErr(t *testing.T) {
+	productDiff := usecase.NewProductDiff(newDiffProductRepository(t),
+		usecase.WithRevisionRepository(newDiffRevisionRepository(t)))
+	_, err := productDiff.Execute(context.TODO(), usecase.ProductDiffInputDTO{
+		Code: "XSZ-000741",
+		From: 1,
+		To:   7,
+	})
+	assert.ErrorIs(t, err, entity.RevisionNotFoundErr)
+}
+
+func productDiffManyLines(t *testing.T) {
+	lines := make([]string, 50000)
+	for index := range lines {
+		lines[index] = "line"
+	}
+	from := strings.Join(lines, "\n")
+	lines[25000] = "changed"
+	revisionRepo := repository.NewProductRevisionRepositoryInMemory()
+	for _, description := range []string{from, strings.Join(lines, "\n")} {
+		_, err := revisionRepo.Insert(context.TODO(), coreRepository.ProductRevisionData{
+			ProductID:   1,
+			ProductCode: "XSZ-000741",
+			Product:     coreRepository.ProductRepositoryData{Code: "XSZ-000741", Description: description},
+		})
+		assert.Nil(t, err)
+	}
+
+	outputDTO, err := usecase.NewProductDiff(newDiffProductRepository(t), usecase.WithRevisionRepository(revisionRepo)).
+		Execute(context.TODO(), usecase.ProductDiffInputDTO{Code: "XSZ-000741", From: 1, To: 2})
+	assert.Nil(t, err)
+	assert.Len(t, outputDTO.DescriptionDiff, 50001)
+	assert.Equal(t, usecase.ProductDiffLineDTO{Operation: usecase.DiffLineDelete, Text: "line"},
+		outputDTO.DescriptionDiff[25000])
+	assert.Equal(t, usecase.ProductDiffLineDTO{Operation: usecase.DiffLineInsert, Text: "changed"},
+		outputDTO.DescriptionDiff[25001])
+}
+
+func productDiffReusedCode(t *testing.T) {
+	revisionRepo := repository.NewProductRevisionRepositoryInMemory()
+	productRepo := repository.NewProductRepositoryInMemory(repository.WithRevisions(revisionRepo))
+	input := coreRepository.ProductRepositoryInput{Title: "Toy", Code: "XSZ-000741",
+		Reference: "RF009", PriceInCents: 2500}
+	_, err := productRepo.Insert(context.TODO(), input)
+	assert.Nil(t, err)
+	_, err = productRepo.DeleteByCode(context.TODO(), "XSZ-000741")
+	assert.Nil(t, err)
+	_, err = productRepo.Insert(context.TODO(), input)
+	assert.Nil(t, err)
+
+	_, err = usecase.NewProductDiff(productRepo, usecase.WithRevisionRepository(revisionRepo)).
+		Execute(context.TODO(), usecase.ProductDiffInputDTO{Code: "XSZ-000741", From: 1, To: 2})
+	assert.ErrorIs(t, err, entity.RevisionNotFoundErr)
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+var productEventTypes = map[string]string{
+	repository.ProductAuditOperationCreate:  entity.ProductCreatedEvent,
+	repository.ProductAuditOperationUpdate:  entity.ProductUpdatedEvent,
+	repository.ProductAuditOperationRevert:  entity.ProductUpdatedEvent,
+	repository.ProductAuditOperationDelete:  entity.ProductDeletedEvent,
+	repository.ProductAuditOperationRestore: entity.ProductRestoredEvent,
+}
+
+type ProductEvent struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	Code       string              `json:"code"`
+	Actor      string              `json:"actor"`
+	OccurredAt string              `json:"occurredAt"`
+	Product    ProductGetOutputDTO `json:"product"`
+}
+
+type ProductEventEmitter interface {
+	Emit(ctx context.Context, event ProductEvent)
+}
+
+func NewProductEvent(ctx context.Context, operation, code string,
+	product repository.ProductRepositoryData) ProductEvent {
+	return ProductEvent{
+		ID:         uuid.Must(uuid.NewV4()).String(),
+		Type:       productEventTypes[operation],
+		Code:       code,
+		Actor:      ActorFromContext(ctx),
+		OccurredAt: time.Now().UTC().Format(time.RFC3339Nano),
+		Product:    toProductGetOutputDTO(product),
+	}
+}
+
+type ProductEventRecord struct {
+	Epoch    string
+	Sequence int64
+	Event    ProductEvent
+}
+
+func (r ProductEventRecord) ID() string {
+	return r.Epoch + "-" + strconv.FormatInt(r.Sequence, 10)
+}
+
+// ProductEventLog replays the events after epoch and sequence when replay is set. When that
+// position is not retained, the returned reset holds the current position instead of a backlog.
+type ProductEventLog interface {
+	Subscribe(epoch string, after int64, replay bool) (backlog []ProductEventRecord,
+		records <-chan ProductEventRecord, unsubscribe func(), reset *ProductEventRecord)
+}
@@ -0,0 +1,50 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type productEventRecorder struct {
+	events []usecase.ProductEvent
+}
+
+func (r *productEventRecorder) Emit(_ context.Context, event usecase.ProductEvent) {
+	r.events = append(r.events, event)
+}
+
+func TestProductEvent_Emit(t *testing.T) {
+	t.Run("Should emit lifecycle events with the request actor", productEventEmitSuccess)
+}
+
+func productEventEmitSuccess(t *testing.T) {
+	recorder := &productEventRecorder{}
+	productRepoInMemory := repository.NewProductRepositoryInMemory()
+	ctx := usecase.WithActor(context.TODO(), "merchandiser@eulabs")
+
+	_, err := usecase.NewProductCreate(productRepoInMemory, usecase.WithEventEmitter(recorder)).
+		Execute(ctx, usecase.ProductInputDTO{
+			Title:        "Toy",
+			Description:  "Description",
+			Code:         "XXCC",
+			Reference:    "XZsdf5tY-AA",
+			PriceInCents: int64(2500),
+		})
+	assert.Nil(t, err)
+	_, err = usecase.NewProductDelete(productRepoInMemory, usecase.WithEventEmitter(recorder)).
+		Execute(ctx, "xxcc")
+	assert.Nil(t, err)
+
+	assert.Len(t, recorder.events, 2)
+	assert.Equal(t, entity.ProductCreatedEvent, recorder.events[0].Type)
+	assert.Equal(t, "XXCC", recorder.events[0].Code)
+	assert.Equal(t, "merchandiser@eulabs", recorder.events[0].Actor)
+	assert.NotEmpty(t, recorder.events[0].ID)
+	assert.Equal(t, entity.ProductDeletedEvent, recorder.events[1].Type)
+	assert.Equal(t, "XXCC", recorder.events[1].Product.Code)
+}
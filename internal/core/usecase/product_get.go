@@ -3,18 +3,21 @@ package usecase
 import (
 	"context"
 	"log/slog"
-	"time"
+	"slices"
 
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
 	"github.com/lbsti/eulabs-challenge/internal/core/repository"
 )
 
 type ProductGet struct {
 	repository repository.ProductRepository
+	options    productOptions
 }
 
 type ProductGetOutputDTO struct {
 	CreatedAt    string `json:"createdAt"`
 	UpdatedAt    string `json:"updatedAt"`
+	DeletedAt    string `json:"deletedAt,omitempty"`
 	Title        string `json:"title"`
 	Description  string `json:"description"`
 	Code         string `json:"code"`
@@ -23,15 +26,16 @@ type ProductGetOutputDTO struct {
 	ID           int64  `json:"id"`
 }
 
-func NewProductGet(productRepo repository.ProductRepository) *ProductGet {
+func NewProductGet(productRepo repository.ProductRepository, opts ...ProductOption) *ProductGet {
 	return &ProductGet{
 		repository: productRepo,
+		options:    newProductOptions(opts...),
 	}
 }
 
 func (p *ProductGet) Execute(ctx context.Context, code string) (ProductGetOutputDTO, error) {
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.options.timeouts.Get)
 	defer cancel()
 	productData, err := p.repository.GetByCode(ctxWithTimeout, code)
 
@@ -39,14 +43,61 @@ func (p *ProductGet) Execute(ctx context.Context, code string) (ProductGetOutput
 		slog.Error("impossible to get product", slog.Any("msg", err))
 		return ProductGetOutputDTO{}, err
 	}
+	return toProductGetOutputDTO(productData), nil
+}
+
+func (p *ProductGet) ExecuteWithFields(ctx context.Context, code string,
+	fields []string) (ProductGetOutputDTO, error) {
+	if err := validateFields(fields); err != nil {
+		return ProductGetOutputDTO{}, err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.options.timeouts.Get)
+	defer cancel()
+	productData, err := p.repository.GetByCodeWithFields(ctxWithTimeout, code, fields)
+
+	if err != nil {
+		slog.Error("impossible to get product", slog.Any("msg", err))
+		return ProductGetOutputDTO{}, err
+	}
+	return toProductGetOutputDTO(productData), nil
+}
+
+func (p *ProductGet) ExecuteMany(ctx context.Context, codes []string) ([]ProductGetOutputDTO, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.options.timeouts.Get)
+	defer cancel()
+	productsData, err := p.repository.GetByCodes(ctxWithTimeout, codes)
+
+	if err != nil {
+		slog.Error("impossible to get products", slog.Any("msg", err))
+		return nil, err
+	}
+	outputDTOs := make([]ProductGetOutputDTO, 0, len(productsData))
+	for _, productData := range productsData {
+		outputDTOs = append(outputDTOs, toProductGetOutputDTO(productData))
+	}
+	return outputDTOs, nil
+}
+
+func toProductGetOutputDTO(productData repository.ProductRepositoryData) ProductGetOutputDTO {
 	return ProductGetOutputDTO{
 		ID:           productData.ID,
 		Title:        productData.Title,
 		CreatedAt:    productData.CreatedAt,
 		UpdatedAt:    productData.UpdatedAt,
+		DeletedAt:    productData.DeletedAt,
 		Reference:    productData.Reference,
 		Code:         productData.Code,
 		PriceInCents: productData.PriceInCents,
 		Description:  productData.Description,
-	}, nil
+	}
+}
+
+func validateFields(fields []string) error {
+	for _, field := range fields {
+		if !slices.Contains(repository.ProductFields, field) {
+			return entity.InvalidFieldErr
+		}
+	}
+	return nil
 }
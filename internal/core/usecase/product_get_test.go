@@ -3,8 +3,10 @@ package usecase_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	coreRepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
 	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
 	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
 	"github.com/stretchr/testify/assert"
@@ -13,10 +15,38 @@ import (
 func TestProductGet_Execute(t *testing.T) {
 	t.Run("Should get a product by code with success", productGetByCodeSuccess)
 	t.Run("Should results a error if product not found", productGetByCodeNotFoundErr)
+	t.Run("Should apply the configured get timeout", productGetByCodeConfiguredTimeout)
 }
 
-func productGetByCodeSuccess(t *testing.T) {
+type deadlineRecorderRepository struct {
+	coreRepository.ProductRepository
+	remaining time.Duration
+}
+
+func (r *deadlineRecorderRepository) GetByCode(ctx context.Context,
+	code string) (coreRepository.ProductRepositoryData, error) {
+	deadline, _ := ctx.Deadline()
+	r.remaining = time.Until(deadline)
+	return r.ProductRepository.GetByCode(ctx, code)
+}
+
+func newProductRepositoryWith(t *testing.T, codes ...string) coreRepository.ProductRepository {
 	productRepoInMemory := repository.NewProductRepositoryInMemory()
+	for _, code := range codes {
+		_, err := productRepoInMemory.Insert(context.TODO(), coreRepository.ProductRepositoryInput{
+			Title:        "Toy",
+			Description:  "Blahahhs",
+			Code:         code,
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(51400),
+		})
+		assert.Nil(t, err)
+	}
+	return productRepoInMemory
+}
+
+func productGetByCodeSuccess(t *testing.T) {
+	productRepoInMemory := newProductRepositoryWith(t, "XSZ-000741")
 	productGet := usecase.NewProductGet(productRepoInMemory)
 	productGetOutputDTO, err := productGet.Execute(context.TODO(), "XSZ-000741")
 	assert.Nil(t, err)
@@ -32,3 +62,38 @@ func productGetByCodeNotFoundErr(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, productGetOutputDTO.ID, int64(0))
 }
+
+func TestProductGet_ExecuteWithFields(t *testing.T) {
+	t.Run("Should get a product with the requested fields", productGetWithFieldsSuccess)
+	t.Run("Should results a error if a field is invalid", productGetWithFieldsInvalidErr)
+}
+
+func productGetWithFieldsSuccess(t *testing.T) {
+	productRepoInMemory := newProductRepositoryWith(t, "XSZ-000741")
+	productGet := usecase.NewProductGet(productRepoInMemory)
+	productGetOutputDTO, err := productGet.ExecuteWithFields(context.TODO(), "XSZ-000741", []string{"code"})
+	assert.Nil(t, err)
+	assert.Equal(t, "XSZ-000741", productGetOutputDTO.Code)
+}
+
+func productGetWithFieldsInvalidErr(t *testing.T) {
+	productRepoInMemory := repository.NewProductRepositoryInMemory()
+	productGet := usecase.NewProductGet(productRepoInMemory)
+	_, err := productGet.ExecuteWithFields(context.TODO(), "XSZ-000741", []string{"secret"})
+	assert.ErrorIs(t, err, entity.InvalidFieldErr)
+}
+
+func productGetByCodeConfiguredTimeout(t *testing.T) {
+	productRepo := &deadlineRecorderRepository{ProductRepository: newProductRepositoryWith(t, "XSZ-000741")}
+
+	_, err := usecase.NewProductGet(productRepo).Execute(context.TODO(), "XSZ-000741")
+	assert.Nil(t, err)
+	assert.Greater(t, productRepo.remaining, time.Second)
+
+	_, err = usecase.NewProductGet(productRepo, usecase.WithTimeouts(usecase.ProductTimeouts{
+		Get: time.Second,
+	})).Execute(context.TODO(), "XSZ-000741")
+	assert.Nil(t, err)
+	assert.LessOrEqual(t, productRepo.remaining, time.Second)
+	assert.Greater(t, productRepo.remaining, time.Duration(0))
+}
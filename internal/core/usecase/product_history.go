@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductHistory struct {
+	options productOptions
+}
+
+type ProductHistoryInputDTO struct {
+	Code     string
+	Page     int
+	PageSize int
+}
+
+type ProductAuditOutputDTO struct {
+	ID        int64                `json:"id"`
+	Operation string               `json:"operation"`
+	Actor     string               `json:"actor"`
+	Before    *ProductGetOutputDTO `json:"before"`
+	After     *ProductGetOutputDTO `json:"after"`
+	CreatedAt string               `json:"createdAt"`
+}
+
+type ProductHistoryOutputDTO struct {
+	Items    []ProductAuditOutputDTO `json:"items"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"pageSize"`
+	Total    int64                   `json:"total"`
+}
+
+func NewProductHistory(opts ...ProductOption) *ProductHistory {
+	return &ProductHistory{
+		options: newProductOptions(opts...),
+	}
+}
+
+func (p *ProductHistory) Execute(ctx context.Context, input ProductHistoryInputDTO) (ProductHistoryOutputDTO, error) {
+	page, pageSize, err := paginate(input.Page, input.PageSize)
+	if err != nil {
+		return ProductHistoryOutputDTO{}, err
+	}
+	output := ProductHistoryOutputDTO{
+		Items:    []ProductAuditOutputDTO{},
+		Page:     page,
+		PageSize: pageSize,
+	}
+	if p.options.auditRepository == nil {
+		return output, nil
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.options.timeouts.Get)
+	defer cancel()
+
+	auditData, total, err := p.options.auditRepository.ListByCode(ctxWithTimeout, repository.ProductAuditFilter{
+		Code:   input.Code,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		slog.Error("impossible to list product history", slog.Any("msg", err))
+		return ProductHistoryOutputDTO{}, err
+	}
+
+	for _, entry := range auditData {
+		output.Items = append(output.Items, ProductAuditOutputDTO{
+			ID:        entry.ID,
+			Operation: entry.Operation,
+			Actor:     entry.Actor,
+			Before:    toProductSnapshotDTO(entry.Before),
+			After:     toProductSnapshotDTO(entry.After),
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+	output.Total = total
+	return output, nil
+}
+
+func toProductSnapshotDTO(productData *repository.ProductRepositoryData) *ProductGetOutputDTO {
+	if productData == nil {
+		return nil
+	}
+	snapshot := toProductGetOutputDTO(*productData)
+	return &snapshot
+}
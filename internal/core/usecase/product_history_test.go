@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	corerepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductHistory_Execute(t *testing.T) {
+	t.Run("Should record create, update and delete with actor and snapshots", productHistorySuccess)
+	t.Run("Should results an empty history if audit is not configured", productHistoryNotConfigured)
+	t.Run("Should not store the product if the audit record cannot be written", productHistoryAuditErr)
+}
+
+type failingAuditRepository struct {
+	corerepository.ProductAuditRepository
+	err error
+}
+
+func (r failingAuditRepository) Insert(ctx context.Context, in corerepository.ProductAuditData) error {
+	return r.err
+}
+
+func productHistorySuccess(t *testing.T) {
+	auditRepo := repository.NewProductAuditRepositoryInMemory()
+	productRepoInMemory := repository.NewProductRepositoryInMemory(repository.WithAuditTrail(auditRepo))
+	ctx := usecase.WithActor(context.TODO(), "merchandiser@eulabs")
+	input := usecase.ProductInputDTO{
+		Title:        "Toy",
+		Description:  "Description",
+		Code:         "XSZ-000741",
+		Reference:    "RF009-pods74",
+		PriceInCents: int64(2500),
+	}
+
+	_, err := usecase.NewProductCreate(productRepoInMemory).Execute(ctx, input)
+	assert.Nil(t, err)
+	_, err = usecase.NewProductUpdate(productRepoInMemory).Execute(ctx, input)
+	assert.Nil(t, err)
+	_, err = usecase.NewProductDelete(productRepoInMemory).Execute(context.TODO(), input.Code)
+	assert.Nil(t, err)
+
+	outputDTO, err := usecase.NewProductHistory(usecase.WithAuditRepository(auditRepo)).Execute(context.TODO(),
+		usecase.ProductHistoryInputDTO{Code: "xsz-000741", PageSize: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), outputDTO.Total)
+	assert.Len(t, outputDTO.Items, 2)
+	assert.Equal(t, "delete", outputDTO.Items[0].Operation)
+	assert.Equal(t, usecase.AnonymousActor, outputDTO.Items[0].Actor)
+	assert.NotNil(t, outputDTO.Items[0].Before)
+	assert.Nil(t, outputDTO.Items[0].After)
+	assert.Equal(t, "update", outputDTO.Items[1].Operation)
+	assert.Equal(t, "merchandiser@eulabs", outputDTO.Items[1].Actor)
+	assert.Equal(t, int64(2500), outputDTO.Items[1].Before.PriceInCents)
+	assert.NotNil(t, outputDTO.Items[1].After)
+}
+
+func productHistoryNotConfigured(t *testing.T) {
+	outputDTO, err := usecase.NewProductHistory().Execute(context.TODO(),
+		usecase.ProductHistoryInputDTO{Code: "XSZ-000741"})
+	assert.Nil(t, err)
+	assert.Empty(t, outputDTO.Items)
+}
+
+func productHistoryAuditErr(t *testing.T) {
+	auditErr := errors.New("audit unavailable")
+	productRepoInMemory := repository.NewProductRepositoryInMemory(
+		repository.WithAuditTrail(failingAuditRepository{err: auditErr}))
+
+	_, err := usecase.NewProductCreate(productRepoInMemory).
+		Execute(context.TODO(), usecase.ProductInputDTO{
+			Title:        "Toy",
+			Description:  "Description",
+			Code:         "XSZ-000741",
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(2500),
+		})
+	assert.ErrorIs(t, err, auditErr)
+
+	_, err = productRepoInMemory.GetByCode(context.TODO(), "XSZ-000741")
+	assert.ErrorIs(t, err, entity.ProductNotFoundErr)
+}
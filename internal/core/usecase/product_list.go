@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	ProductListDefaultPageSize = 20
+	ProductListMaxPageSize     = 100
+)
+
+type ProductList struct {
+	repository repository.ProductRepository
+	options    productOptions
+}
+
+type ProductListInputDTO struct {
+	Code            string
+	Title           string
+	MinPriceInCents int64
+	MaxPriceInCents int64
+	Page            int
+	PageSize        int
+	Fields          []string
+}
+
+type ProductListOutputDTO struct {
+	Items    []ProductGetOutputDTO `json:"items"`
+	Page     int                   `json:"page"`
+	PageSize int                   `json:"pageSize"`
+	Total    int64                 `json:"total"`
+}
+
+func NewProductList(productRepo repository.ProductRepository, opts ...ProductOption) *ProductList {
+	return &ProductList{
+		repository: productRepo,
+		options:    newProductOptions(opts...),
+	}
+}
+
+func (p *ProductList) Execute(ctx context.Context, input ProductListInputDTO) (ProductListOutputDTO, error) {
+	page, pageSize, err := paginate(input.Page, input.PageSize)
+	if err != nil {
+		return ProductListOutputDTO{}, err
+	}
+	input.Page, input.PageSize = page, pageSize
+	if input.MinPriceInCents < 0 || input.MaxPriceInCents < 0 ||
+		(input.MaxPriceInCents > 0 && input.MinPriceInCents > input.MaxPriceInCents) {
+		return ProductListOutputDTO{}, entity.InvalidPriceRangeErr
+	}
+	if err := validateFields(input.Fields); err != nil {
+		return ProductListOutputDTO{}, err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.options.timeouts.List)
+	defer cancel()
+
+	productsData, total, err := p.repository.List(ctxWithTimeout, repository.ProductRepositoryFilter{
+		Code:            input.Code,
+		Title:           input.Title,
+		MinPriceInCents: input.MinPriceInCents,
+		MaxPriceInCents: input.MaxPriceInCents,
+		Offset:          (input.Page - 1) * input.PageSize,
+		Limit:           input.PageSize,
+		Fields:          input.Fields,
+	})
+
+	if err != nil {
+		slog.Error("impossible to list products", slog.Any("msg", err))
+		return ProductListOutputDTO{}, err
+	}
+
+	items := make([]ProductGetOutputDTO, 0, len(productsData))
+	for _, productData := range productsData {
+		items = append(items, toProductGetOutputDTO(productData))
+	}
+	return ProductListOutputDTO{
+		Items:    items,
+		Page:     input.Page,
+		PageSize: input.PageSize,
+		Total:    total,
+	}, nil
+}
+
+func paginate(page, pageSize int) (int, int, error) {
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = ProductListDefaultPageSize
+	}
+	if page < 0 || pageSize < 0 || pageSize > ProductListMaxPageSize {
+		return 0, 0, entity.InvalidPaginationErr
+	}
+	return page, pageSize, nil
+}
@@ -0,0 +1,62 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductList_Execute(t *testing.T) {
+	t.Run("Should list products with default pagination", productListSuccess)
+	t.Run("Should results an error if page size is greater than max", productListPageSizeErr)
+	t.Run("Should results an error if price range is inverted", productListPriceRangeErr)
+	t.Run("Should results an error if repository fails", productListRepositoryErr)
+}
+
+func productListSuccess(t *testing.T) {
+	productRepoInMemory := newProductRepositoryWith(t, "XSZ-000741")
+	productList := usecase.NewProductList(productRepoInMemory)
+
+	outputDTO, err := productList.Execute(context.TODO(), usecase.ProductListInputDTO{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, outputDTO.Page)
+	assert.Equal(t, usecase.ProductListDefaultPageSize, outputDTO.PageSize)
+	assert.Equal(t, int64(1), outputDTO.Total)
+	assert.Len(t, outputDTO.Items, 1)
+}
+
+func productListPageSizeErr(t *testing.T) {
+	productRepoInMemory := repository.NewProductRepositoryInMemory()
+	productList := usecase.NewProductList(productRepoInMemory)
+
+	_, err := productList.Execute(context.TODO(), usecase.ProductListInputDTO{
+		PageSize: usecase.ProductListMaxPageSize + 1,
+	})
+	assert.EqualError(t, err, entity.InvalidPaginationErr.Error())
+}
+
+func productListPriceRangeErr(t *testing.T) {
+	productRepoInMemory := repository.NewProductRepositoryInMemory()
+	productList := usecase.NewProductList(productRepoInMemory)
+
+	_, err := productList.Execute(context.TODO(), usecase.ProductListInputDTO{
+		MinPriceInCents: 5000,
+		MaxPriceInCents: 1000,
+	})
+	assert.EqualError(t, err, entity.InvalidPriceRangeErr.Error())
+}
+
+func productListRepositoryErr(t *testing.T) {
+	productRepoInMemory := repository.ProductRepositoryInMemorySpy{
+		ExpectedError: context.DeadlineExceeded,
+	}
+	productList := usecase.NewProductList(productRepoInMemory)
+
+	outputDTO, err := productList.Execute(context.TODO(), usecase.ProductListInputDTO{})
+	assert.NotNil(t, err)
+	assert.Equal(t, usecase.ProductListOutputDTO{}, outputDTO)
+}
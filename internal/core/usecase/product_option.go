@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	AnonymousActor = "anonymous"
+)
+
+type actorKey struct{}
+
+type ProductOption func(*productOptions)
+
+type ProductTimeouts struct {
+	Create time.Duration
+	Get    time.Duration
+	Update time.Duration
+	Delete time.Duration
+	List   time.Duration
+}
+
+type productOptions struct {
+	auditRepository    repository.ProductAuditRepository
+	revisionRepository repository.ProductRevisionRepository
+	eventEmitters      []ProductEventEmitter
+	timeouts           ProductTimeouts
+}
+
+func WithAuditRepository(auditRepo repository.ProductAuditRepository) ProductOption {
+	return func(options *productOptions) {
+		options.auditRepository = auditRepo
+	}
+}
+
+func WithRevisionRepository(revisionRepo repository.ProductRevisionRepository) ProductOption {
+	return func(options *productOptions) {
+		options.revisionRepository = revisionRepo
+	}
+}
+
+func WithEventEmitter(emitter ProductEventEmitter) ProductOption {
+	return func(options *productOptions) {
+		options.eventEmitters = append(options.eventEmitters, emitter)
+	}
+}
+
+func WithTimeouts(timeouts ProductTimeouts) ProductOption {
+	return func(options *productOptions) {
+		options.timeouts = timeouts
+	}
+}
+
+func newProductOptions(opts ...ProductOption) productOptions {
+	options := productOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	for _, timeout := range []*time.Duration{&options.timeouts.Create, &options.timeouts.Get,
+		&options.timeouts.Update, &options.timeouts.Delete, &options.timeouts.List} {
+		if *timeout <= 0 {
+			*timeout = ProductDefaultTimeout
+		}
+	}
+	return options
+}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, strings.TrimSpace(actor))
+}
+
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+func (o productOptions) emit(ctx context.Context, operation, code string, product repository.ProductRepositoryData) {
+	if len(o.eventEmitters) == 0 {
+		return
+	}
+	event := NewProductEvent(ctx, operation, code, product)
+	for _, emitter := range o.eventEmitters {
+		emitter.Emit(ctx, event)
+	}
+}
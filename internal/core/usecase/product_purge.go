@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductPurge struct {
+	repository repository.ProductRepository
+	retention  time.Duration
+}
+
+func NewProductPurge(productRepo repository.ProductRepository, retention time.Duration) *ProductPurge {
+	return &ProductPurge{
+		repository: productRepo,
+		retention:  retention,
+	}
+}
+
+func (p *ProductPurge) Execute(ctx context.Context) (int64, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	defer cancel()
+
+	purged, err := p.repository.PurgeDeleted(ctxWithTimeout, time.Now().Add(-p.retention))
+	if err != nil {
+		slog.Error("impossible to purge deleted products", slog.Any("msg", err))
+		return 0, err
+	}
+	return purged, nil
+}
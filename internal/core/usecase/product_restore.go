@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductRestore struct {
+	repository repository.ProductRepository
+	options    productOptions
+}
+
+func NewProductRestore(productRepo repository.ProductRepository, opts ...ProductOption) *ProductRestore {
+	return &ProductRestore{
+		repository: productRepo,
+		options:    newProductOptions(opts...),
+	}
+}
+
+func (p *ProductRestore) Execute(ctx context.Context, code string) (ProductOutputDTO, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(WithPrimaryRead(ctx), p.options.timeouts.Update)
+	defer cancel()
+
+	if err := p.repository.RestoreByCode(ctxWithTimeout, code); err != nil {
+		slog.Error("impossible to restore product", slog.Any("msg", err))
+		return ProductOutputDTO{}, err
+	}
+
+	productData, err := p.repository.GetByCode(ctxWithTimeout, code)
+	if err != nil {
+		slog.Error("impossible to get restored product", slog.Any("msg", err))
+		return ProductOutputDTO{}, err
+	}
+	p.options.emit(ctxWithTimeout, repository.ProductAuditOperationRestore, productData.Code, productData)
+	return toProductGetOutputDTO(productData), nil
+}
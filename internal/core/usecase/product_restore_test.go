@@ -0,0 +1,67 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	coreRepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRestore_Execute(t *testing.T) {
+	t.Run("Should restore a product with success", productRestoreSuccess)
+	t.Run("Should results a error if product not found in trash", productRestoreNotFoundErr)
+}
+
+func newTrashedProductRepository(t *testing.T) coreRepository.ProductRepository {
+	productRepoInMemory := newProductRepositoryWith(t, "XSZ-000741")
+	_, err := productRepoInMemory.DeleteByCode(context.TODO(), "XSZ-000741")
+	assert.Nil(t, err)
+	return productRepoInMemory
+}
+
+func productRestoreSuccess(t *testing.T) {
+	productRepoInMemory := newTrashedProductRepository(t)
+	productRestore := usecase.NewProductRestore(productRepoInMemory)
+	outputDTO, err := productRestore.Execute(context.TODO(), "XSZ-000741")
+	assert.Nil(t, err)
+	assert.Equal(t, "XSZ-000741", outputDTO.Code)
+}
+
+func productRestoreNotFoundErr(t *testing.T) {
+	productRepoInMemory := repository.ProductRepositoryInMemorySpy{
+		ExpectedError: entity.ProductNotFoundErr,
+	}
+	productRestore := usecase.NewProductRestore(productRepoInMemory)
+	_, err := productRestore.Execute(context.TODO(), "XSZ-000741")
+	assert.ErrorIs(t, err, entity.ProductNotFoundErr)
+}
+
+func TestProductTrash_Execute(t *testing.T) {
+	t.Run("Should list deleted products with default pagination", productTrashSuccess)
+}
+
+func productTrashSuccess(t *testing.T) {
+	productRepoInMemory := newTrashedProductRepository(t)
+	productTrash := usecase.NewProductTrash(productRepoInMemory)
+	outputDTO, err := productTrash.Execute(context.TODO(), usecase.ProductTrashInputDTO{})
+	assert.Nil(t, err)
+	assert.Equal(t, usecase.ProductListDefaultPageSize, outputDTO.PageSize)
+	assert.NotEmpty(t, outputDTO.Items[0].DeletedAt)
+}
+
+func TestProductPurge_Execute(t *testing.T) {
+	t.Run("Should purge products deleted before the retention", productPurgeSuccess)
+}
+
+func productPurgeSuccess(t *testing.T) {
+	productRepoInMemory := repository.ProductRepositoryInMemorySpy{}
+	productPurge := usecase.NewProductPurge(productRepoInMemory, 24*time.Hour)
+	purged, err := productPurge.Execute(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), purged)
+}
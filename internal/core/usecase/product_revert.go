@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductRevert struct {
+	repository    repository.ProductRepository
+	productUpdate *ProductUpdate
+	options       productOptions
+}
+
+func NewProductRevert(productRepo repository.ProductRepository, opts ...ProductOption) *ProductRevert {
+	return &ProductRevert{
+		repository:    productRepo,
+		productUpdate: NewProductUpdate(productRepo, opts...),
+		options:       newProductOptions(opts...),
+	}
+}
+
+func (p *ProductRevert) Execute(ctx context.Context, code string, revision int) (ProductOutputDTO, error) {
+	if revision < 1 {
+		return ProductOutputDTO{}, entity.InvalidRevisionErr
+	}
+	if p.options.revisionRepository == nil {
+		return ProductOutputDTO{}, entity.RevisionNotFoundErr
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(WithPrimaryRead(ctx), p.options.timeouts.Update)
+	defer cancel()
+
+	productData, err := p.repository.GetByCode(ctxWithTimeout, code)
+	if err != nil {
+		slog.Error("impossible to revert product", slog.Any("msg", err))
+		return ProductOutputDTO{}, err
+	}
+	revisionData, err := p.options.revisionRepository.GetByRevision(ctxWithTimeout, productData.ID, revision)
+	if err != nil {
+		slog.Error("impossible to get product revision", slog.Any("msg", err))
+		return ProductOutputDTO{}, err
+	}
+
+	return p.productUpdate.execute(ctx, ProductInputDTO{
+		Title:        revisionData.Product.Title,
+		Description:  revisionData.Product.Description,
+		Code:         productData.Code,
+		Reference:    revisionData.Product.Reference,
+		PriceInCents: revisionData.Product.PriceInCents,
+	}, repository.ProductAuditOperationRevert)
+}
@@ -0,0 +1,130 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	coreRepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRevert_Execute(t *testing.T) {
+	t.Run("Should revert a product as a new revision", productRevertSuccess)
+	t.Run("Should results a error if revision is invalid", productRevertInvalidRevisionErr)
+	t.Run("Should results a error if revision not found", productRevertNotFoundErr)
+	t.Run("Should results a error if revision snapshot is invalid", productRevertInvalidSnapshotErr)
+	t.Run("Should not store the product if the revision cannot be written", productRevisionInsertErr)
+}
+
+type failingRevisionRepository struct {
+	coreRepository.ProductRevisionRepository
+	err error
+}
+
+func (r failingRevisionRepository) Insert(ctx context.Context,
+	in coreRepository.ProductRevisionData) (coreRepository.ProductRevisionData, error) {
+	return coreRepository.ProductRevisionData{}, r.err
+}
+
+func productRevertSuccess(t *testing.T) {
+	auditRepo := repository.NewProductAuditRepositoryInMemory()
+	revisionRepo := repository.NewProductRevisionRepositoryInMemory()
+	productRepoInMemory := repository.NewProductRepositoryInMemory(repository.WithAuditTrail(auditRepo),
+		repository.WithRevisions(revisionRepo))
+	opts := []usecase.ProductOption{
+		usecase.WithRevisionRepository(revisionRepo),
+	}
+
+	_, err := usecase.NewProductCreate(productRepoInMemory, opts...).Execute(context.TODO(),
+		usecase.ProductInputDTO{
+			Title:        "Toy",
+			Description:  "Description",
+			Code:         "XSZ-000741",
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(2500),
+		})
+	assert.Nil(t, err)
+
+	outputDTO, err := usecase.NewProductRevert(productRepoInMemory, opts...).Execute(context.TODO(),
+		"XSZ-000741", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "XSZ-000741", outputDTO.Code)
+
+	revisionData, err := revisionRepo.GetByRevision(context.TODO(), 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, revisionData.Revision)
+
+	history, _, err := auditRepo.ListByCode(context.TODO(), coreRepository.ProductAuditFilter{Code: "XSZ-000741"})
+	assert.Nil(t, err)
+	assert.Equal(t, coreRepository.ProductAuditOperationRevert, history[0].Operation)
+}
+
+func productRevertInvalidRevisionErr(t *testing.T) {
+	productRevert := usecase.NewProductRevert(repository.NewProductRepositoryInMemory(),
+		usecase.WithRevisionRepository(repository.NewProductRevisionRepositoryInMemory()))
+	_, err := productRevert.Execute(context.TODO(), "XSZ-000741", 0)
+	assert.ErrorIs(t, err, entity.InvalidRevisionErr)
+}
+
+func newRevertProductRepository(t *testing.T) coreRepository.ProductRepository {
+	productRepo := repository.NewProductRepositoryInMemory()
+	_, err := productRepo.Insert(context.TODO(), coreRepository.ProductRepositoryInput{
+		Title:        "Toy",
+		Description:  "Description",
+		Code:         "XSZ-000741",
+		Reference:    "RF009-pods74",
+		PriceInCents: int64(2500),
+	})
+	assert.Nil(t, err)
+	return productRepo
+}
+
+func productRevertNotFoundErr(t *testing.T) {
+	productRevert := usecase.NewProductRevert(newRevertProductRepository(t),
+		usecase.WithRevisionRepository(repository.NewProductRevisionRepositoryInMemory()))
+	_, err := productRevert.Execute(context.TODO(), "XSZ-000741", 3)
+	assert.ErrorIs(t, err, entity.RevisionNotFoundErr)
+}
+
+func productRevertInvalidSnapshotErr(t *testing.T) {
+	revisionRepo := repository.NewProductRevisionRepositoryInMemory()
+	_, err := revisionRepo.Insert(context.TODO(), coreRepository.ProductRevisionData{
+		ProductID:   1,
+		ProductCode: "XSZ-000741",
+		Product: coreRepository.ProductRepositoryData{
+			Code:         "XSZ-000741",
+			Description:  "Description",
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(2500),
+		},
+	})
+	assert.Nil(t, err)
+
+	productRevert := usecase.NewProductRevert(newRevertProductRepository(t),
+		usecase.WithRevisionRepository(revisionRepo))
+	_, err = productRevert.Execute(context.TODO(), "XSZ-000741", 1)
+	assert.ErrorIs(t, err, entity.RequiredTitleErr)
+}
+
+func productRevisionInsertErr(t *testing.T) {
+	revisionErr := errors.New("revisions unavailable")
+	productRepoInMemory := repository.NewProductRepositoryInMemory(
+		repository.WithRevisions(failingRevisionRepository{err: revisionErr}))
+
+	_, err := usecase.NewProductCreate(productRepoInMemory).
+		Execute(context.TODO(), usecase.ProductInputDTO{
+			Title:        "Toy",
+			Description:  "Description",
+			Code:         "XSZ-000741",
+			Reference:    "RF009-pods74",
+			PriceInCents: int64(2500),
+		})
+	assert.ErrorIs(t, err, revisionErr)
+
+	_, err = productRepoInMemory.GetByCode(context.TODO(), "XSZ-000741")
+	assert.ErrorIs(t, err, entity.ProductNotFoundErr)
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductTrash struct {
+	repository repository.ProductRepository
+	options    productOptions
+}
+
+type ProductTrashInputDTO struct {
+	Code     string
+	Page     int
+	PageSize int
+}
+
+func NewProductTrash(productRepo repository.ProductRepository, opts ...ProductOption) *ProductTrash {
+	return &ProductTrash{
+		repository: productRepo,
+		options:    newProductOptions(opts...),
+	}
+}
+
+func (p *ProductTrash) Execute(ctx context.Context, input ProductTrashInputDTO) (ProductListOutputDTO, error) {
+	page, pageSize, err := paginate(input.Page, input.PageSize)
+	if err != nil {
+		return ProductListOutputDTO{}, err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, p.options.timeouts.List)
+	defer cancel()
+
+	productsData, total, err := p.repository.ListDeleted(ctxWithTimeout, repository.ProductRepositoryFilter{
+		Code:   input.Code,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		slog.Error("impossible to list deleted products", slog.Any("msg", err))
+		return ProductListOutputDTO{}, err
+	}
+
+	items := make([]ProductGetOutputDTO, 0, len(productsData))
+	for _, productData := range productsData {
+		items = append(items, toProductGetOutputDTO(productData))
+	}
+	return ProductListOutputDTO{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
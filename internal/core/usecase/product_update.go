@@ -3,40 +3,57 @@ package usecase
 import (
 	"context"
 	"log/slog"
-	"time"
 
 	"github.com/lbsti/eulabs-challenge/internal/core/repository"
 )
 
 type ProductUpdate struct {
 	repository repository.ProductRepository
+	options    productOptions
 }
 
-func NewProductUpdate(productRepo repository.ProductRepository) *ProductUpdate {
+func NewProductUpdate(productRepo repository.ProductRepository, opts ...ProductOption) *ProductUpdate {
 	return &ProductUpdate{
 		repository: productRepo,
+		options:    newProductOptions(opts...),
 	}
 }
 
-func (p *ProductUpdate) Execute(ctx context.Context, input ProductInputDTO) error {
+func (p *ProductUpdate) Execute(ctx context.Context, input ProductInputDTO) (ProductOutputDTO, error) {
+	return p.execute(ctx, input, repository.ProductAuditOperationUpdate)
+}
+
+func (p *ProductUpdate) execute(ctx context.Context, input ProductInputDTO,
+	operation string) (ProductOutputDTO, error) {
 	if err := validate(input); err != nil {
-		return err
+		return ProductOutputDTO{}, err
 	}
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	ctxWithTimeout, cancel := context.WithTimeout(WithPrimaryRead(ctx), p.options.timeouts.Update)
 	defer cancel()
 
-	productData, err := p.repository.GetByCode(ctx, input.Code)
+	productData, err := p.repository.GetByCode(ctxWithTimeout, input.Code)
 
 	if err != nil {
 		slog.Error("impossible to update product", slog.Any("msg", err))
-		return err
+		return ProductOutputDTO{}, err
 	}
 
-	return p.repository.Update(ctxWithTimeout, repository.ProductRepositoryInput{
+	if err := p.repository.Update(ctxWithTimeout, repository.ProductRepositoryInput{
 		Title:        input.Title,
 		Description:  input.Description,
 		Code:         productData.Code,
 		Reference:    input.Reference,
+		Operation:    operation,
 		PriceInCents: input.PriceInCents,
-	})
+	}); err != nil {
+		return ProductOutputDTO{}, err
+	}
+
+	updatedData, err := p.repository.GetByCode(ctxWithTimeout, productData.Code)
+	if err != nil {
+		slog.Error("impossible to retrieve updated product", slog.Any("msg", err))
+		return ProductOutputDTO{}, err
+	}
+	p.options.emit(ctxWithTimeout, operation, productData.Code, updatedData)
+	return toProductGetOutputDTO(updatedData), nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	coreRepository "github.com/lbsti/eulabs-challenge/internal/core/repository"
 	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
 	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
 	"github.com/stretchr/testify/assert"
@@ -16,14 +17,44 @@ func TestProductUpdate_Execute(t *testing.T) {
 	t.Run("Should results an error if product code is empty", productUpdateCodeEmptyErr)
 	t.Run("Should results an error if product is invalid", productUpdateInvalidErr)
 	t.Run("Should results an error if product does not exist", productUpdateNotFoundErr)
+	t.Run("Should read the product from the primary within the timeout", productUpdatePrimaryRead)
+}
+
+type primaryReadRecorder struct {
+	coreRepository.ProductRepository
+	reads []bool
+}
+
+func (r *primaryReadRecorder) GetByCode(ctx context.Context,
+	code string) (coreRepository.ProductRepositoryData, error) {
+	_, hasDeadline := ctx.Deadline()
+	r.reads = append(r.reads, usecase.PrimaryReadFromContext(ctx) && hasDeadline)
+	return r.ProductRepository.GetByCode(ctx, code)
+}
+
+func productUpdatePrimaryRead(t *testing.T) {
+	productRepo := &primaryReadRecorder{ProductRepository: newProductRepositoryWith(t, "XSZ-000741")}
+
+	_, err := usecase.NewProductUpdate(productRepo).Execute(context.TODO(), usecase.ProductInputDTO{
+		Title:        "Toy",
+		Description:  "Description",
+		Code:         "XSZ-000741",
+		Reference:    "RF009-pods74",
+		PriceInCents: int64(2500),
+	})
+	assert.Nil(t, err)
+	_, err = usecase.NewProductDelete(productRepo).Execute(context.TODO(), "XSZ-000741")
+	assert.Nil(t, err)
+
+	assert.Equal(t, []bool{true, true, true}, productRepo.reads)
 }
 
 func productUpdateSuccess(t *testing.T) {
-	productRepoInMemory := repository.NewProductRepositoryInMemory()
+	productRepoInMemory := newProductRepositoryWith(t, "0001-DEF-UDSE-14587")
 	productUpdate := usecase.NewProductUpdate(productRepoInMemory)
 	reference := uuid.FromStringOrNil("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 
-	err := productUpdate.Execute(context.TODO(), usecase.ProductInputDTO{
+	_, err := productUpdate.Execute(context.TODO(), usecase.ProductInputDTO{
 		Title: "exacqVision® VMS",
 		Description: `The exacqVision® VMS (Video Management System) software
 	installs on commercial off-the-shelf (COTS) servers running
@@ -42,7 +73,7 @@ func productUpdateCodeEmptyErr(t *testing.T) {
 	productUpdate := usecase.NewProductUpdate(productRepoInMemory)
 	reference := uuid.FromStringOrNil("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 
-	err := productUpdate.Execute(context.TODO(), usecase.ProductInputDTO{
+	_, err := productUpdate.Execute(context.TODO(), usecase.ProductInputDTO{
 		Title: "exacqVision® VMS",
 		Description: `The exacqVision® VMS (Video Management System) software
 	installs on commercial off-the-shelf (COTS) servers running
@@ -62,7 +93,7 @@ func productUpdateInvalidErr(t *testing.T) {
 	productUpdate := usecase.NewProductUpdate(productRepoInMemory)
 	reference := uuid.FromStringOrNil("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 
-	err := productUpdate.Execute(context.TODO(), usecase.ProductInputDTO{
+	_, err := productUpdate.Execute(context.TODO(), usecase.ProductInputDTO{
 		Title:        "exacqVision® VMS",
 		Description:  ``,
 		Code:         "0001-DEF-UDSE-14587",
@@ -80,7 +111,7 @@ func productUpdateNotFoundErr(t *testing.T) {
 	productUpdate := usecase.NewProductUpdate(productRepoInMemory)
 	reference := uuid.FromStringOrNil("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 
-	err := productUpdate.Execute(context.TODO(), usecase.ProductInputDTO{
+	_, err := productUpdate.Execute(context.TODO(), usecase.ProductInputDTO{
 		Title: "exacqVision® VMS",
 		Description: `The exacqVision® VMS (Video Management System) software
 	installs on commercial off-the-shelf (COTS) servers running
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// ReadSession tells until when one client must read from the primary database. Adapters restore
+// it from a token the client sends back, so the pin follows the client to any node.
+type ReadSession struct {
+	primaryUntil atomic.Int64
+}
+
+type readSessionKey struct{}
+
+func NewReadSession(primaryUntil time.Time) *ReadSession {
+	session := &ReadSession{}
+	if !primaryUntil.IsZero() {
+		session.primaryUntil.Store(primaryUntil.UnixMilli())
+	}
+	return session
+}
+
+func (s *ReadSession) PrimaryUntil() time.Time {
+	millis := s.primaryUntil.Load()
+	if millis == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
+
+func (s *ReadSession) PinPrimary(until time.Time) {
+	for {
+		current := s.primaryUntil.Load()
+		if until.UnixMilli() <= current || s.primaryUntil.CompareAndSwap(current, until.UnixMilli()) {
+			return
+		}
+	}
+}
+
+func WithReadSession(ctx context.Context, session *ReadSession) context.Context {
+	return context.WithValue(ctx, readSessionKey{}, session)
+}
+
+func ReadSessionFromContext(ctx context.Context) (*ReadSession, bool) {
+	session, ok := ctx.Value(readSessionKey{}).(*ReadSession)
+	return session, ok && session != nil
+}
+
+type primaryReadKey struct{}
+
+// WithPrimaryRead sends every read made with ctx to the primary and past any cache, for callers
+// such as caches and write paths that must not keep or act on a row read from a lagging replica.
+func WithPrimaryRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+func PrimaryReadFromContext(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadKey{}).(bool)
+	return primary
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	webhookSecretSize = 32
+)
+
+type WebhookCreate struct {
+	repository repository.WebhookRepository
+	options    webhookOptions
+}
+
+type WebhookInputDTO struct {
+	URL        string   `json:"url" xml:"url"`
+	Secret     string   `json:"secret" xml:"secret"`
+	EventTypes []string `json:"eventTypes" xml:"eventTypes>eventType"`
+	Active     *bool    `json:"active" xml:"active"`
+}
+
+type WebhookOutputDTO struct {
+	ID         int64    `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"eventTypes"`
+	Active     bool     `json:"active"`
+	CreatedAt  string   `json:"createdAt"`
+	UpdatedAt  string   `json:"updatedAt"`
+}
+
+func NewWebhookCreate(webhookRepo repository.WebhookRepository, opts ...WebhookOption) *WebhookCreate {
+	return &WebhookCreate{
+		repository: webhookRepo,
+		options:    newWebhookOptions(opts...),
+	}
+}
+
+func (w *WebhookCreate) Execute(ctx context.Context, input WebhookInputDTO) (WebhookOutputDTO, error) {
+	webhook := entity.NewWebhook()
+	webhook.URL = input.URL
+	webhook.Secret = input.Secret
+	webhook.EventTypes = input.EventTypes
+	webhook.AllowedNetworks = w.options.allowedNetworks
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+	if webhook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return WebhookOutputDTO{}, err
+		}
+		webhook.Secret = secret
+	}
+	if err := webhook.IsValid(); err != nil {
+		return WebhookOutputDTO{}, err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	defer cancel()
+
+	webhookData, err := w.repository.Insert(ctxWithTimeout, repository.WebhookInput{
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		EventTypes: webhook.EventTypes,
+		Active:     webhook.Active,
+	})
+	if err != nil {
+		slog.Error("impossible to create webhook", slog.Any("msg", err))
+		return WebhookOutputDTO{}, err
+	}
+
+	outputDTO := toWebhookOutputDTO(webhookData)
+	outputDTO.Secret = webhookData.Secret
+	return outputDTO, nil
+}
+
+func toWebhookOutputDTO(webhookData repository.WebhookData) WebhookOutputDTO {
+	eventTypes := webhookData.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return WebhookOutputDTO{
+		ID:         webhookData.ID,
+		URL:        webhookData.URL,
+		EventTypes: eventTypes,
+		Active:     webhookData.Active,
+		CreatedAt:  webhookData.CreatedAt,
+		UpdatedAt:  webhookData.UpdatedAt,
+	}
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,81 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookCreate_Execute(t *testing.T) {
+	t.Run("Should create a webhook generating its secret", webhookCreateGeneratedSecret)
+	t.Run("Should results a error if url is invalid", webhookCreateInvalidURLErr)
+	t.Run("Should results a error if event type is unknown", webhookCreateInvalidEventTypeErr)
+}
+
+func webhookCreateGeneratedSecret(t *testing.T) {
+	webhookCreate := usecase.NewWebhookCreate(repository.NewWebhookRepositoryInMemory())
+	outputDTO, err := webhookCreate.Execute(context.TODO(), usecase.WebhookInputDTO{
+		URL:        "https://hooks.example.com/products",
+		EventTypes: []string{entity.ProductCreatedEvent},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), outputDTO.ID)
+	assert.True(t, outputDTO.Active)
+	assert.Len(t, outputDTO.Secret, 64)
+}
+
+func webhookCreateInvalidURLErr(t *testing.T) {
+	webhookCreate := usecase.NewWebhookCreate(repository.NewWebhookRepositoryInMemory())
+	_, err := webhookCreate.Execute(context.TODO(), usecase.WebhookInputDTO{
+		URL: "ftp://hooks.example.com",
+	})
+	assert.ErrorIs(t, err, entity.InvalidWebhookURLErr)
+}
+
+func webhookCreateInvalidEventTypeErr(t *testing.T) {
+	webhookCreate := usecase.NewWebhookCreate(repository.NewWebhookRepositoryInMemory())
+	_, err := webhookCreate.Execute(context.TODO(), usecase.WebhookInputDTO{
+		URL:        "https://hooks.example.com/products",
+		EventTypes: []string{"product.sold"},
+	})
+	assert.ErrorIs(t, err, entity.InvalidEventTypeErr)
+}
+
+func TestWebhookUpdate_Execute(t *testing.T) {
+	t.Run("Should keep the secret when none is given", webhookUpdateKeepSecret)
+	t.Run("Should results a error if webhook not found", webhookUpdateNotFoundErr)
+}
+
+func webhookUpdateKeepSecret(t *testing.T) {
+	webhookRepo := repository.NewWebhookRepositoryInMemory()
+	created, err := usecase.NewWebhookCreate(webhookRepo).Execute(context.TODO(), usecase.WebhookInputDTO{
+		URL:    "https://hooks.example.com/products",
+		Secret: "s3cr3t",
+	})
+	assert.Nil(t, err)
+
+	active := false
+	_, err = usecase.NewWebhookUpdate(webhookRepo).Execute(context.TODO(), created.ID, usecase.WebhookInputDTO{
+		URL:    "https://hooks.example.com/v2/products",
+		Active: &active,
+	})
+	assert.Nil(t, err)
+
+	webhookData, err := webhookRepo.GetByID(context.TODO(), created.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t", webhookData.Secret)
+	assert.Equal(t, "https://hooks.example.com/v2/products", webhookData.URL)
+	assert.False(t, webhookData.Active)
+}
+
+func webhookUpdateNotFoundErr(t *testing.T) {
+	webhookUpdate := usecase.NewWebhookUpdate(repository.NewWebhookRepositoryInMemory())
+	_, err := webhookUpdate.Execute(context.TODO(), 42, usecase.WebhookInputDTO{
+		URL: "https://hooks.example.com/products",
+	})
+	assert.ErrorIs(t, err, entity.WebhookNotFoundErr)
+}
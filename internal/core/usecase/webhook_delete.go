@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type WebhookDelete struct {
+	repository repository.WebhookRepository
+}
+
+func NewWebhookDelete(webhookRepo repository.WebhookRepository) *WebhookDelete {
+	return &WebhookDelete{
+		repository: webhookRepo,
+	}
+}
+
+func (w *WebhookDelete) Execute(ctx context.Context, id int64) error {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	defer cancel()
+
+	if err := w.repository.DeleteByID(ctxWithTimeout, id); err != nil {
+		slog.Error("impossible to delete webhook", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
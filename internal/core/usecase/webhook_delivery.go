@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type WebhookDeliverer interface {
+	Deliver(ctx context.Context, delivery repository.WebhookDeliveryData) repository.WebhookDeliveryData
+}
+
+type WebhookDeliveryList struct {
+	repository repository.WebhookDeliveryRepository
+}
+
+type WebhookRedeliver struct {
+	repository repository.WebhookDeliveryRepository
+	deliverer  WebhookDeliverer
+}
+
+type WebhookDeliveryListInputDTO struct {
+	Status   string
+	Page     int
+	PageSize int
+}
+
+type WebhookDeliveryOutputDTO struct {
+	ID        int64  `json:"id"`
+	WebhookID int64  `json:"webhookId"`
+	EventID   string `json:"eventId"`
+	EventType string `json:"eventType"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"lastError,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type WebhookDeliveryListOutputDTO struct {
+	Items    []WebhookDeliveryOutputDTO `json:"items"`
+	Page     int                        `json:"page"`
+	PageSize int                        `json:"pageSize"`
+	Total    int64                      `json:"total"`
+}
+
+func NewWebhookDeliveryList(deliveryRepo repository.WebhookDeliveryRepository) *WebhookDeliveryList {
+	return &WebhookDeliveryList{
+		repository: deliveryRepo,
+	}
+}
+
+func (w *WebhookDeliveryList) Execute(ctx context.Context,
+	input WebhookDeliveryListInputDTO) (WebhookDeliveryListOutputDTO, error) {
+	page, pageSize, err := paginate(input.Page, input.PageSize)
+	if err != nil {
+		return WebhookDeliveryListOutputDTO{}, err
+	}
+	if input.Status == "" {
+		input.Status = repository.WebhookDeliveryDead
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	defer cancel()
+
+	deliveriesData, total, err := w.repository.List(ctxWithTimeout, repository.WebhookDeliveryFilter{
+		Status: input.Status,
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		slog.Error("impossible to list webhook deliveries", slog.Any("msg", err))
+		return WebhookDeliveryListOutputDTO{}, err
+	}
+
+	items := make([]WebhookDeliveryOutputDTO, 0, len(deliveriesData))
+	for _, deliveryData := range deliveriesData {
+		items = append(items, toWebhookDeliveryOutputDTO(deliveryData))
+	}
+	return WebhookDeliveryListOutputDTO{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	}, nil
+}
+
+func NewWebhookRedeliver(deliveryRepo repository.WebhookDeliveryRepository,
+	deliverer WebhookDeliverer) *WebhookRedeliver {
+	return &WebhookRedeliver{
+		repository: deliveryRepo,
+		deliverer:  deliverer,
+	}
+}
+
+func (w *WebhookRedeliver) Execute(ctx context.Context, id int64) (WebhookDeliveryOutputDTO, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	defer cancel()
+
+	deliveryData, err := w.repository.GetByID(ctxWithTimeout, id)
+	if err != nil {
+		slog.Error("impossible to redeliver webhook", slog.Any("msg", err))
+		return WebhookDeliveryOutputDTO{}, err
+	}
+	return toWebhookDeliveryOutputDTO(w.deliverer.Deliver(ctxWithTimeout, deliveryData)), nil
+}
+
+func toWebhookDeliveryOutputDTO(deliveryData repository.WebhookDeliveryData) WebhookDeliveryOutputDTO {
+	return WebhookDeliveryOutputDTO{
+		ID:        deliveryData.ID,
+		WebhookID: deliveryData.WebhookID,
+		EventID:   deliveryData.EventID,
+		EventType: deliveryData.EventType,
+		Status:    deliveryData.Status,
+		Attempts:  deliveryData.Attempts,
+		LastError: deliveryData.LastError,
+		CreatedAt: deliveryData.CreatedAt,
+		UpdatedAt: deliveryData.UpdatedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type WebhookGet struct {
+	repository repository.WebhookRepository
+}
+
+func NewWebhookGet(webhookRepo repository.WebhookRepository) *WebhookGet {
+	return &WebhookGet{
+		repository: webhookRepo,
+	}
+}
+
+func (w *WebhookGet) Execute(ctx context.Context, id int64) (WebhookOutputDTO, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	defer cancel()
+
+	webhookData, err := w.repository.GetByID(ctxWithTimeout, id)
+	if err != nil {
+		slog.Error("impossible to get webhook", slog.Any("msg", err))
+		return WebhookOutputDTO{}, err
+	}
+	return toWebhookOutputDTO(webhookData), nil
+}
+
+func (w *WebhookGet) ExecuteAll(ctx context.Context) ([]WebhookOutputDTO, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	defer cancel()
+
+	webhooksData, err := w.repository.List(ctxWithTimeout)
+	if err != nil {
+		slog.Error("impossible to list webhooks", slog.Any("msg", err))
+		return nil, err
+	}
+	outputDTOs := make([]WebhookOutputDTO, 0, len(webhooksData))
+	for _, webhookData := range webhooksData {
+		outputDTOs = append(outputDTOs, toWebhookOutputDTO(webhookData))
+	}
+	return outputDTOs, nil
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import "net/netip"
+
+type WebhookOption func(*webhookOptions)
+
+type webhookOptions struct {
+	allowedNetworks []netip.Prefix
+}
+
+// WithAllowedNetworks lets webhooks target the given internal networks, which
+// are refused by default.
+func WithAllowedNetworks(networks []netip.Prefix) WebhookOption {
+	return func(options *webhookOptions) {
+		options.allowedNetworks = networks
+	}
+}
+
+func newWebhookOptions(opts ...WebhookOption) webhookOptions {
+	options := webhookOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type WebhookUpdate struct {
+	repository repository.WebhookRepository
+	options    webhookOptions
+}
+
+func NewWebhookUpdate(webhookRepo repository.WebhookRepository, opts ...WebhookOption) *WebhookUpdate {
+	return &WebhookUpdate{
+		repository: webhookRepo,
+		options:    newWebhookOptions(opts...),
+	}
+}
+
+func (w *WebhookUpdate) Execute(ctx context.Context, id int64, input WebhookInputDTO) (WebhookOutputDTO, error) {
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, time.Duration(ProductDefaultTimeout))
+	defer cancel()
+
+	webhookData, err := w.repository.GetByID(ctxWithTimeout, id)
+	if err != nil {
+		slog.Error("impossible to update webhook", slog.Any("msg", err))
+		return WebhookOutputDTO{}, err
+	}
+
+	webhook := entity.NewWebhook()
+	webhook.ID = webhookData.ID
+	webhook.URL = input.URL
+	webhook.Secret = webhookData.Secret
+	webhook.EventTypes = input.EventTypes
+	webhook.Active = webhookData.Active
+	webhook.AllowedNetworks = w.options.allowedNetworks
+	if input.Secret != "" {
+		webhook.Secret = input.Secret
+	}
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+	if err := webhook.IsValid(); err != nil {
+		return WebhookOutputDTO{}, err
+	}
+
+	updatedData, err := w.repository.Update(ctxWithTimeout, id, repository.WebhookInput{
+		URL:        webhook.URL,
+		Secret:     webhook.Secret,
+		EventTypes: webhook.EventTypes,
+		Active:     webhook.Active,
+	})
+	if err != nil {
+		slog.Error("impossible to update webhook", slog.Any("msg", err))
+		return WebhookOutputDTO{}, err
+	}
+	return toWebhookOutputDTO(updatedData), nil
+}
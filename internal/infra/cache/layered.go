@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type LayeredStore struct {
+	local  *LRU
+	remote ProductStore
+}
+
+func NewLayeredStore(local *LRU, remote ProductStore) *LayeredStore {
+	return &LayeredStore{
+		local:  local,
+		remote: remote,
+	}
+}
+
+func (s *LayeredStore) Get(ctx context.Context, key string) (repository.ProductRepositoryData, bool, error) {
+	if product, ok, _ := s.local.Get(ctx, key); ok {
+		return product, true, nil
+	}
+	product, ok, err := s.remote.Get(ctx, key)
+	if ok {
+		s.local.Set(ctx, key, product)
+	}
+	return product, ok, err
+}
+
+func (s *LayeredStore) Set(ctx context.Context, key string, product repository.ProductRepositoryData) error {
+	err := s.remote.Set(ctx, key, product)
+	if errors.Is(err, ErrInvalidated) {
+		return nil
+	}
+	s.local.Set(ctx, key, product)
+	return err
+}
+
+func (s *LayeredStore) Delete(ctx context.Context, key string) error {
+	return errors.Join(s.local.Delete(ctx, key), s.remote.Delete(ctx, key))
+}
+
+func (s *LayeredStore) Len() int {
+	return s.local.Len()
+}
+
+func (s *LayeredStore) Evictions() int64 {
+	return s.local.Evictions()
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_Get(t *testing.T) {
+	t.Run("Should evict the least recently used product", lruEvictLeastRecentlyUsed)
+	t.Run("Should expire products after the ttl", lruExpire)
+}
+
+func lruEvictLeastRecentlyUsed(t *testing.T) {
+	lru := NewLRU(2, time.Minute)
+	lru.Set(context.TODO(), "a", repository.ProductRepositoryData{Code: "A"})
+	lru.Set(context.TODO(), "b", repository.ProductRepositoryData{Code: "B"})
+	_, ok, _ := lru.Get(context.TODO(), "a")
+	assert.True(t, ok)
+	lru.Set(context.TODO(), "c", repository.ProductRepositoryData{Code: "C"})
+
+	_, ok, _ = lru.Get(context.TODO(), "b")
+	assert.False(t, ok)
+	product, ok, _ := lru.Get(context.TODO(), "a")
+	assert.True(t, ok)
+	assert.Equal(t, "A", product.Code)
+	assert.Equal(t, 2, lru.Len())
+	assert.Equal(t, int64(1), lru.Evictions())
+}
+
+func lruExpire(t *testing.T) {
+	lru := NewLRU(2, time.Millisecond)
+	lru.Set(context.TODO(), "a", repository.ProductRepositoryData{Code: "A"})
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok, _ := lru.Get(context.TODO(), "a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, lru.Len())
+}
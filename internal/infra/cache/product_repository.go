@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"golang.org/x/sync/singleflight"
+)
+
+type ProductStore interface {
+	Get(ctx context.Context, key string) (repository.ProductRepositoryData, bool, error)
+	Set(ctx context.Context, key string, product repository.ProductRepositoryData) error
+	Delete(ctx context.Context, key string) error
+}
+
+type storeStats interface {
+	Len() int
+	Evictions() int64
+}
+
+type Stats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Size      int   `json:"size"`
+	Evictions int64 `json:"evictions"`
+}
+
+type ProductRepositoryCache struct {
+	repository.ProductRepository
+	store      ProductStore
+	loads      singleflight.Group
+	generation atomic.Uint64
+	hits       atomic.Int64
+	misses     atomic.Int64
+}
+
+func NewProductRepository(productRepo repository.ProductRepository, store ProductStore) *ProductRepositoryCache {
+	return &ProductRepositoryCache{
+		ProductRepository: productRepo,
+		store:             store,
+	}
+}
+
+func (r *ProductRepositoryCache) GetByCode(ctx context.Context,
+	code string) (repository.ProductRepositoryData, error) {
+	if err := ctx.Err(); err != nil {
+		return repository.ProductRepositoryData{}, err
+	}
+	if usecase.PrimaryReadFromContext(ctx) {
+		return r.ProductRepository.GetByCode(ctx, code)
+	}
+	key := cacheKey(code)
+	if product, ok := r.cached(ctx, key); ok {
+		return product, nil
+	}
+
+	generation := r.generation.Load()
+	result := r.loads.DoChan(key, func() (any, error) {
+		product, err := r.ProductRepository.GetByCode(usecase.WithPrimaryRead(context.WithoutCancel(ctx)), code)
+		if err != nil {
+			return repository.ProductRepositoryData{}, err
+		}
+		r.fill(ctx, generation, key, product)
+		return product, nil
+	})
+	select {
+	case <-ctx.Done():
+		return repository.ProductRepositoryData{}, ctx.Err()
+	case loaded := <-result:
+		return loaded.Val.(repository.ProductRepositoryData), loaded.Err
+	}
+}
+
+func (r *ProductRepositoryCache) GetByCodeWithFields(ctx context.Context,
+	code string, fields []string) (repository.ProductRepositoryData, error) {
+	if len(fields) == 0 {
+		return r.GetByCode(ctx, code)
+	}
+	return r.ProductRepository.GetByCodeWithFields(ctx, code, fields)
+}
+
+func (r *ProductRepositoryCache) GetByCodes(ctx context.Context,
+	codes []string) ([]repository.ProductRepositoryData, error) {
+	if usecase.PrimaryReadFromContext(ctx) {
+		return r.ProductRepository.GetByCodes(ctx, codes)
+	}
+	products := []repository.ProductRepositoryData{}
+	missingCodes := []string{}
+	for _, code := range codes {
+		if product, ok := r.cached(ctx, cacheKey(code)); ok {
+			products = append(products, product)
+			continue
+		}
+		missingCodes = append(missingCodes, code)
+	}
+	if len(missingCodes) == 0 {
+		return products, nil
+	}
+
+	generation := r.generation.Load()
+	loaded, err := r.ProductRepository.GetByCodes(usecase.WithPrimaryRead(ctx), missingCodes)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range loaded {
+		r.fill(ctx, generation, cacheKey(product.Code), product)
+	}
+	return append(products, loaded...), nil
+}
+
+func (r *ProductRepositoryCache) Update(ctx context.Context, in repository.ProductRepositoryInput) error {
+	defer r.invalidate(ctx, in.Code)
+	return r.ProductRepository.Update(ctx, in)
+}
+
+func (r *ProductRepositoryCache) DeleteByCode(ctx context.Context, code string) (bool, error) {
+	defer r.invalidate(ctx, code)
+	return r.ProductRepository.DeleteByCode(ctx, code)
+}
+
+func (r *ProductRepositoryCache) RestoreByCode(ctx context.Context, code string) error {
+	defer r.invalidate(ctx, code)
+	return r.ProductRepository.RestoreByCode(ctx, code)
+}
+
+func (r *ProductRepositoryCache) Stats() Stats {
+	stats := Stats{
+		Hits:   r.hits.Load(),
+		Misses: r.misses.Load(),
+	}
+	if store, ok := r.store.(storeStats); ok {
+		stats.Size = store.Len()
+		stats.Evictions = store.Evictions()
+	}
+	return stats
+}
+
+func (r *ProductRepositoryCache) cached(ctx context.Context, key string) (repository.ProductRepositoryData, bool) {
+	product, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		slog.Error("impossible to read product cache", slog.Any("msg", err))
+	}
+	if ok {
+		r.hits.Add(1)
+		return product, true
+	}
+	r.misses.Add(1)
+	return repository.ProductRepositoryData{}, false
+}
+
+func (r *ProductRepositoryCache) fill(ctx context.Context, generation uint64, key string,
+	product repository.ProductRepositoryData) {
+	if r.generation.Load() != generation {
+		return
+	}
+	if err := r.store.Set(context.WithoutCancel(ctx), key, product); err != nil {
+		slog.Error("impossible to write product cache", slog.Any("msg", err))
+	}
+}
+
+func (r *ProductRepositoryCache) invalidate(ctx context.Context, code string) {
+	key := cacheKey(code)
+	r.generation.Add(1)
+	r.loads.Forget(key)
+	if err := r.store.Delete(context.WithoutCancel(ctx), key); err != nil {
+		slog.Error("impossible to invalidate product cache", slog.Any("msg", err),
+			slog.String("code", code))
+	}
+}
+
+func cacheKey(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, " ", ""))
+}
@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository/repositorytest"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	infrarepository "github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type productRepositoryCounter struct {
+	repository.ProductRepository
+	loads        atomic.Int32
+	primaryLoads atomic.Int32
+	release      chan struct{}
+}
+
+func newProductRepositoryCounter() *productRepositoryCounter {
+	productRepo := infrarepository.NewProductRepositoryInMemory()
+	productRepo.Insert(context.TODO(), repository.ProductRepositoryInput{
+		Title:        "Toy",
+		Description:  "Blahahhs",
+		Code:         "XSZ-000741",
+		Reference:    "RF009-pods74",
+		PriceInCents: int64(51400),
+	})
+	return &productRepositoryCounter{ProductRepository: productRepo}
+}
+
+func (r *productRepositoryCounter) GetByCode(ctx context.Context,
+	code string) (repository.ProductRepositoryData, error) {
+	r.loads.Add(1)
+	if usecase.PrimaryReadFromContext(ctx) {
+		r.primaryLoads.Add(1)
+	}
+	if r.release != nil {
+		<-r.release
+	}
+	return r.ProductRepository.GetByCode(ctx, code)
+}
+
+func TestProductRepositoryCache_Contract(t *testing.T) {
+	repositorytest.RunProductRepositoryContract(t, func(t *testing.T) repository.ProductRepository {
+		return NewProductRepository(infrarepository.NewProductRepositoryInMemory(), NewLRU(10, time.Minute))
+	})
+}
+
+func TestProductRepositoryCache_GetByCode(t *testing.T) {
+	t.Run("Should read through the cache and count hits and misses", cacheReadThrough)
+	t.Run("Should collapse concurrent misses into a single load", cacheCollapseMisses)
+	t.Run("Should invalidate the product on update and delete", cacheInvalidate)
+	t.Run("Should not cache a load that raced with an invalidation", cacheSkipStaleLoad)
+	t.Run("Should not cache missing products", cacheSkipNotFound)
+	t.Run("Should bypass the cache for primary reads", cacheBypassPrimaryRead)
+}
+
+func cacheBypassPrimaryRead(t *testing.T) {
+	productRepo := newProductRepositoryCounter()
+	cachedRepo := NewProductRepository(productRepo, NewLRU(10, time.Minute))
+
+	_, err := cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+	_, err = cachedRepo.GetByCode(usecase.WithPrimaryRead(context.TODO()), "XSZ-000741")
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(2), productRepo.loads.Load())
+	assert.Equal(t, Stats{Misses: 1, Size: 1}, cachedRepo.Stats())
+}
+
+func cacheReadThrough(t *testing.T) {
+	productRepo := newProductRepositoryCounter()
+	cachedRepo := NewProductRepository(productRepo, NewLRU(10, time.Minute))
+
+	for index := 0; index < 3; index++ {
+		product, err := cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+		assert.NoError(t, err)
+		assert.Equal(t, "Toy", product.Title)
+	}
+	_, err := cachedRepo.GetByCode(context.TODO(), " xsz-000741")
+	assert.NoError(t, err)
+
+	assert.Equal(t, int32(1), productRepo.loads.Load())
+	assert.Equal(t, int32(1), productRepo.primaryLoads.Load())
+	assert.Equal(t, Stats{Hits: 3, Misses: 1, Size: 1}, cachedRepo.Stats())
+}
+
+func cacheCollapseMisses(t *testing.T) {
+	productRepo := newProductRepositoryCounter()
+	productRepo.release = make(chan struct{})
+	cachedRepo := NewProductRepository(productRepo, NewLRU(10, time.Minute))
+
+	var wg sync.WaitGroup
+	for index := 0; index < 10; index++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			product, err := cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+			assert.NoError(t, err)
+			assert.Equal(t, "Toy", product.Title)
+		}()
+	}
+	assert.Eventually(t, func() bool {
+		return cachedRepo.Stats().Misses == 10
+	}, time.Second, time.Millisecond)
+	close(productRepo.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), productRepo.loads.Load())
+}
+
+func cacheInvalidate(t *testing.T) {
+	productRepo := newProductRepositoryCounter()
+	cachedRepo := NewProductRepository(productRepo, NewLRU(10, time.Minute))
+
+	_, err := cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+	assert.NoError(t, cachedRepo.Update(context.TODO(), repository.ProductRepositoryInput{Code: "XSZ-000741"}))
+	_, err = cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+	_, err = cachedRepo.DeleteByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+	_, err = cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.ErrorIs(t, err, entity.ProductNotFoundErr)
+
+	assert.Equal(t, int32(3), productRepo.loads.Load())
+}
+
+func cacheSkipStaleLoad(t *testing.T) {
+	productRepo := newProductRepositoryCounter()
+	productRepo.release = make(chan struct{})
+	cachedRepo := NewProductRepository(productRepo, NewLRU(10, time.Minute))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	}()
+	assert.Eventually(t, func() bool {
+		return productRepo.loads.Load() == 1
+	}, time.Second, time.Millisecond)
+	_, err := cachedRepo.DeleteByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+	close(productRepo.release)
+	<-done
+
+	assert.Equal(t, 0, cachedRepo.Stats().Size)
+}
+
+func cacheSkipNotFound(t *testing.T) {
+	cachedRepo := NewProductRepository(infrarepository.ProductRepositoryInMemorySpy{
+		ExpectedError: entity.ProductNotFoundErr,
+	}, NewLRU(10, time.Minute))
+
+	_, err := cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.ErrorIs(t, err, entity.ProductNotFoundErr)
+	assert.Equal(t, 0, cachedRepo.Stats().Size)
+}
@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	RedisBackend = "redis"
+
+	redisKeyPrefix           = "product:"
+	redisInvalidationChannel = "product-cache-invalidation"
+	redisRetryInterval       = 5 * time.Second
+	redisTombstone           = "invalidated"
+	redisTombstoneTTL        = 30 * time.Second
+)
+
+var ErrInvalidated = errors.New("product cache entry was invalidated while it was loaded")
+
+// redisSetScript refuses to overwrite a tombstone, so a fill that read the database before an
+// invalidation cannot bring the old row back after it.
+var redisSetScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[2] then
+	return 0
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+return 1`)
+
+type RedisOptions struct {
+	Addr     string
+	Password string
+	DB       int
+	Timeout  time.Duration
+	TTL      time.Duration
+}
+
+type RedisStore struct {
+	client           *redis.Client
+	ttl              time.Duration
+	unavailableUntil atomic.Int64
+	mu               sync.Mutex
+	pending          map[string]struct{}
+	done             chan struct{}
+}
+
+func NewRedisStore(options RedisOptions) *RedisStore {
+	store := &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:         options.Addr,
+			Password:     options.Password,
+			DB:           options.DB,
+			DialTimeout:  options.Timeout,
+			ReadTimeout:  options.Timeout,
+			WriteTimeout: options.Timeout,
+		}),
+		ttl:     options.TTL,
+		pending: map[string]struct{}{},
+		done:    make(chan struct{}),
+	}
+	go store.retryInvalidations()
+	return store
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (repository.ProductRepositoryData, bool, error) {
+	if !s.available() {
+		return repository.ProductRepositoryData{}, false, nil
+	}
+	payload, err := s.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return repository.ProductRepositoryData{}, false, nil
+	}
+	if err != nil {
+		s.markUnavailable()
+		return repository.ProductRepositoryData{}, false, err
+	}
+	if string(payload) == redisTombstone {
+		return repository.ProductRepositoryData{}, false, nil
+	}
+
+	var product repository.ProductRepositoryData
+	if err := json.Unmarshal(payload, &product); err != nil {
+		return repository.ProductRepositoryData{}, false, err
+	}
+	return product, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, product repository.ProductRepositoryData) error {
+	if !s.available() {
+		return nil
+	}
+	payload, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+	written, err := redisSetScript.Run(ctx, s.client, []string{redisKeyPrefix + key},
+		payload, redisTombstone, s.ttl.Milliseconds()).Int()
+	if err != nil {
+		s.markUnavailable()
+		return err
+	}
+	if written == 0 {
+		return ErrInvalidated
+	}
+	return nil
+}
+
+// Delete replaces the product with a short-lived tombstone and notifies the other nodes. When
+// Redis cannot be reached the invalidation is queued and retried in the background, and this
+// node stops reading from Redis until every queued invalidation has been applied.
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if err := s.invalidate(ctx, key); err != nil {
+		s.mu.Lock()
+		s.pending[key] = struct{}{}
+		s.mu.Unlock()
+		s.markUnavailable()
+		return err
+	}
+	return nil
+}
+
+func (s *RedisStore) Subscribe(ctx context.Context, local *LRU) (func() error, error) {
+	pubsub := s.client.Subscribe(ctx, redisInvalidationChannel)
+	_, err := pubsub.Receive(ctx)
+	if err != nil {
+		slog.Error("impossible to subscribe to product cache invalidations", slog.Any("msg", err))
+	}
+	go func() {
+		for message := range pubsub.ChannelWithSubscriptions() {
+			switch message := message.(type) {
+			case *redis.Subscription:
+				local.Purge()
+			case *redis.Message:
+				local.Delete(ctx, message.Payload)
+			}
+		}
+	}()
+	return pubsub.Close, err
+}
+
+func (s *RedisStore) Close() error {
+	close(s.done)
+	return s.client.Close()
+}
+
+func (s *RedisStore) invalidate(ctx context.Context, key string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, redisKeyPrefix+key, redisTombstone, redisTombstoneTTL)
+		pipe.Publish(ctx, redisInvalidationChannel, key)
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) retryInvalidations() {
+	ticker := time.NewTicker(redisRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.flushInvalidations(context.Background())
+		}
+	}
+}
+
+func (s *RedisStore) flushInvalidations(ctx context.Context) {
+	s.mu.Lock()
+	keys := make([]string, 0, len(s.pending))
+	for key := range s.pending {
+		keys = append(keys, key)
+	}
+	s.mu.Unlock()
+
+	for _, key := range keys {
+		if err := s.invalidate(ctx, key); err != nil {
+			slog.Error("impossible to retry product cache invalidation", slog.Any("msg", err),
+				slog.String("key", key))
+			s.markUnavailable()
+			return
+		}
+		s.mu.Lock()
+		delete(s.pending, key)
+		s.mu.Unlock()
+	}
+}
+
+func (s *RedisStore) available() bool {
+	s.mu.Lock()
+	pending := len(s.pending)
+	s.mu.Unlock()
+	return pending == 0 && time.Now().UnixNano() >= s.unavailableUntil.Load()
+}
+
+func (s *RedisStore) markUnavailable() {
+	s.unavailableUntil.Store(time.Now().Add(redisRetryInterval).UnixNano())
+}
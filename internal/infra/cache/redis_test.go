@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheNode struct {
+	local      *LRU
+	redisStore *RedisStore
+	cachedRepo *ProductRepositoryCache
+	productDB  *productRepositoryCounter
+}
+
+func newCacheNode(t *testing.T, addr string) cacheNode {
+	local := NewLRU(10, time.Minute)
+	redisStore := NewRedisStore(RedisOptions{Addr: addr, Timeout: 100 * time.Millisecond, TTL: time.Minute})
+	t.Cleanup(func() { redisStore.Close() })
+	unsubscribe, err := redisStore.Subscribe(context.Background(), local)
+	assert.NoError(t, err)
+	t.Cleanup(func() { unsubscribe() })
+
+	productDB := newProductRepositoryCounter()
+	return cacheNode{
+		local:      local,
+		redisStore: redisStore,
+		cachedRepo: NewProductRepository(productDB, NewLayeredStore(local, redisStore)),
+		productDB:  productDB,
+	}
+}
+
+func TestRedisStore_Get(t *testing.T) {
+	t.Run("Should share cached products between nodes", redisShareBetweenNodes)
+	t.Run("Should broadcast invalidations to every node", redisBroadcastInvalidation)
+	t.Run("Should fall back to the database when redis is unreachable", redisFallbackToDatabase)
+}
+
+func TestRedisStore_Delete(t *testing.T) {
+	t.Run("Should not let a late fill overwrite an invalidation", redisRejectLateFill)
+	t.Run("Should retry invalidations that failed", redisRetryFailedInvalidation)
+}
+
+func redisRejectLateFill(t *testing.T) {
+	server := miniredis.RunT(t)
+	node := newCacheNode(t, server.Addr())
+
+	assert.NoError(t, node.redisStore.Delete(context.TODO(), "xsz-000741"))
+	err := node.redisStore.Set(context.TODO(), "xsz-000741", repository.ProductRepositoryData{Title: "Old"})
+	assert.ErrorIs(t, err, ErrInvalidated)
+
+	_, ok, err := node.redisStore.Get(context.TODO(), "xsz-000741")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func redisRetryFailedInvalidation(t *testing.T) {
+	server := miniredis.RunT(t)
+	node := newCacheNode(t, server.Addr())
+	_, err := node.cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+
+	server.Close()
+	assert.Error(t, node.redisStore.Delete(context.TODO(), "xsz-000741"))
+	assert.False(t, node.redisStore.available())
+
+	assert.NoError(t, server.Restart())
+	node.redisStore.flushInvalidations(context.TODO())
+	tombstone, err := server.Get("product:xsz-000741")
+	assert.NoError(t, err)
+	assert.Equal(t, redisTombstone, tombstone)
+	assert.Empty(t, node.redisStore.pending)
+}
+
+func redisShareBetweenNodes(t *testing.T) {
+	server := miniredis.RunT(t)
+	nodeA := newCacheNode(t, server.Addr())
+	nodeB := newCacheNode(t, server.Addr())
+
+	_, err := nodeA.cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+	product, err := nodeB.cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Toy", product.Title)
+	assert.Equal(t, int32(1), nodeA.productDB.loads.Load())
+	assert.Equal(t, int32(0), nodeB.productDB.loads.Load())
+	assert.True(t, server.Exists("product:xsz-000741"))
+}
+
+func redisBroadcastInvalidation(t *testing.T) {
+	server := miniredis.RunT(t)
+	nodeA := newCacheNode(t, server.Addr())
+	nodeB := newCacheNode(t, server.Addr())
+
+	_, err := nodeB.cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, nodeB.local.Len())
+
+	assert.NoError(t, nodeA.cachedRepo.Update(context.TODO(),
+		repository.ProductRepositoryInput{Code: "XSZ-000741"}))
+
+	tombstone, err := server.Get("product:xsz-000741")
+	assert.NoError(t, err)
+	assert.Equal(t, redisTombstone, tombstone)
+	assert.Eventually(t, func() bool {
+		return nodeB.local.Len() == 0
+	}, time.Second, time.Millisecond)
+}
+
+func redisFallbackToDatabase(t *testing.T) {
+	server := miniredis.RunT(t)
+	node := newCacheNode(t, server.Addr())
+	server.Close()
+
+	for index := 0; index < 2; index++ {
+		product, err := node.cachedRepo.GetByCode(context.TODO(), "XSZ-000741")
+		assert.NoError(t, err)
+		assert.Equal(t, "Toy", product.Title)
+		node.local.Purge()
+	}
+	assert.Equal(t, int32(2), node.productDB.loads.Load())
+	assert.False(t, node.redisStore.available())
+}
@@ -1,62 +1,142 @@
 package config
 
 import (
-	"bytes"
+	"errors"
+	"fmt"
 	"log"
-	"os"
+	"net/netip"
 	"strings"
 
 	"github.com/caarlos0/env/v10"
-	"github.com/joho/godotenv"
 )
 
 type DatabaseConfig struct {
-	Host                string `env:"DATABASE_HOST,required"`
-	User                string `env:"DATABASE_USER,required"`
-	Password            string `env:"DATABASE_PASSWORD,required"`
-	Name                string `env:"DATABASE_NAME,required"`
-	Port                int    `env:"DATABASE_PORT,required"`
-	MaxConnections      int    `env:"DATABASE_MAX_CONNECTIONS,required"`
-	MaxIdleConnections  int    `env:"DATABASE_MAX_IDLE_CONNECTIONS" required:"true"`
-	DefaultQueryTimeout int    `env:"DATABASE_DEFAULT_QUERY_TIMEOUT_SECS" required:"true"`
+	Driver                 string   `env:"DATABASE_DRIVER" envDefault:"mysql"`
+	Host                   string   `env:"DATABASE_HOST"`
+	User                   string   `env:"DATABASE_USER"`
+	Password               string   `env:"DATABASE_PASSWORD"`
+	Name                   string   `env:"DATABASE_NAME"`
+	Port                   int      `env:"DATABASE_PORT"`
+	Path                   string   `env:"DATABASE_PATH" envDefault:"eulabs.db"`
+	MaxConnections         int      `env:"DATABASE_MAX_CONNECTIONS,required"`
+	MaxIdleConnections     int      `env:"DATABASE_MAX_IDLE_CONNECTIONS" required:"true"`
+	DefaultQueryTimeout    int      `env:"DATABASE_DEFAULT_QUERY_TIMEOUT_SECS" required:"true"`
+	ReplicaHosts           []string `env:"DATABASE_REPLICA_HOSTS"`
+	ReplicaHealthCheckSecs int      `env:"DATABASE_REPLICA_HEALTH_CHECK_SECS" envDefault:"5"`
+	ReadYourWritesMillis   int      `env:"DATABASE_READ_YOUR_WRITES_MILLIS" envDefault:"1000"`
+	ConnMaxLifetimeSecs    int      `env:"DATABASE_CONN_MAX_LIFETIME_SECS" envDefault:"0"`
+	ConnMaxIdleTimeSecs    int      `env:"DATABASE_CONN_MAX_IDLE_TIME_SECS" envDefault:"0"`
+	TLSMode                string   `env:"DATABASE_TLS_MODE"`
+	TLSCAFile              string   `env:"DATABASE_TLS_CA_FILE"`
+	TLSCertFile            string   `env:"DATABASE_TLS_CERT_FILE"`
+	TLSKeyFile             string   `env:"DATABASE_TLS_KEY_FILE"`
+	TLSServerName          string   `env:"DATABASE_TLS_SERVER_NAME"`
+	DialTimeoutSecs        int      `env:"DATABASE_DIAL_TIMEOUT_SECS" envDefault:"0"`
+	ReadTimeoutSecs        int      `env:"DATABASE_READ_TIMEOUT_SECS" envDefault:"0"`
+	WriteTimeoutSecs       int      `env:"DATABASE_WRITE_TIMEOUT_SECS" envDefault:"0"`
+	ParseTime              bool     `env:"DATABASE_PARSE_TIME" envDefault:"false"`
 }
 
-type Config struct {
-	AppServerPort string `env:"PORT,required"`
-	Database      DatabaseConfig
-}
-
-func extractCurrentDir() string {
-	currentDir, err := os.Getwd()
-	if err != nil {
-		log.Fatal(err)
+func (c DatabaseConfig) validate(values map[string]string) error {
+	if c.Driver == "sqlite" {
+		return nil
 	}
-	if _, err := os.Stat(currentDir + "/.env"); err != nil {
-		tokens := strings.Split(currentDir, "/")
-		var buf bytes.Buffer
-		for index := 0; index < len(tokens)-1; index++ {
-			buf.WriteString(tokens[index])
-			if index != len(tokens)-2 {
-				buf.WriteString("/")
-			}
+	var missing []string
+	for _, key := range []string{"DATABASE_HOST", "DATABASE_USER", "DATABASE_PASSWORD",
+		"DATABASE_NAME", "DATABASE_PORT"} {
+		if _, ok := values[key]; !ok {
+			missing = append(missing, key)
 		}
-		return buf.String()
 	}
-	return currentDir
+	if len(missing) > 0 {
+		return fmt.Errorf("required settings %s are not set for driver %q",
+			strings.Join(missing, ", "), c.Driver)
+	}
+	return nil
 }
 
-func Load() Config {
-	currentDir := extractCurrentDir()
-	err := godotenv.Load(currentDir + "/.env")
-	if err != nil {
-		log.Fatalf("unable to load .env file: %v", err)
-	}
+type PurgeConfig struct {
+	RetentionDays   int `env:"PURGE_RETENTION_DAYS" envDefault:"30"`
+	IntervalMinutes int `env:"PURGE_INTERVAL_MINUTES" envDefault:"60"`
+}
+
+type WebhookConfig struct {
+	MaxAttempts     int            `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+	BackoffMillis   int            `env:"WEBHOOK_BACKOFF_MILLIS" envDefault:"500"`
+	TimeoutSecs     int            `env:"WEBHOOK_TIMEOUT_SECS" envDefault:"10"`
+	Workers         int            `env:"WEBHOOK_WORKERS" envDefault:"4"`
+	IntervalMillis  int            `env:"WEBHOOK_INTERVAL_MILLIS" envDefault:"1000"`
+	AllowedNetworks []netip.Prefix `env:"WEBHOOK_ALLOWED_NETWORKS"`
+}
 
+type OutboxConfig struct {
+	Publisher      string `env:"OUTBOX_PUBLISHER" envDefault:"log"`
+	FilePath       string `env:"OUTBOX_FILE_PATH" envDefault:"outbox.jsonl"`
+	HTTPURL        string `env:"OUTBOX_HTTP_URL"`
+	TimeoutSecs    int    `env:"OUTBOX_TIMEOUT_SECS" envDefault:"10"`
+	BatchSize      int    `env:"OUTBOX_BATCH_SIZE" envDefault:"100"`
+	IntervalMillis int    `env:"OUTBOX_INTERVAL_MILLIS" envDefault:"1000"`
+	MaxAttempts    int    `env:"OUTBOX_MAX_ATTEMPTS" envDefault:"10"`
+	BackoffMillis  int    `env:"OUTBOX_BACKOFF_MILLIS" envDefault:"1000"`
+	MaxBackoffSecs int    `env:"OUTBOX_MAX_BACKOFF_SECS" envDefault:"300"`
+}
+
+type StreamConfig struct {
+	LogSize       int `env:"SSE_LOG_SIZE" envDefault:"1000"`
+	HeartbeatSecs int `env:"SSE_HEARTBEAT_SECS" envDefault:"15"`
+}
+
+type CacheConfig struct {
+	Enabled            bool   `env:"CACHE_ENABLED" envDefault:"false"`
+	Backend            string `env:"CACHE_BACKEND" envDefault:"memory"`
+	Size               int    `env:"CACHE_SIZE" envDefault:"10000"`
+	TTLSecs            int    `env:"CACHE_TTL_SECS" envDefault:"60"`
+	LocalTTLSecs       int    `env:"CACHE_LOCAL_TTL_SECS" envDefault:"5"`
+	RedisAddr          string `env:"CACHE_REDIS_ADDR" envDefault:"localhost:6379"`
+	RedisPassword      string `env:"CACHE_REDIS_PASSWORD"`
+	RedisDB            int    `env:"CACHE_REDIS_DB" envDefault:"0"`
+	RedisTimeoutMillis int    `env:"CACHE_REDIS_TIMEOUT_MILLIS" envDefault:"100"`
+}
+
+type TimeoutConfig struct {
+	CreateSecs int `env:"PRODUCT_CREATE_TIMEOUT_SECS" envDefault:"30"`
+	GetSecs    int `env:"PRODUCT_GET_TIMEOUT_SECS" envDefault:"30"`
+	UpdateSecs int `env:"PRODUCT_UPDATE_TIMEOUT_SECS" envDefault:"30"`
+	DeleteSecs int `env:"PRODUCT_DELETE_TIMEOUT_SECS" envDefault:"30"`
+	ListSecs   int `env:"PRODUCT_LIST_TIMEOUT_SECS" envDefault:"30"`
+}
+
+type Config struct {
+	AppServerPort  string `env:"PORT,required"`
+	GrpcServerPort string `env:"GRPC_PORT" envDefault:"9090"`
+	AdminAddr      string `env:"ADMIN_ADDR" envDefault:"127.0.0.1:6060"`
+	Database       DatabaseConfig
+	Purge          PurgeConfig
+	Webhook        WebhookConfig
+	Outbox         OutboxConfig
+	Stream         StreamConfig
+	Cache          CacheConfig
+	Timeouts       TimeoutConfig
+	Secrets        SecretProvider
+}
+
+func Load(opts Options) (Config, error) {
 	log.Default().SetPrefix("\r")
 
-	cfg := Config{}
-	if err := env.Parse(&cfg); err != nil {
-		log.Fatalf("unable to parse .env file: %v", err)
+	values, err := resolve(opts)
+	if err != nil {
+		return Config{}, err
+	}
+
+	merged := values.merged()
+	secretFiles, errs := resolveSecretFiles(merged)
+	cfg := Config{Secrets: ChainSecretProvider{NewFileSecretProvider(secretFiles), NewEnvSecretProvider(merged)}}
+	if err := env.ParseWithOptions(&cfg, env.Options{Environment: merged}); err != nil {
+		errs = append(errs, err)
+	}
+	if err := cfg.Database.validate(merged); err != nil {
+		errs = append(errs, err)
 	}
-	return cfg
+	errs = append(errs, values.unknownKeys()...)
+	return cfg, errors.Join(errs...)
 }
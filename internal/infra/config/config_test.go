@@ -0,0 +1,214 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Should load without a .env file", shouldLoadWithoutEnvFile)
+	t.Run("Should apply sources in precedence order", shouldApplySourcesInPrecedenceOrder)
+	t.Run("Should read TOML config files", shouldReadTOMLConfigFiles)
+	t.Run("Should aggregate validation errors", shouldAggregateValidationErrors)
+	t.Run("Should reject unknown settings in config files", shouldRejectUnknownSettings)
+	t.Run("Should fail when an explicit .env file is missing", shouldFailWhenExplicitEnvFileIsMissing)
+}
+
+func shouldLoadWithoutEnvFile(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_MAX_CONNECTIONS", "5")
+
+	cfg, err := Load(Options{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "8080", cfg.AppServerPort)
+	assert.Equal(t, "eulabs.db", cfg.Database.Path)
+	assert.Equal(t, 5, cfg.Database.MaxConnections)
+}
+
+func shouldApplySourcesInPrecedenceOrder(t *testing.T) {
+	file := writeConfigFile(t, "config.yaml", `
+port: 7000
+grpc-port: 7001
+database:
+  driver: sqlite
+  max_connections: 1
+  replica_hosts: [replica-1, replica-2]
+cache:
+  size: 10
+`)
+	envFile := writeConfigFile(t, ".env", "GRPC_PORT=8001\nDATABASE_MAX_CONNECTIONS=2\n")
+	t.Setenv("DATABASE_MAX_CONNECTIONS", "3")
+
+	cfg, err := Load(Options{File: file, EnvFile: envFile, Flags: map[string]string{"CACHE_SIZE": "20"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "7000", cfg.AppServerPort)
+	assert.Equal(t, "8001", cfg.GrpcServerPort)
+	assert.Equal(t, 3, cfg.Database.MaxConnections)
+	assert.Equal(t, []string{"replica-1", "replica-2"}, cfg.Database.ReplicaHosts)
+	assert.Equal(t, 20, cfg.Cache.Size)
+	assert.Equal(t, 1000, cfg.Stream.LogSize)
+}
+
+func shouldReadTOMLConfigFiles(t *testing.T) {
+	file := writeConfigFile(t, "config.toml", `
+port = 7000
+
+[database]
+driver = "sqlite"
+max_connections = 4
+`)
+
+	cfg, err := Load(Options{File: file})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "7000", cfg.AppServerPort)
+	assert.Equal(t, 4, cfg.Database.MaxConnections)
+}
+
+func shouldAggregateValidationErrors(t *testing.T) {
+	file := writeConfigFile(t, "config.yaml", "database:\n  driver: mysql\n  port: nope\n")
+
+	_, err := Load(Options{File: file})
+
+	assert.ErrorContains(t, err, `"PORT" is not set`)
+	assert.ErrorContains(t, err, `parsing "nope"`)
+	assert.ErrorContains(t, err, "required settings DATABASE_HOST, DATABASE_USER")
+}
+
+func shouldRejectUnknownSettings(t *testing.T) {
+	file := writeConfigFile(t, "config.yaml", "port: 7000\ndatabase:\n  driver: sqlite\n  max_connections: 1\n  hots: db\n")
+
+	_, err := Load(Options{File: file})
+
+	assert.ErrorContains(t, err, "unknown setting DATABASE_HOTS")
+}
+
+func shouldFailWhenExplicitEnvFileIsMissing(t *testing.T) {
+	_, err := Load(Options{EnvFile: filepath.Join(t.TempDir(), ".env")})
+
+	assert.ErrorContains(t, err, "unable to load")
+}
+
+func TestSettings(t *testing.T) {
+	t.Run("Should report the source of every setting", shouldReportSettingSources)
+	t.Run("Should redact secrets", shouldRedactSecrets)
+}
+
+func shouldReportSettingSources(t *testing.T) {
+	file := writeConfigFile(t, "config.yaml", "database:\n  driver: sqlite\n")
+	t.Setenv("PORT", "8080")
+
+	settings, err := Settings(Options{File: file, Flags: map[string]string{"CACHE_SIZE": "20"}})
+
+	assert.Nil(t, err)
+	sources := map[string]Setting{}
+	for _, setting := range settings {
+		sources[setting.Key] = setting
+	}
+	assert.Equal(t, Setting{Key: "DATABASE_DRIVER", Value: "sqlite", Source: file}, sources["DATABASE_DRIVER"])
+	assert.Equal(t, Setting{Key: "PORT", Value: "8080", Source: "environment"}, sources["PORT"])
+	assert.Equal(t, Setting{Key: "CACHE_SIZE", Value: "20", Source: "flags"}, sources["CACHE_SIZE"])
+	assert.Equal(t, Setting{Key: "GRPC_PORT", Value: "9090", Source: DefaultSource}, sources["GRPC_PORT"])
+}
+
+func shouldRedactSecrets(t *testing.T) {
+	t.Setenv("DATABASE_PASSWORD", "hunter2")
+	t.Setenv("CACHE_REDIS_PASSWORD", "")
+
+	settings, err := Settings(Options{})
+
+	assert.Nil(t, err)
+	for _, setting := range settings {
+		switch setting.Key {
+		case "DATABASE_PASSWORD":
+			assert.Equal(t, redactedValue, setting.Value)
+		case "CACHE_REDIS_PASSWORD":
+			assert.Equal(t, "", setting.Value)
+		}
+	}
+}
+
+func TestLoad_Secrets(t *testing.T) {
+	t.Run("Should read secrets from *_FILE settings", shouldReadSecretsFromFiles)
+	t.Run("Should reject a secret set both inline and from a file", shouldRejectInlineAndFileSecret)
+	t.Run("Should re-read rotated secret files", shouldRereadRotatedSecretFiles)
+}
+
+func shouldReadSecretsFromFiles(t *testing.T) {
+	secretFile := writeConfigFile(t, "db_password", "s3cret\n")
+	file := writeConfigFile(t, "config.yaml", "port: 7000\ndatabase:\n  driver: sqlite\n  max_connections: 1\n  password_file: "+secretFile+"\n")
+
+	cfg, err := Load(Options{File: file})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cret", cfg.Database.Password)
+	settings, err := Settings(Options{File: file})
+	assert.Nil(t, err)
+	for _, setting := range settings {
+		if setting.Key == DatabasePasswordKey {
+			assert.Equal(t, Setting{Key: DatabasePasswordKey, Value: redactedValue, Source: "file " + secretFile}, setting)
+		}
+	}
+}
+
+func shouldRejectInlineAndFileSecret(t *testing.T) {
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_MAX_CONNECTIONS", "1")
+	t.Setenv("DATABASE_PASSWORD", "inline")
+	t.Setenv("DATABASE_PASSWORD_FILE", writeConfigFile(t, "db_password", "from-file"))
+
+	_, err := Load(Options{})
+
+	assert.ErrorContains(t, err, "DATABASE_PASSWORD and DATABASE_PASSWORD_FILE are mutually exclusive")
+}
+
+func shouldRereadRotatedSecretFiles(t *testing.T) {
+	secretFile := writeConfigFile(t, "db_password", "first")
+	t.Setenv("PORT", "8080")
+	t.Setenv("DATABASE_DRIVER", "sqlite")
+	t.Setenv("DATABASE_MAX_CONNECTIONS", "1")
+	t.Setenv("DATABASE_PASSWORD_FILE", secretFile)
+
+	cfg, err := Load(Options{})
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(secretFile, []byte("rotated"), 0o644))
+	password, err := cfg.Secrets.Secret(context.Background(), DatabasePasswordKey)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "first", cfg.Database.Password)
+	assert.Equal(t, "rotated", password)
+}
+
+func TestChainSecretProvider_Secret(t *testing.T) {
+	t.Run("Should fall back to the next provider", shouldFallBackToNextProvider)
+}
+
+func shouldFallBackToNextProvider(t *testing.T) {
+	provider := ChainSecretProvider{
+		NewFileSecretProvider(map[string]string{}),
+		NewEnvSecretProvider(map[string]string{"CACHE_REDIS_PASSWORD": "redis"}),
+	}
+
+	password, err := provider.Secret(context.Background(), "CACHE_REDIS_PASSWORD")
+	assert.Nil(t, err)
+	assert.Equal(t, "redis", password)
+
+	_, err = provider.Secret(context.Background(), DatabasePasswordKey)
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	DatabasePasswordKey = "DATABASE_PASSWORD"
+	secretFileSuffix    = "_FILE"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+type SecretProvider interface {
+	Secret(ctx context.Context, key string) (string, error)
+}
+
+type EnvSecretProvider struct {
+	values map[string]string
+}
+
+func NewEnvSecretProvider(values map[string]string) EnvSecretProvider {
+	return EnvSecretProvider{values: values}
+}
+
+func (p EnvSecretProvider) Secret(ctx context.Context, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	value, ok := os.LookupEnv(key)
+	if p.values != nil {
+		value, ok = p.values[key]
+	}
+	if !ok {
+		return "", fmt.Errorf("%s: %w", key, ErrSecretNotFound)
+	}
+	return value, nil
+}
+
+type FileSecretProvider struct {
+	paths map[string]string
+}
+
+func NewFileSecretProvider(paths map[string]string) FileSecretProvider {
+	return FileSecretProvider{paths: paths}
+}
+
+func (p FileSecretProvider) Secret(ctx context.Context, key string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	path, ok := p.paths[key]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", key, ErrSecretNotFound)
+	}
+	return readSecretFile(path)
+}
+
+type ChainSecretProvider []SecretProvider
+
+func (c ChainSecretProvider) Secret(ctx context.Context, key string) (string, error) {
+	for _, provider := range c {
+		value, err := provider.Secret(ctx, key)
+		if !errors.Is(err, ErrSecretNotFound) {
+			return value, err
+		}
+	}
+	return "", fmt.Errorf("%s: %w", key, ErrSecretNotFound)
+}
+
+func readSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+func resolveSecretFiles(values map[string]string) (map[string]string, []error) {
+	paths := map[string]string{}
+	var errs []error
+	for _, params := range fieldParams() {
+		path, ok := values[params.Key+secretFileSuffix]
+		if !ok || !isSecret(params.Key) {
+			continue
+		}
+		if _, ok := values[params.Key]; ok {
+			errs = append(errs, fmt.Errorf("%s and %s are mutually exclusive",
+				params.Key, params.Key+secretFileSuffix))
+			continue
+		}
+		secret, err := readSecretFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", params.Key+secretFileSuffix, err))
+			continue
+		}
+		values[params.Key] = secret
+		paths[params.Key] = path
+	}
+	return paths, errs
+}
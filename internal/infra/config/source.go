@@ -0,0 +1,245 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/caarlos0/env/v10"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ConfigFileKey  = "CONFIG_FILE"
+	DefaultSource  = "default"
+	defaultEnvFile = ".env"
+	redactedValue  = "******"
+)
+
+var secretKeyMarkers = []string{"PASSWORD", "SECRET", "TOKEN"}
+
+type Options struct {
+	File    string
+	EnvFile string
+	Flags   map[string]string
+}
+
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+type layer struct {
+	source string
+	values map[string]string
+	strict bool
+}
+
+type layers []layer
+
+func (l layers) merged() map[string]string {
+	values := map[string]string{}
+	for _, current := range l {
+		for key, value := range current.values {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+func (l layers) lookup(key string) (string, string, bool) {
+	for index := len(l) - 1; index >= 0; index-- {
+		if value, ok := l[index].values[key]; ok {
+			return value, l[index].source, true
+		}
+	}
+	return "", "", false
+}
+
+func (l layers) unknownKeys() []error {
+	known := map[string]bool{}
+	for _, params := range fieldParams() {
+		known[params.Key] = true
+		known[params.Key+secretFileSuffix] = isSecret(params.Key)
+	}
+	var errs []error
+	for _, current := range l {
+		if !current.strict {
+			continue
+		}
+		for key := range current.values {
+			if !known[key] {
+				errs = append(errs, fmt.Errorf("unknown setting %s in %s", key, current.source))
+			}
+		}
+	}
+	return errs
+}
+
+func resolve(opts Options) (layers, error) {
+	var resolved layers
+
+	file := opts.File
+	if file == "" {
+		file = os.Getenv(ConfigFileKey)
+	}
+	if file != "" {
+		values, err := readConfigFile(file)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, layer{source: file, values: values, strict: true})
+	}
+
+	envFile := opts.EnvFile
+	if envFile == "" {
+		envFile = defaultEnvFile
+	}
+	values, err := godotenv.Read(envFile)
+	if err != nil && (opts.EnvFile != "" || !errors.Is(err, fs.ErrNotExist)) {
+		return nil, fmt.Errorf("unable to load %s: %w", envFile, err)
+	}
+	if err == nil {
+		resolved = append(resolved, layer{source: envFile, values: values})
+	}
+
+	environment := map[string]string{}
+	for _, entry := range os.Environ() {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			environment[key] = value
+		}
+	}
+	resolved = append(resolved, layer{source: "environment", values: environment})
+	return append(resolved, layer{source: "flags", values: opts.Flags}), nil
+}
+
+func readConfigFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file: %w", err)
+	}
+
+	raw := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &raw)
+	case ".toml":
+		err = toml.Unmarshal(content, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file %q, expected .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config file %q: %w", path, err)
+	}
+
+	values := map[string]string{}
+	flatten("", raw, values)
+	return values, nil
+}
+
+func flatten(prefix string, raw map[string]any, values map[string]string) {
+	for key, value := range raw {
+		name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		switch typed := value.(type) {
+		case map[string]any:
+			flatten(name, typed, values)
+		case []any:
+			items := make([]string, 0, len(typed))
+			for _, item := range typed {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[name] = strings.Join(items, ",")
+		case nil:
+			values[name] = ""
+		default:
+			values[name] = fmt.Sprint(typed)
+		}
+	}
+}
+
+func Settings(opts Options) ([]Setting, error) {
+	values, err := resolve(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings []Setting
+	for _, params := range fieldParams() {
+		setting := Setting{Key: params.Key}
+		if path, _, ok := values.lookup(params.Key + secretFileSuffix); ok && isSecret(params.Key) {
+			setting.Value, setting.Source = redactedValue, "file "+path
+		} else if value, source, ok := values.lookup(params.Key); ok {
+			setting.Value, setting.Source = value, source
+		} else if params.HasDefaultValue {
+			setting.Value, setting.Source = params.DefaultValue, DefaultSource
+		}
+		if setting.Value != "" && isSecret(params.Key) {
+			setting.Value = redactedValue
+		}
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+func isSecret(key string) bool {
+	for _, marker := range secretKeyMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldParams() []env.FieldParams {
+	params, err := env.GetFieldParams(&Config{})
+	if err != nil {
+		panic(err.Error())
+	}
+	return params
+}
+
+type Flags struct {
+	flags   *flag.FlagSet
+	file    *string
+	envFile *string
+	keys    map[string]string
+}
+
+func RegisterFlags(flags *flag.FlagSet) *Flags {
+	registered := &Flags{
+		flags:   flags,
+		file:    flags.String("config", "", "YAML or TOML config file, also read from "+ConfigFileKey),
+		envFile: flags.String("env-file", "", "dotenv file, defaults to .env in the working directory when present"),
+		keys:    map[string]string{},
+	}
+	for _, params := range fieldParams() {
+		name := strings.ToLower(strings.ReplaceAll(params.Key, "_", "-"))
+		registered.keys[name] = params.Key
+		flags.String(name, "", "overrides "+params.Key)
+		if isSecret(params.Key) {
+			registered.keys[name+"-file"] = params.Key + secretFileSuffix
+			flags.String(name+"-file", "", "reads "+params.Key+" from this file")
+		}
+	}
+	return registered
+}
+
+func (f *Flags) Options() Options {
+	opts := Options{File: *f.file, EnvFile: *f.envFile, Flags: map[string]string{}}
+	f.flags.Visit(func(set *flag.Flag) {
+		if key, ok := f.keys[set.Name]; ok {
+			opts.Flags[key] = set.Value.String()
+		}
+	})
+	return opts
+}
@@ -1,52 +1,303 @@
 package database
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
 	"sync"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
 )
 
+const (
+	MySQLDriver    = "mysql"
+	PostgresDriver = "postgres"
+	SQLiteDriver   = "sqlite"
+)
+
+const (
+	TLSDisabled        = "false"
+	TLSRequired        = "true"
+	TLSSkipVerify      = "skip-verify"
+	TLSPreferred       = "preferred"
+	mysqlTLSConfigName = "eulabs"
+)
+
+// Sessions are pinned to UTC so CURRENT_TIMESTAMP and the times bound by the drivers agree.
+// MySQL takes an offset because named zones depend on the server time zone tables.
+const (
+	mysqlTimeZone    = "'+00:00'"
+	postgresTimeZone = "UTC"
+)
+
+var tlsModes = map[string]bool{
+	"":            true,
+	TLSDisabled:   true,
+	TLSRequired:   true,
+	TLSSkipVerify: true,
+	TLSPreferred:  true,
+}
+
+var sqlDrivers = map[string]string{
+	MySQLDriver:    "mysql",
+	PostgresDriver: "pgx",
+	SQLiteDriver:   "sqlite",
+}
+
+var sqlDriverInstances = map[string]driver.Driver{
+	MySQLDriver:    &mysql.MySQLDriver{},
+	PostgresDriver: stdlib.GetDefaultDriver(),
+}
+
 var instance *sql.DB
 var once sync.Once
 
+var replicaInstances []*sql.DB
+var replicasOnce sync.Once
+
+type PasswordFunc func(ctx context.Context) (string, error)
+
+type endpoint struct {
+	host string
+	port int
+}
+
 type DBPool struct {
+	cfg                DBConfig
+	tlsConfig          string
+	driver             string
 	dsn                string
+	replicas           []endpoint
+	replicaDSNs        []string
 	maxConnections     int
 	maxIdleConnections int
 }
 type DBConfig struct {
+	Driver             string
 	Host               string
 	User               string
 	Password           string
+	PasswordFunc       PasswordFunc
 	DBName             string
+	Path               string
 	Port               int
 	MaxConnections     int
 	MaxIdleConnections int
+	ConnMaxLifetime    time.Duration
+	ConnMaxIdleTime    time.Duration
+	ReplicaHosts       []string
+	TLSMode            string
+	TLSCAFile          string
+	TLSCertFile        string
+	TLSKeyFile         string
+	TLSServerName      string
+	DialTimeout        time.Duration
+	ReadTimeout        time.Duration
+	WriteTimeout       time.Duration
+	ParseTime          bool
 }
 
-func NewDBPool(cfg DBConfig) DBPool {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", cfg.User, cfg.Password,
-		cfg.Host, cfg.Port, cfg.DBName)
+func NewDBPool(cfg DBConfig) (DBPool, error) {
+	if cfg.Driver == "" {
+		cfg.Driver = MySQLDriver
+	}
+	var tlsConfig string
+	if cfg.Driver == MySQLDriver {
+		var err error
+		if tlsConfig, err = registerMySQLTLS(cfg); err != nil {
+			return DBPool{}, err
+		}
+	}
+	dsn := buildDSN(cfg, tlsConfig, cfg.Host, cfg.Port)
 
-	return DBPool{dsn: dsn, maxConnections: cfg.MaxConnections,
-		maxIdleConnections: cfg.MaxIdleConnections}
+	var replicas []endpoint
+	var replicaDSNs []string
+	for _, replicaHost := range cfg.ReplicaHosts {
+		if replicaHost == "" || cfg.Driver == SQLiteDriver {
+			continue
+		}
+		host, port := replicaHost, cfg.Port
+		if splitHost, splitPort, err := net.SplitHostPort(replicaHost); err == nil {
+			if parsedPort, err := strconv.Atoi(splitPort); err == nil {
+				host, port = splitHost, parsedPort
+			}
+		}
+		replicas = append(replicas, endpoint{host: host, port: port})
+		replicaDSNs = append(replicaDSNs, buildDSN(cfg, tlsConfig, host, port))
+	}
+
+	return DBPool{cfg: cfg, tlsConfig: tlsConfig, driver: cfg.Driver, dsn: dsn, replicas: replicas,
+		replicaDSNs: replicaDSNs, maxConnections: cfg.MaxConnections,
+		maxIdleConnections: cfg.MaxIdleConnections}, nil
 }
 
 func (p *DBPool) GetDB() *sql.DB {
 	once.Do(func() {
-		db, err := sql.Open("mysql", p.dsn)
-		if err != nil {
-			panic(err.Error())
-		}
-		db.SetMaxIdleConns(p.maxIdleConnections)
-		db.SetMaxOpenConns(p.maxConnections)
-		instance = db
+		instance = p.open(p.dsn, endpoint{host: p.cfg.Host, port: p.cfg.Port})
 	})
 	return instance
 }
 
+func (p *DBPool) GetReplicaDBs() []*sql.DB {
+	replicasOnce.Do(func() {
+		for index, dsn := range p.replicaDSNs {
+			replicaInstances = append(replicaInstances, p.open(dsn, p.replicas[index]))
+		}
+	})
+	return replicaInstances
+}
+
 func (p *DBPool) GetDSN() string {
 	return p.dsn
 }
+
+func (p *DBPool) GetDriver() string {
+	return p.driver
+}
+
+func (p *DBPool) open(dsn string, target endpoint) *sql.DB {
+	var db *sql.DB
+	if sqlDriver, ok := sqlDriverInstances[p.driver]; ok && p.cfg.PasswordFunc != nil {
+		db = sql.OpenDB(newRotatingConnector(sqlDriver, func(ctx context.Context) (string, error) {
+			password, err := p.cfg.PasswordFunc(ctx)
+			if err != nil {
+				return "", err
+			}
+			cfg := p.cfg
+			cfg.Password = password
+			return buildDSN(cfg, p.tlsConfig, target.host, target.port), nil
+		}))
+	} else {
+		var err error
+		if db, err = sql.Open(SQLDriverName(p.driver), dsn); err != nil {
+			panic(err.Error())
+		}
+	}
+	if p.driver == SQLiteDriver {
+		db.SetMaxIdleConns(1)
+		db.SetMaxOpenConns(1)
+		return db
+	}
+	db.SetMaxIdleConns(p.maxIdleConnections)
+	db.SetMaxOpenConns(p.maxConnections)
+	db.SetConnMaxLifetime(p.cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(p.cfg.ConnMaxIdleTime)
+	return db
+}
+
+func SQLDriverName(driver string) string {
+	if name, ok := sqlDrivers[driver]; ok {
+		return name
+	}
+	return driver
+}
+
+func buildDSN(cfg DBConfig, tlsConfig, host string, port int) string {
+	if cfg.Driver == SQLiteDriver {
+		return "file:" + cfg.Path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+	}
+	if cfg.Driver == PostgresDriver {
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.User, cfg.Password),
+			Host:     net.JoinHostPort(host, strconv.Itoa(port)),
+			Path:     cfg.DBName,
+			RawQuery: "timezone=" + postgresTimeZone,
+		}
+		return dsn.String()
+	}
+	mysqlCfg := mysql.NewConfig()
+	mysqlCfg.User = cfg.User
+	mysqlCfg.Passwd = cfg.Password
+	mysqlCfg.Net = "tcp"
+	mysqlCfg.Addr = net.JoinHostPort(host, strconv.Itoa(port))
+	mysqlCfg.DBName = cfg.DBName
+	mysqlCfg.Timeout = cfg.DialTimeout
+	mysqlCfg.ReadTimeout = cfg.ReadTimeout
+	mysqlCfg.WriteTimeout = cfg.WriteTimeout
+	mysqlCfg.ParseTime = cfg.ParseTime
+	mysqlCfg.Loc = time.UTC
+	mysqlCfg.Params = map[string]string{"time_zone": mysqlTimeZone}
+	mysqlCfg.TLSConfig = tlsConfig
+	mysqlCfg.AllowFallbackToPlaintext = cfg.TLSMode == TLSPreferred && tlsConfig == mysqlTLSConfigName
+	return mysqlCfg.FormatDSN()
+}
+
+func registerMySQLTLS(cfg DBConfig) (string, error) {
+	if !tlsModes[cfg.TLSMode] {
+		return "", fmt.Errorf("unsupported TLS mode %q", cfg.TLSMode)
+	}
+	if cfg.TLSCAFile == "" && cfg.TLSCertFile == "" && cfg.TLSKeyFile == "" && cfg.TLSServerName == "" {
+		return cfg.TLSMode, nil
+	}
+	if cfg.TLSMode == TLSDisabled {
+		return "", errors.New("TLS certificates are configured but TLS is disabled")
+	}
+
+	tlsCfg := &tls.Config{
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSMode == TLSSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if cfg.TLSCAFile != "" {
+		caPEM, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to read TLS CA: %w", err)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caPEM) {
+			return "", fmt.Errorf("no certificates found in TLS CA %s", cfg.TLSCAFile)
+		}
+		tlsCfg.RootCAs = rootCAs
+	}
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return "", fmt.Errorf("unable to load TLS client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{certificate}
+	}
+	if err := mysql.RegisterTLSConfig(mysqlTLSConfigName, tlsCfg); err != nil {
+		return "", err
+	}
+	return mysqlTLSConfigName, nil
+}
+
+type rotatingConnector struct {
+	driver driver.Driver
+	dsn    func(ctx context.Context) (string, error)
+}
+
+func newRotatingConnector(sqlDriver driver.Driver, dsn func(ctx context.Context) (string, error)) rotatingConnector {
+	return rotatingConnector{driver: sqlDriver, dsn: dsn}
+}
+
+func (c rotatingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dsn, err := c.dsn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve database credentials: %w", err)
+	}
+	if driverContext, ok := c.driver.(driver.DriverContext); ok {
+		connector, err := driverContext.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return connector.Connect(ctx)
+	}
+	return c.driver.Open(dsn)
+}
+
+func (c rotatingConnector) Driver() driver.Driver {
+	return c.driver
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+type replica struct {
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+type Router struct {
+	primary        *sql.DB
+	replicas       []*replica
+	next           atomic.Uint64
+	readYourWrites time.Duration
+}
+
+type RouterOption func(*Router)
+
+func WithReadYourWrites(window time.Duration) RouterOption {
+	return func(r *Router) {
+		r.readYourWrites = window
+	}
+}
+
+func NewRouter(primary *sql.DB, replicas []*sql.DB, opts ...RouterOption) *Router {
+	router := &Router{primary: primary}
+	for _, db := range replicas {
+		replica := &replica{db: db}
+		replica.healthy.Store(true)
+		router.replicas = append(router.replicas, replica)
+	}
+	for _, opt := range opts {
+		opt(router)
+	}
+	return router
+}
+
+func (r *Router) Primary() *sql.DB {
+	return r.primary
+}
+
+func (r *Router) Reader(ctx context.Context) *sql.DB {
+	if len(r.replicas) == 0 || usecase.PrimaryReadFromContext(ctx) || r.pinned(ctx) {
+		return r.primary
+	}
+	start := r.next.Add(1)
+	for offset := range r.replicas {
+		replica := r.replicas[(start+uint64(offset))%uint64(len(r.replicas))]
+		if replica.healthy.Load() {
+			return replica.db
+		}
+	}
+	return r.primary
+}
+
+func (r *Router) MarkWritten(ctx context.Context) {
+	if r.readYourWrites <= 0 || len(r.replicas) == 0 {
+		return
+	}
+	if session, ok := usecase.ReadSessionFromContext(ctx); ok {
+		session.PinPrimary(time.Now().Add(r.readYourWrites))
+	}
+}
+
+func (r *Router) CheckHealth(ctx context.Context, interval time.Duration) {
+	if len(r.replicas) == 0 || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.checkReplicas(ctx, interval)
+		}
+	}
+}
+
+func (r *Router) checkReplicas(ctx context.Context, timeout time.Duration) {
+	for index, replica := range r.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := replica.db.PingContext(pingCtx)
+		cancel()
+		healthy := err == nil
+		if replica.healthy.Swap(healthy) != healthy {
+			slog.Warn("database replica health changed", slog.Int("replica", index),
+				slog.Bool("healthy", healthy), slog.Any("msg", err))
+		}
+	}
+}
+
+// pinned ignores pins further away than the window, since clients could send any value back.
+func (r *Router) pinned(ctx context.Context) bool {
+	session, ok := usecase.ReadSessionFromContext(ctx)
+	if r.readYourWrites <= 0 || !ok {
+		return false
+	}
+	now, until := time.Now(), session.PrimaryUntil()
+	return now.Before(until) && !until.After(now.Add(r.readYourWrites))
+}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	downDSNs     = map[string]bool{}
+	downDSNsLock sync.Mutex
+)
+
+type routerTestDriver struct{}
+
+type routerTestConn struct {
+	dsn string
+}
+
+func (routerTestDriver) Open(dsn string) (driver.Conn, error) {
+	return routerTestConn{dsn: dsn}, nil
+}
+
+func (c routerTestConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c routerTestConn) Close() error {
+	return nil
+}
+
+func (c routerTestConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c routerTestConn) Ping(ctx context.Context) error {
+	downDSNsLock.Lock()
+	defer downDSNsLock.Unlock()
+	if downDSNs[c.dsn] {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+func init() {
+	sql.Register("routertest", routerTestDriver{})
+}
+
+func openTestDB(t *testing.T, dsn string) *sql.DB {
+	db, err := sql.Open("routertest", dsn)
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestRouter_Reader(t *testing.T) {
+	t.Run("Should read from the primary without replicas", routerPrimaryOnly)
+	t.Run("Should round robin between healthy replicas", routerRoundRobin)
+	t.Run("Should skip unhealthy replicas", routerSkipUnhealthy)
+	t.Run("Should pin a session to the primary after it writes", routerReadYourWrites)
+	t.Run("Should ignore pins beyond the read your writes window", routerIgnoreForgedPin)
+	t.Run("Should read from the primary when asked to", routerPrimaryRead)
+}
+
+func routerPrimaryOnly(t *testing.T) {
+	primary := openTestDB(t, "primary")
+	router := NewRouter(primary, nil)
+	assert.Same(t, primary, router.Reader(context.TODO()))
+}
+
+func routerRoundRobin(t *testing.T) {
+	primary, replicaA, replicaB := openTestDB(t, "primary"), openTestDB(t, "a"), openTestDB(t, "b")
+	router := NewRouter(primary, []*sql.DB{replicaA, replicaB})
+
+	first := router.Reader(context.TODO())
+	second := router.Reader(context.TODO())
+	assert.NotSame(t, first, second)
+	assert.Same(t, first, router.Reader(context.TODO()))
+	assert.Same(t, primary, router.Primary())
+}
+
+func routerSkipUnhealthy(t *testing.T) {
+	primary, replicaA, replicaB := openTestDB(t, "primary"), openTestDB(t, "down-a"), openTestDB(t, "b")
+	router := NewRouter(primary, []*sql.DB{replicaA, replicaB})
+	downDSNsLock.Lock()
+	downDSNs["down-a"] = true
+	downDSNsLock.Unlock()
+
+	router.checkReplicas(context.TODO(), time.Second)
+	for index := 0; index < 3; index++ {
+		assert.Same(t, replicaB, router.Reader(context.TODO()))
+	}
+
+	downDSNsLock.Lock()
+	downDSNs["b"] = true
+	downDSNsLock.Unlock()
+	router.checkReplicas(context.TODO(), time.Second)
+	assert.Same(t, primary, router.Reader(context.TODO()))
+
+	downDSNsLock.Lock()
+	delete(downDSNs, "b")
+	downDSNsLock.Unlock()
+	router.checkReplicas(context.TODO(), time.Second)
+	assert.Same(t, replicaB, router.Reader(context.TODO()))
+}
+
+func routerReadYourWrites(t *testing.T) {
+	primary, replica := openTestDB(t, "primary"), openTestDB(t, "replica")
+	router := NewRouter(primary, []*sql.DB{replica}, WithReadYourWrites(50*time.Millisecond))
+	writerSession := usecase.NewReadSession(time.Time{})
+	writer := usecase.WithReadSession(context.TODO(), writerSession)
+	reader := usecase.WithReadSession(context.TODO(), usecase.NewReadSession(time.Time{}))
+
+	router.MarkWritten(writer)
+	assert.Same(t, primary, router.Reader(writer))
+	assert.Same(t, replica, router.Reader(reader))
+
+	resumed := usecase.WithReadSession(context.TODO(), usecase.NewReadSession(writerSession.PrimaryUntil()))
+	assert.Same(t, primary, router.Reader(resumed))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Same(t, replica, router.Reader(writer))
+}
+
+func routerIgnoreForgedPin(t *testing.T) {
+	primary, replica := openTestDB(t, "primary"), openTestDB(t, "replica")
+	router := NewRouter(primary, []*sql.DB{replica}, WithReadYourWrites(50*time.Millisecond))
+	forged := usecase.WithReadSession(context.TODO(), usecase.NewReadSession(time.Now().Add(time.Hour)))
+
+	assert.Same(t, replica, router.Reader(forged))
+}
+
+func routerPrimaryRead(t *testing.T) {
+	primary, replica := openTestDB(t, "primary"), openTestDB(t, "replica")
+	router := NewRouter(primary, []*sql.DB{replica})
+
+	assert.Same(t, primary, router.Reader(usecase.WithPrimaryRead(context.TODO())))
+	assert.Same(t, replica, router.Reader(context.TODO()))
+}
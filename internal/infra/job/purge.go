@@ -0,0 +1,49 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+type PurgeJob struct {
+	productPurge *usecase.ProductPurge
+	interval     time.Duration
+}
+
+func NewPurgeJob(productRepo repository.ProductRepository, retention, interval time.Duration) *PurgeJob {
+	return &PurgeJob{
+		productPurge: usecase.NewProductPurge(productRepo, retention),
+		interval:     interval,
+	}
+}
+
+func (j *PurgeJob) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		slog.Info("purge job disabled")
+		return
+	}
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		j.purge(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PurgeJob) purge(ctx context.Context) {
+	purged, err := j.productPurge.Execute(ctx)
+	if err != nil {
+		return
+	}
+	if purged > 0 {
+		slog.Info("deleted products purged", slog.Int64("count", purged))
+	}
+}
@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	LogPublisher  = "log"
+	FilePublisher = "file"
+	HTTPPublisher = "http"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event repository.OutboxEventData) error
+}
+
+func NewPublisher(kind, filePath, url string, client *http.Client) (EventPublisher, error) {
+	switch kind {
+	case LogPublisher:
+		return NewLogPublisher(), nil
+	case FilePublisher:
+		return NewFilePublisher(filePath)
+	case HTTPPublisher:
+		return NewHTTPPublisher(url, client)
+	}
+	return nil, fmt.Errorf("unknown outbox publisher %q", kind)
+}
+
+type FanoutEventPublisher struct {
+	publishers []EventPublisher
+}
+
+// NewFanoutPublisher hands each event to every publisher in order. The relay retries the whole
+// event when one of them fails, so the publishers must tolerate receiving an event again.
+func NewFanoutPublisher(publishers ...EventPublisher) EventPublisher {
+	return FanoutEventPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p FanoutEventPublisher) Publish(ctx context.Context, event repository.OutboxEventData) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type LogEventPublisher struct{}
+
+func NewLogPublisher() EventPublisher {
+	return LogEventPublisher{}
+}
+
+func (p LogEventPublisher) Publish(ctx context.Context, event repository.OutboxEventData) error {
+	slog.Info("product event published",
+		slog.String("id", event.EventID),
+		slog.String("type", event.EventType),
+		slog.String("code", event.AggregateCode),
+		slog.String("payload", string(event.Payload)))
+	return nil
+}
@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type FileEventPublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFilePublisher(path string) (*FileEventPublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileEventPublisher{
+		file: file,
+	}, nil
+}
+
+func (p *FileEventPublisher) Publish(ctx context.Context, event repository.OutboxEventData) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.file.Write(append(slices.Clip(event.Payload), '\n')); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+func (p *FileEventPublisher) Close() error {
+	return p.file.Close()
+}
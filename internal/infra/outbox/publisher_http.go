@@ -0,0 +1,57 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	EventIDHeader   = "X-Event-ID"
+	EventTypeHeader = "X-Event-Type"
+
+	maxErrorBodySize = 512
+)
+
+type HTTPEventPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPPublisher(endpoint string, client *http.Client) (*HTTPEventPublisher, error) {
+	parsedURL, err := url.Parse(endpoint)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return nil, fmt.Errorf("invalid outbox publisher url %q", endpoint)
+	}
+	return &HTTPEventPublisher{
+		url:    endpoint,
+		client: client,
+	}, nil
+}
+
+func (p *HTTPEventPublisher) Publish(ctx context.Context, event repository.OutboxEventData) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(event.Payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, event.EventID)
+	request.Header.Set(EventTypeHeader, event.EventType)
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+		return fmt.Errorf("unexpected status %d: %s", response.StatusCode, body)
+	}
+	return nil
+}
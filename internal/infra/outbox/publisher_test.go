@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPublisher(t *testing.T) {
+	t.Run("Should results error if publisher is unknown", newPublisherUnknownErr)
+	t.Run("Should results error if http publisher url is invalid", newPublisherInvalidURLErr)
+}
+
+func newPublisherUnknownErr(t *testing.T) {
+	_, err := NewPublisher("kafka", "", "", http.DefaultClient)
+	assert.EqualError(t, err, `unknown outbox publisher "kafka"`)
+}
+
+func newPublisherInvalidURLErr(t *testing.T) {
+	_, err := NewPublisher(HTTPPublisher, "", "", http.DefaultClient)
+	assert.Error(t, err)
+}
+
+func TestFanoutEventPublisher_Publish(t *testing.T) {
+	t.Run("Should hand the event to every publisher", fanoutPublisherSuccess)
+	t.Run("Should results error if a publisher fails", fanoutPublisherErr)
+}
+
+func fanoutPublisherSuccess(t *testing.T) {
+	first, second := &publisherRecorder{}, &publisherRecorder{}
+	publisher := NewFanoutPublisher(first, second)
+
+	assert.NoError(t, publisher.Publish(context.TODO(), outboxEvent("e1", "XSZ-000741")))
+	assert.Equal(t, []string{"e1"}, first.published)
+	assert.Equal(t, []string{"e1"}, second.published)
+}
+
+func fanoutPublisherErr(t *testing.T) {
+	second := &publisherRecorder{}
+	publisher := NewFanoutPublisher(&publisherRecorder{failingCodes: map[string]bool{"XSZ-000741": true}},
+		second)
+
+	assert.EqualError(t, publisher.Publish(context.TODO(), outboxEvent("e1", "XSZ-000741")),
+		"broker unavailable")
+	assert.Empty(t, second.published)
+}
+
+func TestFileEventPublisher_Publish(t *testing.T) {
+	t.Run("Should append one event per line", filePublisherAppend)
+}
+
+func filePublisherAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	publisher, err := NewFilePublisher(path)
+	assert.NoError(t, err)
+	defer publisher.Close()
+
+	assert.NoError(t, publisher.Publish(context.TODO(), outboxEvent("e1", "XXCC")))
+	assert.NoError(t, publisher.Publish(context.TODO(), outboxEvent("e2", "XXCC")))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"id\":\"e1\"}\n{\"id\":\"e2\"}\n", string(content))
+}
+
+func TestHTTPEventPublisher_Publish(t *testing.T) {
+	t.Run("Should post the event payload", httpPublisherSuccess)
+	t.Run("Should results error if endpoint does not accept the event", httpPublisherStatusErr)
+}
+
+func httpPublisherSuccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"id":"e1"}`, string(body))
+		assert.Equal(t, "e1", r.Header.Get(EventIDHeader))
+		assert.Equal(t, entity.ProductUpdatedEvent, r.Header.Get(EventTypeHeader))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	publisher, err := NewHTTPPublisher(server.URL, http.DefaultClient)
+	assert.NoError(t, err)
+	assert.NoError(t, publisher.Publish(context.TODO(), outboxEvent("e1", "XXCC")))
+}
+
+func httpPublisherStatusErr(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	publisher, err := NewHTTPPublisher(server.URL, http.DefaultClient)
+	assert.NoError(t, err)
+	assert.EqualError(t, publisher.Publish(context.TODO(), outboxEvent("e1", "XXCC")), "unexpected status 502: ")
+}
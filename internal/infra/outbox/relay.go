@@ -0,0 +1,147 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	claimLease        = time.Minute
+	defaultMaxBackoff = 5 * time.Minute
+)
+
+type Relay struct {
+	outboxRepo  repository.OutboxRepository
+	publisher   EventPublisher
+	batchSize   int
+	interval    time.Duration
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+type RelayOption func(*Relay)
+
+func WithMaxBackoff(maxBackoff time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.maxBackoff = maxBackoff
+	}
+}
+
+func NewRelay(outboxRepo repository.OutboxRepository, publisher EventPublisher,
+	batchSize int, interval time.Duration, maxAttempts int, backoff time.Duration, opts ...RelayOption) *Relay {
+	r := &Relay{
+		outboxRepo:  outboxRepo,
+		publisher:   publisher,
+		batchSize:   batchSize,
+		interval:    interval,
+		maxAttempts: maxAttempts,
+		backoff:     backoff,
+		maxBackoff:  defaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.maxBackoff = max(r.maxBackoff, r.backoff)
+	return r
+}
+
+func (r *Relay) Run(ctx context.Context) {
+	if r.interval <= 0 || r.batchSize <= 0 {
+		slog.Info("outbox relay disabled")
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if r.relay(ctx) == r.batchSize {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay publishes a batch of claimed events. Claims are leases, so publishing stops once the
+// lease expires and another relay may already have taken the remaining events over.
+func (r *Relay) relay(ctx context.Context) int {
+	now := time.Now()
+	leaseUntil := now.Add(claimLease)
+	events, err := r.outboxRepo.ClaimPending(ctx, now, leaseUntil, r.batchSize)
+	if err != nil {
+		return 0
+	}
+	leaseCtx, cancel := context.WithDeadline(ctx, leaseUntil)
+	defer cancel()
+
+	delivered := 0
+	blockedCodes := map[string]time.Time{}
+	for _, event := range events {
+		code := strings.ToLower(event.AggregateCode)
+		if retryAt, blocked := blockedCodes[code]; blocked {
+			if err := r.outboxRepo.Reschedule(ctx, event.ID, retryAt); err != nil {
+				slog.Error("impossible to reschedule outbox event", slog.Any("msg", err),
+					slog.String("id", event.EventID), slog.String("code", event.AggregateCode))
+			}
+			continue
+		}
+		if leaseCtx.Err() != nil {
+			break
+		}
+		if err := r.publisher.Publish(leaseCtx, event); err != nil {
+			slog.Error("impossible to publish outbox event", slog.Any("msg", err),
+				slog.String("id", event.EventID), slog.String("code", event.AggregateCode))
+			blockedCodes[code] = r.fail(ctx, event, err)
+			continue
+		}
+		if err := r.outboxRepo.MarkDelivered(ctx, event.ID); err != nil {
+			slog.Error("impossible to mark outbox event delivered", slog.Any("msg", err),
+				slog.String("id", event.EventID), slog.String("code", event.AggregateCode))
+			blockedCodes[code] = leaseUntil
+			continue
+		}
+		delivered++
+	}
+	return delivered
+}
+
+// fail records a failed publish and returns when the product may be retried. If the failure
+// cannot be recorded the event keeps its claim, so it is retried once the lease expires.
+func (r *Relay) fail(ctx context.Context, event repository.OutboxEventData, err error) time.Time {
+	if event.Attempts+1 >= r.maxAttempts {
+		slog.Error("outbox event moved to dead letters", slog.String("id", event.EventID),
+			slog.String("code", event.AggregateCode), slog.Int("attempts", event.Attempts+1))
+		if markErr := r.outboxRepo.MarkDead(ctx, event.ID, err.Error()); markErr != nil {
+			slog.Error("impossible to move outbox event to dead letters", slog.Any("msg", markErr),
+				slog.String("id", event.EventID), slog.String("code", event.AggregateCode))
+			return event.NextAttemptAt
+		}
+		return time.Now()
+	}
+	retryAt := time.Now().Add(r.retryDelay(event.Attempts))
+	if markErr := r.outboxRepo.MarkFailed(ctx, event.ID, err.Error(), retryAt); markErr != nil {
+		slog.Error("impossible to record outbox event failure", slog.Any("msg", markErr),
+			slog.String("id", event.EventID), slog.String("code", event.AggregateCode))
+		return event.NextAttemptAt
+	}
+	return retryAt
+}
+
+// retryDelay doubles the backoff per attempt up to maxBackoff without overflowing.
+func (r *Relay) retryDelay(attempts int) time.Duration {
+	delay := r.backoff
+	for attempt := 0; attempt < attempts; attempt++ {
+		if delay > r.maxBackoff/2 {
+			return r.maxBackoff
+		}
+		delay *= 2
+	}
+	return delay
+}
@@ -0,0 +1,169 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	infrarepository "github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type publisherRecorder struct {
+	failingCodes map[string]bool
+	published    []string
+}
+
+func (p *publisherRecorder) Publish(ctx context.Context, event repository.OutboxEventData) error {
+	if p.failingCodes[event.AggregateCode] {
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, event.EventID)
+	return nil
+}
+
+func newTestOutbox(t *testing.T, events ...repository.OutboxEventData) repository.OutboxRepository {
+	outboxRepo := infrarepository.NewOutboxRepositoryInMemory()
+	for _, event := range events {
+		_, err := outboxRepo.Insert(context.TODO(), event)
+		assert.NoError(t, err)
+	}
+	return outboxRepo
+}
+
+func outboxEvent(eventID, code string) repository.OutboxEventData {
+	return repository.OutboxEventData{
+		EventID:       eventID,
+		EventType:     entity.ProductUpdatedEvent,
+		AggregateCode: code,
+		Payload:       []byte(`{"id":"` + eventID + `"}`),
+	}
+}
+
+func TestRelay_relay(t *testing.T) {
+	t.Run("Should publish pending events in order and mark them delivered", relayDeliverInOrder)
+	t.Run("Should hold the following events of a product when one fails", relayHoldFailedProduct)
+	t.Run("Should not let a failing product delay the others", relaySkipBlockedProduct)
+	t.Run("Should move events to dead letters after max attempts", relayDeadLetter)
+	t.Run("Should skip products leased by another relay", relaySkipLeasedProduct)
+	t.Run("Should cap the retry delay at the max backoff", relayCapBackoff)
+	t.Run("Should keep the claim if the failure cannot be recorded", relayFailureNotRecorded)
+}
+
+type failingOutboxRepository struct {
+	repository.OutboxRepository
+}
+
+func (r failingOutboxRepository) MarkFailed(ctx context.Context, id int64, lastError string,
+	nextAttemptAt time.Time) error {
+	return errors.New("database unavailable")
+}
+
+func relayCapBackoff(t *testing.T) {
+	relay := NewRelay(newTestOutbox(t), &publisherRecorder{}, 10, 0, 100, time.Second,
+		WithMaxBackoff(time.Minute))
+
+	assert.Equal(t, time.Second, relay.retryDelay(0))
+	assert.Equal(t, 8*time.Second, relay.retryDelay(3))
+	assert.Equal(t, time.Minute, relay.retryDelay(6))
+	assert.Equal(t, time.Minute, relay.retryDelay(90))
+}
+
+func relayFailureNotRecorded(t *testing.T) {
+	event := outboxEvent("e1", "XSZ-000741")
+	event.NextAttemptAt = time.Now().Add(claimLease)
+	relay := NewRelay(failingOutboxRepository{OutboxRepository: newTestOutbox(t)},
+		&publisherRecorder{}, 10, 0, 3, time.Second)
+
+	retryAt := relay.fail(context.TODO(), event, errors.New("broker unavailable"))
+
+	assert.Equal(t, event.NextAttemptAt, retryAt)
+}
+
+func relayDeliverInOrder(t *testing.T) {
+	outboxRepo := newTestOutbox(t,
+		outboxEvent("e1", "XSZ-000741"),
+		outboxEvent("e2", "XXCC"),
+		outboxEvent("e3", "XSZ-000741"))
+	publisher := &publisherRecorder{}
+	relay := NewRelay(outboxRepo, publisher, 10, 0, 3, 0)
+
+	assert.Equal(t, 3, relay.relay(context.TODO()))
+	assert.Equal(t, []string{"e1", "e2", "e3"}, publisher.published)
+
+	pending, err := outboxRepo.ListPending(context.TODO(), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func relayHoldFailedProduct(t *testing.T) {
+	outboxRepo := newTestOutbox(t,
+		outboxEvent("e1", "XSZ-000741"),
+		outboxEvent("e2", "XXCC"),
+		outboxEvent("e3", "xsz-000741"))
+	publisher := &publisherRecorder{failingCodes: map[string]bool{"XSZ-000741": true}}
+	relay := NewRelay(outboxRepo, publisher, 10, 0, 3, 0)
+
+	assert.Equal(t, 1, relay.relay(context.TODO()))
+	assert.Equal(t, []string{"e2"}, publisher.published)
+
+	pending, err := outboxRepo.ListPending(context.TODO(), 10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "broker unavailable", pending[0].LastError)
+	assert.Equal(t, 0, pending[1].Attempts)
+
+	publisher.failingCodes = nil
+	assert.Equal(t, 2, relay.relay(context.TODO()))
+	assert.Equal(t, []string{"e2", "e1", "e3"}, publisher.published)
+}
+
+func relaySkipBlockedProduct(t *testing.T) {
+	outboxRepo := newTestOutbox(t,
+		outboxEvent("e1", "XSZ-000741"),
+		outboxEvent("e2", "XSZ-000741"),
+		outboxEvent("e3", "XXCC"))
+	publisher := &publisherRecorder{failingCodes: map[string]bool{"XSZ-000741": true}}
+	relay := NewRelay(outboxRepo, publisher, 2, 0, 3, time.Hour)
+
+	assert.Equal(t, 0, relay.relay(context.TODO()))
+	assert.Equal(t, 1, relay.relay(context.TODO()))
+	assert.Equal(t, []string{"e3"}, publisher.published)
+}
+
+func relayDeadLetter(t *testing.T) {
+	outboxRepo := newTestOutbox(t,
+		outboxEvent("e1", "XSZ-000741"),
+		outboxEvent("e2", "XSZ-000741"))
+	publisher := &publisherRecorder{failingCodes: map[string]bool{"XSZ-000741": true}}
+	relay := NewRelay(outboxRepo, publisher, 10, 0, 2, 0)
+
+	assert.Equal(t, 0, relay.relay(context.TODO()))
+	assert.Equal(t, 0, relay.relay(context.TODO()))
+	publisher.failingCodes = nil
+	assert.Equal(t, 1, relay.relay(context.TODO()))
+	assert.Equal(t, []string{"e2"}, publisher.published)
+
+	pending, err := outboxRepo.ListPending(context.TODO(), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func relaySkipLeasedProduct(t *testing.T) {
+	outboxRepo := newTestOutbox(t,
+		outboxEvent("e1", "XSZ-000741"),
+		outboxEvent("e2", "XXCC"))
+	now := time.Now()
+	leased, err := outboxRepo.ClaimPending(context.TODO(), now, now.Add(time.Minute), 1)
+	assert.NoError(t, err)
+	assert.Len(t, leased, 1)
+	publisher := &publisherRecorder{}
+	relay := NewRelay(outboxRepo, publisher, 10, 0, 3, 0)
+
+	assert.Equal(t, 1, relay.relay(context.TODO()))
+	assert.Equal(t, []string{"e2"}, publisher.published)
+}
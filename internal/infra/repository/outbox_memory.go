@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type OutboxRepositoryInMemory struct {
+	mu     sync.RWMutex
+	events []repository.OutboxEventData
+}
+
+func NewOutboxRepositoryInMemory() repository.OutboxRepository {
+	return &OutboxRepositoryInMemory{}
+}
+
+func (r *OutboxRepositoryInMemory) Insert(ctx context.Context,
+	in repository.OutboxEventData) (repository.OutboxEventData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	in.ID = int64(len(r.events) + 1)
+	in.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	r.events = append(r.events, in)
+	return in, nil
+}
+
+func (r *OutboxRepositoryInMemory) ListPending(ctx context.Context,
+	limit int) ([]repository.OutboxEventData, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := []repository.OutboxEventData{}
+	for _, event := range r.events {
+		if len(events) == limit {
+			break
+		}
+		if event.DeliveredAt == "" && event.DeadAt == "" {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *OutboxRepositoryInMemory) ClaimPending(ctx context.Context, now time.Time,
+	leaseUntil time.Time, limit int) ([]repository.OutboxEventData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []repository.OutboxEventData{}
+	blockedCodes := map[string]bool{}
+	for index := range r.events {
+		if len(events) == limit {
+			break
+		}
+		event := &r.events[index]
+		if event.DeliveredAt != "" || event.DeadAt != "" {
+			continue
+		}
+		code := strings.ToLower(event.AggregateCode)
+		if blockedCodes[code] || event.NextAttemptAt.After(now) {
+			blockedCodes[code] = true
+			continue
+		}
+		event.NextAttemptAt = leaseUntil
+		events = append(events, *event)
+	}
+	return events, nil
+}
+
+func (r *OutboxRepositoryInMemory) Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > int64(len(r.events)) {
+		return nil
+	}
+	r.events[id-1].NextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (r *OutboxRepositoryInMemory) MarkDelivered(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > int64(len(r.events)) {
+		return nil
+	}
+	r.events[id-1].Attempts++
+	r.events[id-1].LastError = ""
+	r.events[id-1].DeliveredAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return nil
+}
+
+func (r *OutboxRepositoryInMemory) MarkFailed(ctx context.Context, id int64, lastError string,
+	nextAttemptAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > int64(len(r.events)) {
+		return nil
+	}
+	r.events[id-1].Attempts++
+	r.events[id-1].LastError = lastError
+	r.events[id-1].NextAttemptAt = nextAttemptAt
+	return nil
+}
+
+func (r *OutboxRepositoryInMemory) MarkDead(ctx context.Context, id int64, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || id > int64(len(r.events)) {
+		return nil
+	}
+	r.events[id-1].Attempts++
+	r.events[id-1].LastError = lastError
+	r.events[id-1].DeadAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type OutboxRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewOutboxRepositoryPostgres(db *sql.DB) repository.OutboxRepository {
+	return OutboxRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r OutboxRepositoryPostgres) Insert(ctx context.Context,
+	in repository.OutboxEventData) (repository.OutboxEventData, error) {
+	return insertPostgresOutboxEvent(ctx, r.db, in)
+}
+
+func (r OutboxRepositoryPostgres) ListPending(ctx context.Context,
+	limit int) ([]repository.OutboxEventData, error) {
+	query := `SELECT o.id, o.event_id, o.event_type, o.aggregate_code, o.payload::text,
+	o.attempts, COALESCE(o.last_error, ''), o.next_attempt_at,
+	to_char(o.created_at, 'YYYY-MM-DD HH24:MI:SS') created_at
+	FROM outbox o WHERE o.delivered_at IS NULL AND o.dead_at IS NULL ORDER BY o.id LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		slog.Error("impossible to list outbox events", slog.Any("msg", err))
+		return nil, err
+	}
+	return scanOutboxEvents(rows)
+}
+
+func (r OutboxRepositoryPostgres) ClaimPending(ctx context.Context, now time.Time,
+	leaseUntil time.Time, limit int) ([]repository.OutboxEventData, error) {
+	query := `SELECT o.id, o.event_id, o.event_type, o.aggregate_code, o.payload::text,
+	o.attempts, COALESCE(o.last_error, ''), o.next_attempt_at,
+	to_char(o.created_at, 'YYYY-MM-DD HH24:MI:SS') created_at
+	FROM outbox o WHERE o.delivered_at IS NULL AND o.dead_at IS NULL
+	AND NOT EXISTS (SELECT 1 FROM outbox h WHERE h.delivered_at IS NULL AND h.dead_at IS NULL
+		AND LOWER(h.aggregate_code) = LOWER(o.aggregate_code) AND h.id <= o.id AND h.next_attempt_at > $1)
+	ORDER BY o.id LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, now.UnixMilli(), limit)
+	if err != nil {
+		slog.Error("impossible to list outbox events", slog.Any("msg", err))
+		return nil, err
+	}
+	events, err := scanOutboxEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	leaseQuery := `UPDATE outbox SET next_attempt_at = $1
+	WHERE id = $2 AND delivered_at IS NULL AND dead_at IS NULL AND next_attempt_at = $3`
+	return claimOutboxEvents(events, func(event repository.OutboxEventData) (sql.Result, error) {
+		return r.db.ExecContext(ctx, leaseQuery, leaseUntil.UnixMilli(), event.ID,
+			event.NextAttemptAt.UnixMilli())
+	}, leaseUntil)
+}
+
+func (r OutboxRepositoryPostgres) Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time) error {
+	query := `UPDATE outbox SET next_attempt_at = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, nextAttemptAt.UnixMilli(), id); err != nil {
+		slog.Error("impossible to reschedule outbox event", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func (r OutboxRepositoryPostgres) MarkDelivered(ctx context.Context, id int64) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = NULL,
+	delivered_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		slog.Error("impossible to mark outbox event as delivered", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func (r OutboxRepositoryPostgres) MarkFailed(ctx context.Context, id int64, lastError string,
+	nextAttemptAt time.Time) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = $1, next_attempt_at = $2 WHERE id = $3`
+
+	if _, err := r.db.ExecContext(ctx, query, lastError, nextAttemptAt.UnixMilli(), id); err != nil {
+		slog.Error("impossible to mark outbox event as failed", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func (r OutboxRepositoryPostgres) MarkDead(ctx context.Context, id int64, lastError string) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = $1, dead_at = CURRENT_TIMESTAMP
+	WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, lastError, id); err != nil {
+		slog.Error("impossible to mark outbox event as dead", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func insertPostgresOutboxEvent(ctx context.Context, queryer sqlQueryer,
+	in repository.OutboxEventData) (repository.OutboxEventData, error) {
+	query := `INSERT INTO outbox (event_id, event_type, aggregate_code, payload)
+	VALUES ($1, $2, $3, $4) RETURNING id`
+
+	if err := queryer.QueryRowContext(ctx, query, in.EventID, in.EventType,
+		in.AggregateCode, string(in.Payload)).Scan(&in.ID); err != nil {
+		slog.Error("impossible to insert outbox event", slog.Any("msg", err))
+		return repository.OutboxEventData{}, err
+	}
+	return in, nil
+}
+
+func writePostgresProductEvent(ctx context.Context, queryer sqlQueryer, operation string,
+	product repository.ProductRepositoryData) error {
+	event, err := newOutboxEvent(ctx, operation, product)
+	if err != nil {
+		return err
+	}
+	_, err = insertPostgresOutboxEvent(ctx, queryer, event)
+	return err
+}
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type OutboxRepositorySQL struct {
+	db *sql.DB
+}
+
+func NewOutboxRepositorySQL(db *sql.DB) repository.OutboxRepository {
+	return OutboxRepositorySQL{
+		db: db,
+	}
+}
+
+func (r OutboxRepositorySQL) Insert(ctx context.Context,
+	in repository.OutboxEventData) (repository.OutboxEventData, error) {
+	return insertOutboxEvent(ctx, r.db, in)
+}
+
+func (r OutboxRepositorySQL) ListPending(ctx context.Context,
+	limit int) ([]repository.OutboxEventData, error) {
+	query := `SELECT o.id, o.event_id, o.event_type, o.aggregate_code, o.payload,
+	o.attempts, COALESCE(o.last_error, ''), o.next_attempt_at,
+	CAST(o.created_at AS CHAR) created_at
+	FROM outbox o WHERE o.delivered_at IS NULL AND o.dead_at IS NULL ORDER BY o.id LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		slog.Error("impossible to list outbox events", slog.Any("msg", err))
+		return nil, err
+	}
+	return scanOutboxEvents(rows)
+}
+
+func (r OutboxRepositorySQL) ClaimPending(ctx context.Context, now time.Time,
+	leaseUntil time.Time, limit int) ([]repository.OutboxEventData, error) {
+	query := `SELECT o.id, o.event_id, o.event_type, o.aggregate_code, o.payload,
+	o.attempts, COALESCE(o.last_error, ''), o.next_attempt_at,
+	CAST(o.created_at AS CHAR) created_at
+	FROM outbox o WHERE o.delivered_at IS NULL AND o.dead_at IS NULL
+	AND NOT EXISTS (SELECT 1 FROM outbox h WHERE h.delivered_at IS NULL AND h.dead_at IS NULL
+		AND LOWER(h.aggregate_code) = LOWER(o.aggregate_code) AND h.id <= o.id AND h.next_attempt_at > ?)
+	ORDER BY o.id LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, now.UnixMilli(), limit)
+	if err != nil {
+		slog.Error("impossible to list outbox events", slog.Any("msg", err))
+		return nil, err
+	}
+	events, err := scanOutboxEvents(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	leaseQuery := `UPDATE outbox SET next_attempt_at = ?
+	WHERE id = ? AND delivered_at IS NULL AND dead_at IS NULL AND next_attempt_at = ?`
+	return claimOutboxEvents(events, func(event repository.OutboxEventData) (sql.Result, error) {
+		return r.db.ExecContext(ctx, leaseQuery, leaseUntil.UnixMilli(), event.ID,
+			event.NextAttemptAt.UnixMilli())
+	}, leaseUntil)
+}
+
+func (r OutboxRepositorySQL) Reschedule(ctx context.Context, id int64, nextAttemptAt time.Time) error {
+	query := `UPDATE outbox SET next_attempt_at = ? WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, nextAttemptAt.UnixMilli(), id); err != nil {
+		slog.Error("impossible to reschedule outbox event", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func (r OutboxRepositorySQL) MarkDelivered(ctx context.Context, id int64) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = NULL,
+	delivered_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		slog.Error("impossible to mark outbox event as delivered", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func (r OutboxRepositorySQL) MarkFailed(ctx context.Context, id int64, lastError string,
+	nextAttemptAt time.Time) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, lastError, nextAttemptAt.UnixMilli(), id); err != nil {
+		slog.Error("impossible to mark outbox event as failed", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func (r OutboxRepositorySQL) MarkDead(ctx context.Context, id int64, lastError string) error {
+	query := `UPDATE outbox SET attempts = attempts + 1, last_error = ?, dead_at = CURRENT_TIMESTAMP
+	WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, lastError, id); err != nil {
+		slog.Error("impossible to mark outbox event as dead", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func scanOutboxEvents(rows *sql.Rows) ([]repository.OutboxEventData, error) {
+	defer rows.Close()
+
+	events := []repository.OutboxEventData{}
+	for rows.Next() {
+		var event repository.OutboxEventData
+		var nextAttemptAt int64
+		if err := rows.Scan(&event.ID, &event.EventID, &event.EventType, &event.AggregateCode,
+			&event.Payload, &event.Attempts, &event.LastError, &nextAttemptAt, &event.CreatedAt); err != nil {
+			slog.Error("impossible to list outbox events", slog.Any("msg", err))
+			return nil, err
+		}
+		event.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		slog.Error("impossible to list outbox events", slog.Any("msg", err))
+		return nil, err
+	}
+	return events, nil
+}
+
+// claimOutboxEvents leases the listed events in id order with a compare-and-set on
+// next_attempt_at. Once another relay wins an event, the rest of its product is skipped so two
+// relays never publish events of the same product concurrently.
+func claimOutboxEvents(events []repository.OutboxEventData,
+	lease func(event repository.OutboxEventData) (sql.Result, error),
+	leaseUntil time.Time) ([]repository.OutboxEventData, error) {
+	claimed := []repository.OutboxEventData{}
+	lostCodes := map[string]bool{}
+	for _, event := range events {
+		code := strings.ToLower(event.AggregateCode)
+		if lostCodes[code] {
+			continue
+		}
+		result, err := lease(event)
+		if err != nil {
+			slog.Error("impossible to lease outbox event", slog.Any("msg", err))
+			return claimed, err
+		}
+		if leased, err := result.RowsAffected(); err != nil || leased == 0 {
+			lostCodes[code] = true
+			continue
+		}
+		event.NextAttemptAt = leaseUntil
+		claimed = append(claimed, event)
+	}
+	return claimed, nil
+}
+
+func insertOutboxEvent(ctx context.Context, executor sqlExecutor,
+	in repository.OutboxEventData) (repository.OutboxEventData, error) {
+	query := `INSERT INTO outbox (event_id, event_type, aggregate_code, payload) VALUES (?, ?, ?, ?)`
+
+	insertResult, err := executor.ExecContext(ctx, query, in.EventID, in.EventType,
+		in.AggregateCode, string(in.Payload))
+	if err != nil {
+		slog.Error("impossible to insert outbox event", slog.Any("msg", err))
+		return repository.OutboxEventData{}, err
+	}
+	id, err := insertResult.LastInsertId()
+	if err != nil {
+		slog.Error("impossible to retrieve last inserted outbox event id", slog.Any("msg", err))
+		return repository.OutboxEventData{}, err
+	}
+	in.ID = id
+	return in, nil
+}
+
+func writeProductEvent(ctx context.Context, executor sqlExecutor, operation string,
+	product repository.ProductRepositoryData) error {
+	event, err := newOutboxEvent(ctx, operation, product)
+	if err != nil {
+		return err
+	}
+	_, err = insertOutboxEvent(ctx, executor, event)
+	return err
+}
+
+func newOutboxEvent(ctx context.Context, operation string,
+	product repository.ProductRepositoryData) (repository.OutboxEventData, error) {
+	event := usecase.NewProductEvent(ctx, operation, product.Code, product)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return repository.OutboxEventData{}, err
+	}
+	return repository.OutboxEventData{
+		EventID:       event.ID,
+		EventType:     event.Type,
+		AggregateCode: event.Code,
+		Payload:       payload,
+	}, nil
+}
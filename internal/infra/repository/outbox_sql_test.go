@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepositorySQL_ClaimPending(t *testing.T) {
+	t.Run("Should skip blocked, leased and dead events on SQLite", shouldClaimSQLiteOutboxEvents)
+	t.Run("Should skip blocked, leased and dead events on MySQL", shouldClaimMySQLOutboxEvents)
+}
+
+func shouldClaimSQLiteOutboxEvents(t *testing.T) {
+	shouldClaimOutboxEvents(t, newSQLiteTestDB(t))
+}
+
+func shouldClaimMySQLOutboxEvents(t *testing.T) {
+	shouldClaimOutboxEvents(t, newMySQLTestDB(t))
+}
+
+func shouldClaimOutboxEvents(t *testing.T, db *sql.DB) {
+	outboxRepo := NewOutboxRepositorySQL(db)
+	ctx := context.Background()
+	for _, event := range []repository.OutboxEventData{
+		{EventID: "e1", AggregateCode: "XSZ-000741"},
+		{EventID: "e2", AggregateCode: "xsz-000741"},
+		{EventID: "e3", AggregateCode: "XXCC"},
+	} {
+		event.EventType = entity.ProductUpdatedEvent
+		event.Payload = []byte(`{}`)
+		_, err := outboxRepo.Insert(ctx, event)
+		assert.Nil(t, err)
+	}
+	now := time.Now()
+	assert.Nil(t, outboxRepo.MarkFailed(ctx, 1, "broker unavailable", now.Add(time.Hour)))
+
+	claimed, err := outboxRepo.ClaimPending(ctx, now, now.Add(time.Minute), 10)
+	assert.Nil(t, err)
+	assert.Len(t, claimed, 1)
+	assert.Equal(t, "e3", claimed[0].EventID)
+
+	claimed, err = outboxRepo.ClaimPending(ctx, now, now.Add(time.Minute), 10)
+	assert.Nil(t, err)
+	assert.Empty(t, claimed)
+
+	assert.Nil(t, outboxRepo.MarkDead(ctx, 1, "broker unavailable"))
+	claimed, err = outboxRepo.ClaimPending(ctx, now, now.Add(time.Minute), 10)
+	assert.Nil(t, err)
+	assert.Len(t, claimed, 1)
+	assert.Equal(t, "e2", claimed[0].EventID)
+
+	pending, err := outboxRepo.ListPending(ctx, 10)
+	assert.Nil(t, err)
+	assert.Len(t, pending, 2)
+}
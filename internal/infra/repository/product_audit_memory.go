@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductAuditRepositoryInMemory struct {
+	mu      sync.RWMutex
+	entries []repository.ProductAuditData
+}
+
+func NewProductAuditRepositoryInMemory() repository.ProductAuditRepository {
+	return &ProductAuditRepositoryInMemory{}
+}
+
+func (r *ProductAuditRepositoryInMemory) Insert(ctx context.Context, in repository.ProductAuditData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	in.ID = int64(len(r.entries) + 1)
+	in.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05.000000")
+	r.entries = append(r.entries, in)
+	return nil
+}
+
+func (r *ProductAuditRepositoryInMemory) ListByCode(ctx context.Context,
+	filter repository.ProductAuditFilter) ([]repository.ProductAuditData, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	code := strings.ToLower(strings.ReplaceAll(filter.Code, " ", ""))
+	matched := []repository.ProductAuditData{}
+	for index := len(r.entries) - 1; index >= 0; index-- {
+		if strings.ToLower(r.entries[index].ProductCode) == code {
+			matched = append(matched, r.entries[index])
+		}
+	}
+
+	total := int64(len(matched))
+	if filter.Offset >= len(matched) {
+		return []repository.ProductAuditData{}, total, nil
+	}
+	end := len(matched)
+	if filter.Limit > 0 && filter.Offset+filter.Limit < end {
+		end = filter.Offset + filter.Limit
+	}
+	return matched[filter.Offset:end], total, nil
+}
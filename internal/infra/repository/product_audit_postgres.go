@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductAuditRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewProductAuditRepositoryPostgres(db *sql.DB) repository.ProductAuditRepository {
+	return ProductAuditRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r ProductAuditRepositoryPostgres) Insert(ctx context.Context, in repository.ProductAuditData) error {
+	return insertPostgresProductAudit(ctx, r.db, in)
+}
+
+func (r ProductAuditRepositoryPostgres) ListByCode(ctx context.Context,
+	filter repository.ProductAuditFilter) ([]repository.ProductAuditData, int64, error) {
+	codeLowerCase := strings.ToLower(strings.ReplaceAll(filter.Code, " ", ""))
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM product_audit a WHERE LOWER(a.product_code) = $1`
+	if err := r.db.QueryRowContext(ctx, countQuery, codeLowerCase).Scan(&total); err != nil {
+		slog.Error("impossible to count product audit", slog.Any("msg", err))
+		return nil, 0, err
+	}
+
+	query := `SELECT a.id, a.product_code, a.operation, a.actor, a.before_data::text, a.after_data::text,
+	to_char(a.created_at, ` + postgresPreciseTimestampFormat + `) created_at
+	FROM product_audit a WHERE LOWER(a.product_code) = $1
+	ORDER BY a.id DESC LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, codeLowerCase, filter.Limit, filter.Offset)
+	if err != nil {
+		slog.Error("impossible to list product audit", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []repository.ProductAuditData{}
+	for rows.Next() {
+		var entry repository.ProductAuditData
+		var before, after sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.ProductCode, &entry.Operation, &entry.Actor,
+			&before, &after, &entry.CreatedAt); err != nil {
+			slog.Error("impossible to list product audit", slog.Any("msg", err))
+			return nil, 0, err
+		}
+		if entry.Before, err = unmarshalSnapshot(before); err != nil {
+			return nil, 0, err
+		}
+		if entry.After, err = unmarshalSnapshot(after); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		slog.Error("impossible to list product audit", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func insertPostgresProductAudit(ctx context.Context, executor sqlExecutor, in repository.ProductAuditData) error {
+	before, err := marshalSnapshot(in.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalSnapshot(in.After)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO product_audit (product_code, operation, actor, before_data, after_data)
+	VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err := executor.ExecContext(ctx, query, in.ProductCode, in.Operation, in.Actor,
+		before, after); err != nil {
+		slog.Error("impossible to insert product audit", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
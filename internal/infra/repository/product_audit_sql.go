@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type productSnapshot struct {
+	ID           int64  `json:"id"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Code         string `json:"code"`
+	Reference    string `json:"reference"`
+	PriceInCents int64  `json:"priceInCents"`
+	CreatedAt    string `json:"createdAt"`
+	UpdatedAt    string `json:"updatedAt"`
+}
+
+type ProductAuditRepositorySQL struct {
+	db *sql.DB
+}
+
+func NewProductAuditRepositorySQL(db *sql.DB) repository.ProductAuditRepository {
+	return ProductAuditRepositorySQL{
+		db: db,
+	}
+}
+
+func (r ProductAuditRepositorySQL) Insert(ctx context.Context, in repository.ProductAuditData) error {
+	return insertProductAudit(ctx, r.db, in)
+}
+
+func (r ProductAuditRepositorySQL) ListByCode(ctx context.Context,
+	filter repository.ProductAuditFilter) ([]repository.ProductAuditData, int64, error) {
+	codeLowerCase := strings.ToLower(strings.ReplaceAll(filter.Code, " ", ""))
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM product_audit a WHERE LOWER(a.product_code) = ?`
+	if err := r.db.QueryRowContext(ctx, countQuery, codeLowerCase).Scan(&total); err != nil {
+		slog.Error("impossible to count product audit", slog.Any("msg", err))
+		return nil, 0, err
+	}
+
+	query := `SELECT a.id, a.product_code, a.operation, a.actor, a.before_data, a.after_data,
+	CAST(a.created_at AS CHAR) created_at
+	FROM product_audit a WHERE LOWER(a.product_code) = ?
+	ORDER BY a.id DESC LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, codeLowerCase, filter.Limit, filter.Offset)
+	if err != nil {
+		slog.Error("impossible to list product audit", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []repository.ProductAuditData{}
+	for rows.Next() {
+		var entry repository.ProductAuditData
+		var before, after sql.NullString
+		if err := rows.Scan(&entry.ID, &entry.ProductCode, &entry.Operation, &entry.Actor,
+			&before, &after, &entry.CreatedAt); err != nil {
+			slog.Error("impossible to list product audit", slog.Any("msg", err))
+			return nil, 0, err
+		}
+		if entry.Before, err = unmarshalSnapshot(before); err != nil {
+			return nil, 0, err
+		}
+		if entry.After, err = unmarshalSnapshot(after); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		slog.Error("impossible to list product audit", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func insertProductAudit(ctx context.Context, executor sqlExecutor, in repository.ProductAuditData) error {
+	before, err := marshalSnapshot(in.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalSnapshot(in.After)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO product_audit (product_code, operation, actor, before_data, after_data)
+	VALUES (?, ?, ?, ?, ?)`
+
+	if _, err := executor.ExecContext(ctx, query, in.ProductCode, in.Operation, in.Actor,
+		before, after); err != nil {
+		slog.Error("impossible to insert product audit", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func marshalSnapshot(product *repository.ProductRepositoryData) (sql.NullString, error) {
+	if product == nil {
+		return sql.NullString{}, nil
+	}
+	snapshot, err := json.Marshal(productSnapshot{
+		ID:           product.ID,
+		Title:        product.Title,
+		Description:  product.Description,
+		Code:         product.Code,
+		Reference:    product.Reference,
+		PriceInCents: product.PriceInCents,
+		CreatedAt:    product.CreatedAt,
+		UpdatedAt:    product.UpdatedAt,
+	})
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(snapshot), Valid: true}, nil
+}
+
+func unmarshalSnapshot(value sql.NullString) (*repository.ProductRepositoryData, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	var snapshot productSnapshot
+	if err := json.Unmarshal([]byte(value.String), &snapshot); err != nil {
+		return nil, err
+	}
+	return &repository.ProductRepositoryData{
+		ID:           snapshot.ID,
+		Title:        snapshot.Title,
+		Description:  snapshot.Description,
+		Code:         snapshot.Code,
+		Reference:    snapshot.Reference,
+		PriceInCents: snapshot.PriceInCents,
+		CreatedAt:    snapshot.CreatedAt,
+		UpdatedAt:    snapshot.UpdatedAt,
+	}, nil
+}
@@ -2,51 +2,366 @@ package repository
 
 import (
 	"context"
-	"math/rand"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
 	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
 )
 
-type ProductRepositoryInMemory struct{}
+const inMemoryTimestampFormat = "2006-01-02 15:04:05"
 
-func NewProductRepositoryInMemory() repository.ProductRepository {
-	return ProductRepositoryInMemory{}
+type productRecord struct {
+	data      repository.ProductRepositoryData
+	deletedAt time.Time
 }
 
-func (r ProductRepositoryInMemory) Insert(ctx context.Context,
+type ProductRepositoryInMemory struct {
+	mu           sync.RWMutex
+	nextID       int64
+	products     []*productRecord
+	active       map[string]*productRecord
+	auditRepo    repository.ProductAuditRepository
+	revisionRepo repository.ProductRevisionRepository
+}
+
+type ProductRepositoryInMemoryOption func(*ProductRepositoryInMemory)
+
+func WithAuditTrail(auditRepo repository.ProductAuditRepository) ProductRepositoryInMemoryOption {
+	return func(r *ProductRepositoryInMemory) {
+		r.auditRepo = auditRepo
+	}
+}
+
+func WithRevisions(revisionRepo repository.ProductRevisionRepository) ProductRepositoryInMemoryOption {
+	return func(r *ProductRepositoryInMemory) {
+		r.revisionRepo = revisionRepo
+	}
+}
+
+func NewProductRepositoryInMemory(opts ...ProductRepositoryInMemoryOption) repository.ProductRepository {
+	r := &ProductRepositoryInMemory{
+		active: map[string]*productRecord{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *ProductRepositoryInMemory) Insert(ctx context.Context,
 	in repository.ProductRepositoryInput) (repository.ProductRepositoryData, error) {
-	now := time.Now().UTC()
+	if err := ctx.Err(); err != nil {
+		return repository.ProductRepositoryData{}, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := productKey(in.Code)
+	if _, ok := r.active[key]; ok {
+		return repository.ProductRepositoryData{}, entity.DuplicatedProductCodeErr
+	}
+
+	now := time.Now().UTC().Format(inMemoryTimestampFormat)
+	record := &productRecord{data: repository.ProductRepositoryData{
+		ID:           r.nextID + 1,
+		Title:        in.Title,
+		Description:  in.Description,
+		Code:         in.Code,
+		Reference:    in.Reference,
+		PriceInCents: in.PriceInCents,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}}
+	if err := r.record(ctx, repository.ProductAuditOperationCreate, nil, &record.data); err != nil {
+		return repository.ProductRepositoryData{}, err
+	}
+	r.nextID++
+	r.products = append(r.products, record)
+	r.active[key] = record
 
 	return repository.ProductRepositoryData{
+		ID:        record.data.ID,
 		Reference: in.Reference,
-		CreatedAt: now.Format("2006-01-02 15:04:05"),
-		ID:        rand.Int63n(10000) + 1,
+		CreatedAt: now,
+		UpdatedAt: now,
 	}, nil
 }
 
-func (r ProductRepositoryInMemory) GetByCode(ctx context.Context, code string) (repository.ProductRepositoryData, error) {
-	now := time.Now().UTC()
+func (r *ProductRepositoryInMemory) GetByCode(ctx context.Context, code string) (repository.ProductRepositoryData, error) {
+	return r.GetByCodeWithFields(ctx, code, nil)
+}
 
-	return repository.ProductRepositoryData{
-		Reference:    "RF009-pods74",
-		CreatedAt:    now.Format("2006-01-02 15:04:05"),
-		ID:           rand.Int63n(10000) + 1,
-		Title:        "Toy",
-		Description:  "Blahahhs",
-		Code:         "XSZ-000741",
-		PriceInCents: int64(51400),
-	}, nil
+func (r *ProductRepositoryInMemory) GetByCodeWithFields(ctx context.Context,
+	code string, fields []string) (repository.ProductRepositoryData, error) {
+	if err := ctx.Err(); err != nil {
+		return repository.ProductRepositoryData{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.active[productKey(code)]
+	if !ok {
+		return repository.ProductRepositoryData{}, entity.ProductNotFoundErr
+	}
+	return projectProduct(record.data, projection(fields)), nil
+}
+
+func (r *ProductRepositoryInMemory) List(ctx context.Context,
+	filter repository.ProductRepositoryFilter) ([]repository.ProductRepositoryData, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var records []*productRecord
+	for _, record := range r.products {
+		if record.deletedAt.IsZero() && matchesFilter(record.data, filter) {
+			records = append(records, record)
+		}
+	}
+	return page(records, filter, projection(filter.Fields))
 }
 
-func (ProductRepositoryInMemory) DeleteByCode(ctx context.Context, code string) (bool, error) {
+func (r *ProductRepositoryInMemory) ListDeleted(ctx context.Context,
+	filter repository.ProductRepositoryFilter) ([]repository.ProductRepositoryData, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var records []*productRecord
+	for _, record := range r.products {
+		if !record.deletedAt.IsZero() && matchesFilter(record.data, filter) {
+			records = append(records, record)
+		}
+	}
+	slices.SortStableFunc(records, func(a, b *productRecord) int {
+		return b.deletedAt.Compare(a.deletedAt)
+	})
+	selectedFields := append(append([]string{}, projection(filter.Fields)...), productFieldDeletedAt)
+	return page(records, filter, selectedFields)
+}
+
+func (r *ProductRepositoryInMemory) GetByCodes(ctx context.Context,
+	codes []string) ([]repository.ProductRepositoryData, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	requested := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		requested[productKey(code)] = true
+	}
+	products := []repository.ProductRepositoryData{}
+	for _, record := range r.products {
+		if record.deletedAt.IsZero() && requested[productKey(record.data.Code)] {
+			products = append(products, record.data)
+		}
+	}
+	return products, nil
+}
+
+func (r *ProductRepositoryInMemory) DeleteByCode(ctx context.Context, code string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := productKey(code)
+	record, ok := r.active[key]
+	if !ok {
+		return false, entity.ProductNotFoundErr
+	}
+	before := record.data
+	if err := r.record(ctx, repository.ProductAuditOperationDelete, &before, nil); err != nil {
+		return false, err
+	}
+	now := time.Now().UTC()
+	record.deletedAt = now
+	record.data.DeletedAt = now.Format(inMemoryTimestampFormat)
+	record.data.UpdatedAt = record.data.DeletedAt
+	delete(r.active, key)
 	return true, nil
 }
 
-func (ProductRepositoryInMemory) Update(ctx context.Context, in repository.ProductRepositoryInput) error {
+func (r *ProductRepositoryInMemory) Update(ctx context.Context, in repository.ProductRepositoryInput) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.active[productKey(in.Code)]
+	if !ok {
+		return entity.ProductNotFoundErr
+	}
+	before, after := record.data, record.data
+	after.Title = in.Title
+	after.Description = in.Description
+	after.Reference = in.Reference
+	after.PriceInCents = in.PriceInCents
+	after.UpdatedAt = time.Now().UTC().Format(inMemoryTimestampFormat)
+	if err := r.record(ctx, updateOperation(in), &before, &after); err != nil {
+		return err
+	}
+	record.data = after
 	return nil
 }
 
+func (r *ProductRepositoryInMemory) RestoreByCode(ctx context.Context, code string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := productKey(code)
+	var restored *productRecord
+	for _, record := range r.products {
+		if record.deletedAt.IsZero() || productKey(record.data.Code) != key {
+			continue
+		}
+		if restored == nil || !record.deletedAt.Before(restored.deletedAt) {
+			restored = record
+		}
+	}
+	if restored == nil {
+		return entity.ProductNotFoundErr
+	}
+	if _, ok := r.active[key]; ok {
+		return entity.DuplicatedProductCodeErr
+	}
+	after := restored.data
+	after.DeletedAt = ""
+	after.UpdatedAt = time.Now().UTC().Format(inMemoryTimestampFormat)
+	if err := r.record(ctx, repository.ProductAuditOperationRestore, nil, &after); err != nil {
+		return err
+	}
+	restored.deletedAt = time.Time{}
+	restored.data = after
+	r.active[key] = restored
+	return nil
+}
+
+func (r *ProductRepositoryInMemory) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	remaining := r.products[:0]
+	var purged int64
+	for _, record := range r.products {
+		if !record.deletedAt.IsZero() && record.deletedAt.Before(deletedBefore) {
+			purged++
+			continue
+		}
+		remaining = append(remaining, record)
+	}
+	clear(r.products[len(remaining):])
+	r.products = remaining
+	return purged, nil
+}
+
+func (r *ProductRepositoryInMemory) record(ctx context.Context, operation string,
+	before, after *repository.ProductRepositoryData) error {
+	product := after
+	if product == nil {
+		product = before
+	}
+	actor := usecase.ActorFromContext(ctx)
+	if r.auditRepo != nil {
+		if err := r.auditRepo.Insert(ctx, repository.ProductAuditData{
+			ProductCode: product.Code,
+			Operation:   operation,
+			Actor:       actor,
+			Before:      before,
+			After:       after,
+		}); err != nil {
+			return err
+		}
+	}
+	if r.revisionRepo != nil {
+		if _, err := r.revisionRepo.Insert(ctx, repository.ProductRevisionData{
+			ProductID:   product.ID,
+			ProductCode: product.Code,
+			Actor:       actor,
+			Product:     *product,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func productKey(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, " ", ""))
+}
+
+func matchesFilter(product repository.ProductRepositoryData, filter repository.ProductRepositoryFilter) bool {
+	if code := productKey(filter.Code); code != "" && !strings.HasPrefix(productKey(product.Code), code) {
+		return false
+	}
+	if title := strings.ToLower(strings.TrimSpace(filter.Title)); title != "" &&
+		!strings.Contains(strings.ToLower(product.Title), title) {
+		return false
+	}
+	if filter.MinPriceInCents > 0 && product.PriceInCents < filter.MinPriceInCents {
+		return false
+	}
+	if filter.MaxPriceInCents > 0 && product.PriceInCents > filter.MaxPriceInCents {
+		return false
+	}
+	return true
+}
+
+func page(records []*productRecord, filter repository.ProductRepositoryFilter,
+	selectedFields []string) ([]repository.ProductRepositoryData, int64, error) {
+	products := []repository.ProductRepositoryData{}
+	start := min(max(filter.Offset, 0), len(records))
+	end := min(start+max(filter.Limit, 0), len(records))
+	for _, record := range records[start:end] {
+		products = append(products, projectProduct(record.data, selectedFields))
+	}
+	return products, int64(len(records)), nil
+}
+
+func projectProduct(product repository.ProductRepositoryData,
+	fields []string) repository.ProductRepositoryData {
+	projected := repository.ProductRepositoryData{Code: product.Code}
+	for _, field := range fields {
+		switch field {
+		case repository.ProductFieldID:
+			projected.ID = product.ID
+		case repository.ProductFieldTitle:
+			projected.Title = product.Title
+		case repository.ProductFieldDescription:
+			projected.Description = product.Description
+		case repository.ProductFieldReference:
+			projected.Reference = product.Reference
+		case repository.ProductFieldPriceInCents:
+			projected.PriceInCents = product.PriceInCents
+		case repository.ProductFieldCreatedAt:
+			projected.CreatedAt = product.CreatedAt
+		case repository.ProductFieldUpdatedAt:
+			projected.UpdatedAt = product.UpdatedAt
+		case productFieldDeletedAt:
+			projected.DeletedAt = product.DeletedAt
+		}
+	}
+	return projected
+}
+
 type ProductRepositoryInMemorySpy struct {
 	ExpectedError error
 	ExpectedData  repository.ProductRepositoryData
@@ -71,3 +386,44 @@ func (spyRepo ProductRepositoryInMemorySpy) Update(ctx context.Context,
 	in repository.ProductRepositoryInput) error {
 	return spyRepo.ExpectedError
 }
+
+func (spyRepo ProductRepositoryInMemorySpy) List(ctx context.Context,
+	filter repository.ProductRepositoryFilter) ([]repository.ProductRepositoryData, int64, error) {
+	if spyRepo.ExpectedError != nil {
+		return nil, 0, spyRepo.ExpectedError
+	}
+	return []repository.ProductRepositoryData{spyRepo.ExpectedData}, 1, nil
+}
+
+func (spyRepo ProductRepositoryInMemorySpy) GetByCodes(ctx context.Context,
+	codes []string) ([]repository.ProductRepositoryData, error) {
+	if spyRepo.ExpectedError != nil {
+		return nil, spyRepo.ExpectedError
+	}
+	return []repository.ProductRepositoryData{spyRepo.ExpectedData}, nil
+}
+
+func (spyRepo ProductRepositoryInMemorySpy) GetByCodeWithFields(ctx context.Context,
+	code string, fields []string) (repository.ProductRepositoryData, error) {
+	return spyRepo.ExpectedData, spyRepo.ExpectedError
+}
+
+func (spyRepo ProductRepositoryInMemorySpy) RestoreByCode(ctx context.Context, code string) error {
+	return spyRepo.ExpectedError
+}
+
+func (spyRepo ProductRepositoryInMemorySpy) ListDeleted(ctx context.Context,
+	filter repository.ProductRepositoryFilter) ([]repository.ProductRepositoryData, int64, error) {
+	if spyRepo.ExpectedError != nil {
+		return nil, 0, spyRepo.ExpectedError
+	}
+	return []repository.ProductRepositoryData{spyRepo.ExpectedData}, 1, nil
+}
+
+func (spyRepo ProductRepositoryInMemorySpy) PurgeDeleted(ctx context.Context,
+	deletedBefore time.Time) (int64, error) {
+	if spyRepo.ExpectedError != nil {
+		return 0, spyRepo.ExpectedError
+	}
+	return 1, nil
+}
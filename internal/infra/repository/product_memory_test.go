@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository/repositorytest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRepositoryInMemory(t *testing.T) {
+	t.Run("Should assign increasing ids under concurrent inserts", shouldAssignIncreasingInMemoryIDs)
+	t.Run("Should accept a single insert for concurrent duplicated codes", shouldRejectConcurrentInMemoryDuplicates)
+	t.Run("Should keep soft deleted products out of reads", shouldHideDeletedInMemoryProducts)
+}
+
+func TestProductRepositoryInMemory_Contract(t *testing.T) {
+	repositorytest.RunProductRepositoryContract(t, func(t *testing.T) repository.ProductRepository {
+		return NewProductRepositoryInMemory()
+	})
+}
+
+func shouldAssignIncreasingInMemoryIDs(t *testing.T) {
+	productRepo := NewProductRepositoryInMemory()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for index := 0; index < 50; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			_, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+				Code: fmt.Sprintf("XSZ-%06d", index), Reference: "ref", PriceInCents: 1000})
+			assert.Nil(t, err)
+		}(index)
+	}
+	wg.Wait()
+
+	products, total, err := productRepo.List(ctx, repository.ProductRepositoryFilter{Limit: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(50), total)
+	for index, product := range products {
+		assert.Equal(t, int64(index+1), product.ID)
+	}
+
+	inserted, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Code: "XSZ-999999", Reference: "ref", PriceInCents: 1000})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(51), inserted.ID)
+}
+
+func shouldRejectConcurrentInMemoryDuplicates(t *testing.T) {
+	productRepo := NewProductRepositoryInMemory()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for index := 0; index < 20; index++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			code := "xsz-000741"
+			if index%2 == 0 {
+				code = "XSZ-000741"
+			}
+			_, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+				Code: code, Reference: "ref", PriceInCents: 1000})
+			errs <- err
+		}(index)
+	}
+	wg.Wait()
+	close(errs)
+
+	var inserted int
+	for err := range errs {
+		if err == nil {
+			inserted++
+			continue
+		}
+		assert.Equal(t, entity.DuplicatedProductCodeErr, err)
+	}
+	assert.Equal(t, 1, inserted)
+}
+
+func shouldHideDeletedInMemoryProducts(t *testing.T) {
+	productRepo := NewProductRepositoryInMemory()
+	ctx := context.Background()
+
+	_, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Nil(t, err)
+	_, err = productRepo.DeleteByCode(ctx, "xsz-000741")
+	assert.Nil(t, err)
+
+	products, err := productRepo.GetByCodes(ctx, []string{"XSZ-000741"})
+	assert.Nil(t, err)
+	assert.Empty(t, products)
+	_, total, err := productRepo.List(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Equal(t, entity.ProductNotFoundErr, productRepo.Update(ctx,
+		repository.ProductRepositoryInput{Code: "XSZ-000741"}))
+
+	deleted, err := productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Equal(t, entity.ProductNotFoundErr, err)
+	assert.False(t, deleted)
+}
@@ -0,0 +1,410 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
+)
+
+const (
+	postgresUniqueViolation        = "23505"
+	postgresTimestampFormat        = "'YYYY-MM-DD HH24:MI:SS'"
+	postgresPreciseTimestampFormat = "'YYYY-MM-DD HH24:MI:SS.US'"
+)
+
+var postgresProductColumns = map[string]string{
+	repository.ProductFieldID:           "p.id",
+	repository.ProductFieldTitle:        "p.title",
+	repository.ProductFieldDescription:  "p.description",
+	repository.ProductFieldCode:         "p.code",
+	repository.ProductFieldReference:    "p.reference",
+	repository.ProductFieldPriceInCents: "p.price_in_cents",
+	repository.ProductFieldCreatedAt:    "to_char(p.created_at, " + postgresTimestampFormat + ") created_at",
+	repository.ProductFieldUpdatedAt:    "to_char(p.updated_at, " + postgresTimestampFormat + ") updated_at",
+	productFieldDeletedAt:               "to_char(p.deleted_at, " + postgresTimestampFormat + ") deleted_at",
+}
+
+type ProductRepositoryPostgres struct {
+	db           *sql.DB
+	router       *database.Router
+	queryTimeout time.Duration
+}
+
+func NewProductRepositoryPostgres(router *database.Router, opts ...ProductRepositorySQLOption) repository.ProductRepository {
+	base := newProductRepositorySQL(router, opts...)
+	return ProductRepositoryPostgres{
+		db:           base.db,
+		router:       router,
+		queryTimeout: base.queryTimeout,
+	}
+}
+
+func (r ProductRepositoryPostgres) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withQueryTimeout(ctx, r.queryTimeout)
+}
+
+func (r ProductRepositoryPostgres) Insert(
+	ctx context.Context,
+	in repository.ProductRepositoryInput) (repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO products (title, description, code, reference, price_in_cents)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, to_char(created_at, ` + postgresTimestampFormat + `),
+	to_char(updated_at, ` + postgresTimestampFormat + `)`
+
+	product := repository.ProductRepositoryData{
+		Title:        in.Title,
+		Description:  in.Description,
+		Code:         in.Code,
+		Reference:    in.Reference,
+		PriceInCents: in.PriceInCents,
+	}
+	if err := tx.QueryRowContext(ctx, query, in.Title, in.Description, in.Code, in.Reference,
+		in.PriceInCents).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return repository.ProductRepositoryData{}, entity.DuplicatedProductCodeErr
+		}
+		slog.Error("impossible insert product", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+
+	if err := writePostgresProductChange(ctx, tx, repository.ProductAuditOperationCreate, nil, &product); err != nil {
+		return repository.ProductRepositoryData{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product insert", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+	r.router.MarkWritten(ctx)
+
+	return repository.ProductRepositoryData{
+		ID:        product.ID,
+		Reference: in.Reference,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+	}, nil
+}
+
+func (r ProductRepositoryPostgres) GetByCode(ctx context.Context,
+	code string) (repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	return getPostgresProductByCode(ctx, r.router.Reader(ctx), code, "")
+}
+
+func getPostgresProductByCode(ctx context.Context, queryer sqlQueryer,
+	code, lock string) (repository.ProductRepositoryData, error) {
+	return selectPostgresProduct(ctx, queryer, code, projection(nil), lock)
+}
+
+func selectPostgresProduct(ctx context.Context, queryer sqlQueryer, code string,
+	selectedFields []string, lock string) (repository.ProductRepositoryData, error) {
+	query := `SELECT ` + selectColumns(postgresProductColumns, selectedFields) +
+		` FROM products p WHERE LOWER(p.code) = $1 AND p.deleted_at IS NULL` + lock
+
+	codeLowerCase := strings.ToLower(strings.ReplaceAll(code, " ", ""))
+
+	var product repository.ProductRepositoryData
+	if err := queryer.QueryRowContext(ctx, query, codeLowerCase).Scan(
+		scanTargets(&product, selectedFields)...); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ProductRepositoryData{}, entity.ProductNotFoundErr
+		}
+		slog.Error("impossible to retrieve product", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+	return product, nil
+}
+
+func (r ProductRepositoryPostgres) GetByCodeWithFields(ctx context.Context,
+	code string, fields []string) (repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	if len(fields) == 0 {
+		return r.GetByCode(ctx, code)
+	}
+	return selectPostgresProduct(ctx, r.router.Reader(ctx), code, projection(fields), "")
+}
+
+func (r ProductRepositoryPostgres) DeleteByCode(ctx context.Context, code string) (bool, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return false, err
+	}
+	defer tx.Rollback()
+
+	product, err := getPostgresProductByCode(ctx, tx, code, ` FOR UPDATE`)
+	if err != nil {
+		return false, err
+	}
+
+	query := `UPDATE products SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, product.ID); err != nil {
+		slog.Error("impossible to delete product", slog.Any("msg", err))
+		return false, err
+	}
+
+	if err := writePostgresProductChange(ctx, tx, repository.ProductAuditOperationDelete, &product, nil); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product delete", slog.Any("msg", err))
+		return false, err
+	}
+	r.router.MarkWritten(ctx)
+	return true, nil
+}
+
+func (r ProductRepositoryPostgres) Update(ctx context.Context,
+	in repository.ProductRepositoryInput) error {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getPostgresProductByCode(ctx, tx, in.Code, ` FOR UPDATE`)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE products SET title = $1, description = $2, reference = $3,
+	 price_in_cents = $4, updated_at = CURRENT_TIMESTAMP
+	 WHERE id = $5`
+
+	if _, err := tx.ExecContext(ctx, query, in.Title, in.Description,
+		in.Reference, in.PriceInCents, before.ID); err != nil {
+		slog.Error("impossible to update product", slog.Any("msg", err))
+		return err
+	}
+
+	after, err := getPostgresProductByCode(ctx, tx, before.Code, "")
+	if err != nil {
+		return err
+	}
+	if err := writePostgresProductChange(ctx, tx, updateOperation(in), &before, &after); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product update", slog.Any("msg", err))
+		return err
+	}
+	r.router.MarkWritten(ctx)
+	return nil
+}
+
+func (r ProductRepositoryPostgres) List(ctx context.Context,
+	filter repository.ProductRepositoryFilter) ([]repository.ProductRepositoryData, int64, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	return r.list(ctx, filter, `p.deleted_at IS NULL`, `p.id`, projection(filter.Fields))
+}
+
+func (r ProductRepositoryPostgres) ListDeleted(ctx context.Context,
+	filter repository.ProductRepositoryFilter) ([]repository.ProductRepositoryData, int64, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	selectedFields := append(append([]string{}, projection(filter.Fields)...), productFieldDeletedAt)
+	return r.list(ctx, filter, `p.deleted_at IS NOT NULL`, `p.deleted_at DESC, p.id`, selectedFields)
+}
+
+func (r ProductRepositoryPostgres) list(ctx context.Context, filter repository.ProductRepositoryFilter,
+	scope, orderBy string, selectedFields []string) ([]repository.ProductRepositoryData, int64, error) {
+
+	conditions := []string{scope}
+	var args []any
+
+	if code := strings.ToLower(strings.ReplaceAll(filter.Code, " ", "")); code != "" {
+		args = append(args, escapeLike(code)+"%")
+		conditions = append(conditions, fmt.Sprintf(`LOWER(p.code) LIKE $%d`, len(args)))
+	}
+	if title := strings.TrimSpace(filter.Title); title != "" {
+		args = append(args, "%"+escapeLike(strings.ToLower(title))+"%")
+		conditions = append(conditions, fmt.Sprintf(`LOWER(p.title) LIKE $%d`, len(args)))
+	}
+	if filter.MinPriceInCents > 0 {
+		args = append(args, filter.MinPriceInCents)
+		conditions = append(conditions, fmt.Sprintf(`p.price_in_cents >= $%d`, len(args)))
+	}
+	if filter.MaxPriceInCents > 0 {
+		args = append(args, filter.MaxPriceInCents)
+		conditions = append(conditions, fmt.Sprintf(`p.price_in_cents <= $%d`, len(args)))
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM products p` + where
+	reader := r.router.Reader(ctx)
+	if err := reader.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		slog.Error("impossible to count products", slog.Any("msg", err))
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + selectColumns(postgresProductColumns, selectedFields) + ` FROM products p` + where +
+		fmt.Sprintf(` ORDER BY %s LIMIT $%d OFFSET $%d`, orderBy, len(args)+1, len(args)+2)
+
+	rows, err := reader.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		slog.Error("impossible to list products", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	products, err := scanProducts(rows, selectedFields)
+	if err != nil {
+		slog.Error("impossible to list products", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+func (r ProductRepositoryPostgres) GetByCodes(ctx context.Context,
+	codes []string) ([]repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	if len(codes) == 0 {
+		return []repository.ProductRepositoryData{}, nil
+	}
+
+	lowerCodes := make([]string, len(codes))
+	for index, code := range codes {
+		lowerCodes[index] = strings.ToLower(strings.ReplaceAll(code, " ", ""))
+	}
+
+	selectedFields := projection(nil)
+	query := `SELECT ` + selectColumns(postgresProductColumns, selectedFields) +
+		` FROM products p WHERE p.deleted_at IS NULL AND LOWER(p.code) = ANY($1)`
+
+	rows, err := r.router.Reader(ctx).QueryContext(ctx, query, lowerCodes)
+	if err != nil {
+		slog.Error("impossible to retrieve products", slog.Any("msg", err))
+		return nil, err
+	}
+	products, err := scanProducts(rows, selectedFields)
+	if err != nil {
+		slog.Error("impossible to retrieve products", slog.Any("msg", err))
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r ProductRepositoryPostgres) RestoreByCode(ctx context.Context, code string) error {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	codeWithoutSpace := strings.ReplaceAll(code, " ", "")
+	codeLowerCase := strings.ToLower(codeWithoutSpace)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE products SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE id = (SELECT id FROM products WHERE LOWER(code) = $1 AND deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC LIMIT 1)`
+
+	result, err := tx.ExecContext(ctx, query, codeLowerCase)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return entity.DuplicatedProductCodeErr
+		}
+		slog.Error("impossible to restore product", slog.Any("msg", err))
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("impossible to restore product", slog.Any("msg", err))
+		return err
+	}
+	if affectedRows == 0 {
+		return entity.ProductNotFoundErr
+	}
+
+	product, err := getPostgresProductByCode(ctx, tx, code, "")
+	if err != nil {
+		return err
+	}
+	if err := writePostgresProductChange(ctx, tx, repository.ProductAuditOperationRestore, nil, &product); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product restore", slog.Any("msg", err))
+		return err
+	}
+	r.router.MarkWritten(ctx)
+	return nil
+}
+
+func (r ProductRepositoryPostgres) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	query := `DELETE FROM products WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, deletedBefore.UTC())
+	if err != nil {
+		slog.Error("impossible to purge products", slog.Any("msg", err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func writePostgresProductChange(ctx context.Context, queryer sqlExecQueryer, operation string,
+	before, after *repository.ProductRepositoryData) error {
+	product := after
+	if product == nil {
+		product = before
+	}
+	actor := usecase.ActorFromContext(ctx)
+	if err := insertPostgresProductAudit(ctx, queryer, repository.ProductAuditData{
+		ProductCode: product.Code,
+		Operation:   operation,
+		Actor:       actor,
+		Before:      before,
+		After:       after,
+	}); err != nil {
+		return err
+	}
+	if _, err := insertPostgresProductRevision(ctx, queryer, repository.ProductRevisionData{
+		ProductID:   product.ID,
+		ProductCode: product.Code,
+		Actor:       actor,
+		Product:     *product,
+	}); err != nil {
+		return err
+	}
+	return writePostgresProductEvent(ctx, queryer, operation, *product)
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == postgresUniqueViolation
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	migrate "github.com/lbsti/eulabs-challenge/db"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository/repositorytest"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPostgresTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	db, err := sql.Open(database.SQLDriverName(database.PostgresDriver), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrate.RunMigrate(db, migrate.Migrations(""), database.PostgresDriver, "up",
+		migrate.MigrationsDir(database.PostgresDriver)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`TRUNCATE products, outbox, product_audit, product_revisions,
+	webhooks, webhook_deliveries RESTART IDENTITY`); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestProductRepositoryPostgres_Contract(t *testing.T) {
+	repositorytest.RunProductRepositoryContract(t, func(t *testing.T) repository.ProductRepository {
+		return NewProductRepositoryPostgres(database.NewRouter(newPostgresTestDB(t), nil))
+	})
+}
+
+func TestProductRepositoryPostgres(t *testing.T) {
+	t.Run("Should write audit entries in the same transaction", shouldWritePostgresAuditEntries)
+	t.Run("Should write revisions keyed by product id", shouldWritePostgresRevisions)
+	t.Run("Should apply the query timeout", shouldApplyPostgresQueryTimeout)
+}
+
+func shouldWritePostgresAuditEntries(t *testing.T) {
+	db := newPostgresTestDB(t)
+	productRepo := NewProductRepositoryPostgres(database.NewRouter(db, nil))
+	auditRepo := NewProductAuditRepositoryPostgres(db)
+	ctx := usecase.WithActor(context.Background(), "merchandiser@eulabs")
+
+	_, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Nil(t, err)
+	assert.Nil(t, productRepo.Update(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "xsz-000741", Reference: "ref", PriceInCents: 1500,
+		Operation: repository.ProductAuditOperationRevert}))
+	_, err = productRepo.DeleteByCode(context.Background(), "XSZ-000741")
+	assert.Nil(t, err)
+
+	entries, total, err := auditRepo.ListByCode(ctx, repository.ProductAuditFilter{Code: "XSZ-000741", Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, repository.ProductAuditOperationDelete, entries[0].Operation)
+	assert.Equal(t, usecase.AnonymousActor, entries[0].Actor)
+	assert.Nil(t, entries[0].After)
+	assert.Equal(t, repository.ProductAuditOperationRevert, entries[1].Operation)
+	assert.Equal(t, "merchandiser@eulabs", entries[1].Actor)
+	assert.Equal(t, int64(1000), entries[1].Before.PriceInCents)
+	assert.Equal(t, int64(1500), entries[1].After.PriceInCents)
+	assert.Equal(t, repository.ProductAuditOperationCreate, entries[2].Operation)
+	assert.Nil(t, entries[2].Before)
+}
+
+func shouldWritePostgresRevisions(t *testing.T) {
+	db := newPostgresTestDB(t)
+	productRepo := NewProductRepositoryPostgres(database.NewRouter(db, nil))
+	revisionRepo := NewProductRevisionRepositoryPostgres(db)
+	ctx := context.Background()
+	input := repository.ProductRepositoryInput{Title: "Toy", Description: "Toy car",
+		Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000}
+
+	deleted, err := productRepo.Insert(ctx, input)
+	assert.Nil(t, err)
+	_, err = productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+	reused, err := productRepo.Insert(ctx, input)
+	assert.Nil(t, err)
+
+	revision, err := revisionRepo.GetByRevision(ctx, deleted.ID, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, deleted.ID, revision.Product.ID)
+	revision, err = revisionRepo.GetByRevision(ctx, reused.ID, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, reused.ID, revision.ProductID)
+	_, err = revisionRepo.GetByRevision(ctx, reused.ID, 2)
+	assert.ErrorIs(t, err, entity.RevisionNotFoundErr)
+}
+
+func shouldApplyPostgresQueryTimeout(t *testing.T) {
+	productRepo := NewProductRepositoryPostgres(database.NewRouter(newPostgresTestDB(t), nil),
+		WithQueryTimeout(time.Nanosecond))
+
+	_, err := productRepo.GetByCode(context.Background(), "XSZ-000741")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
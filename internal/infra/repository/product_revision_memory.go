@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductRevisionRepositoryInMemory struct {
+	mu        sync.RWMutex
+	revisions map[int64][]repository.ProductRevisionData
+}
+
+func NewProductRevisionRepositoryInMemory() repository.ProductRevisionRepository {
+	return &ProductRevisionRepositoryInMemory{
+		revisions: map[int64][]repository.ProductRevisionData{},
+	}
+}
+
+func (r *ProductRevisionRepositoryInMemory) Insert(ctx context.Context,
+	in repository.ProductRevisionData) (repository.ProductRevisionData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	in.Revision = len(r.revisions[in.ProductID]) + 1
+	in.CreatedAt = time.Now().UTC().Format("2006-01-02 15:04:05.000000")
+	r.revisions[in.ProductID] = append(r.revisions[in.ProductID], in)
+	return in, nil
+}
+
+func (r *ProductRevisionRepositoryInMemory) GetByRevision(ctx context.Context,
+	productID int64, revision int) (repository.ProductRevisionData, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	revisions := r.revisions[productID]
+	if revision < 1 || revision > len(revisions) {
+		return repository.ProductRevisionData{}, entity.RevisionNotFoundErr
+	}
+	return revisions[revision-1], nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type ProductRevisionRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewProductRevisionRepositoryPostgres(db *sql.DB) repository.ProductRevisionRepository {
+	return ProductRevisionRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r ProductRevisionRepositoryPostgres) Insert(ctx context.Context,
+	in repository.ProductRevisionData) (repository.ProductRevisionData, error) {
+	for attempt := 1; ; attempt++ {
+		id, err := insertPostgresProductRevision(ctx, r.db, in)
+		if err != nil {
+			if isUniqueViolation(err) && attempt < revisionInsertAttempts {
+				continue
+			}
+			return repository.ProductRevisionData{}, err
+		}
+		return r.getByID(ctx, id)
+	}
+}
+
+func (r ProductRevisionRepositoryPostgres) GetByRevision(ctx context.Context,
+	productID int64, revision int) (repository.ProductRevisionData, error) {
+	query := `SELECT r.product_id, r.product_code, r.revision, r.actor, r.data::text,
+	to_char(r.created_at, ` + postgresPreciseTimestampFormat + `) created_at
+	FROM product_revisions r WHERE r.product_id = $1 AND r.revision = $2`
+
+	return scanPostgresRevision(r.db.QueryRowContext(ctx, query, productID, revision))
+}
+
+func (r ProductRevisionRepositoryPostgres) getByID(ctx context.Context,
+	id int64) (repository.ProductRevisionData, error) {
+	query := `SELECT r.product_id, r.product_code, r.revision, r.actor, r.data::text,
+	to_char(r.created_at, ` + postgresPreciseTimestampFormat + `) created_at
+	FROM product_revisions r WHERE r.id = $1`
+
+	return scanPostgresRevision(r.db.QueryRowContext(ctx, query, id))
+}
+
+func scanPostgresRevision(row *sql.Row) (repository.ProductRevisionData, error) {
+	var revision repository.ProductRevisionData
+	var data sql.NullString
+	if err := row.Scan(&revision.ProductID, &revision.ProductCode, &revision.Revision, &revision.Actor,
+		&data, &revision.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ProductRevisionData{}, entity.RevisionNotFoundErr
+		}
+		slog.Error("impossible to retrieve product revision", slog.Any("msg", err))
+		return repository.ProductRevisionData{}, err
+	}
+	product, err := unmarshalSnapshot(data)
+	if err != nil {
+		return repository.ProductRevisionData{}, err
+	}
+	revision.Product = *product
+	return revision, nil
+}
+
+// insertPostgresProductRevision numbers the revision like insertProductRevision
+// and reads the new id back with RETURNING.
+func insertPostgresProductRevision(ctx context.Context, queryer sqlQueryer,
+	in repository.ProductRevisionData) (int64, error) {
+	data, err := marshalSnapshot(&in.Product)
+	if err != nil {
+		return 0, err
+	}
+
+	var revision int
+	revisionQuery := `SELECT COALESCE(MAX(r.revision), 0) + 1 FROM product_revisions r WHERE r.product_id = $1`
+	if err := queryer.QueryRowContext(ctx, revisionQuery, in.ProductID).Scan(&revision); err != nil {
+		slog.Error("impossible to number product revision", slog.Any("msg", err))
+		return 0, err
+	}
+
+	query := `INSERT INTO product_revisions (product_id, product_code, revision, actor, data)
+	VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	var id int64
+	if err := queryer.QueryRowContext(ctx, query, in.ProductID, in.ProductCode, revision,
+		in.Actor, data).Scan(&id); err != nil {
+		slog.Error("impossible to insert product revision", slog.Any("msg", err))
+		return 0, err
+	}
+	return id, nil
+}
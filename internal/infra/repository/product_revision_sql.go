@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	revisionInsertAttempts = 3
+)
+
+type ProductRevisionRepositorySQL struct {
+	db *sql.DB
+}
+
+func NewProductRevisionRepositorySQL(db *sql.DB) repository.ProductRevisionRepository {
+	return ProductRevisionRepositorySQL{
+		db: db,
+	}
+}
+
+func (r ProductRevisionRepositorySQL) Insert(ctx context.Context,
+	in repository.ProductRevisionData) (repository.ProductRevisionData, error) {
+	for attempt := 1; ; attempt++ {
+		id, err := insertProductRevision(ctx, r.db, in)
+		if err != nil {
+			if (isDuplicateEntry(err) || isUniqueConstraint(err)) && attempt < revisionInsertAttempts {
+				continue
+			}
+			return repository.ProductRevisionData{}, err
+		}
+		return r.getByID(ctx, id)
+	}
+}
+
+func (r ProductRevisionRepositorySQL) GetByRevision(ctx context.Context,
+	productID int64, revision int) (repository.ProductRevisionData, error) {
+	query := `SELECT r.product_id, r.product_code, r.revision, r.actor, r.data,
+	CAST(r.created_at AS CHAR) created_at
+	FROM product_revisions r WHERE r.product_id = ? AND r.revision = ?`
+
+	return r.scanRevision(r.db.QueryRowContext(ctx, query, productID, revision))
+}
+
+func (r ProductRevisionRepositorySQL) getByID(ctx context.Context,
+	id int64) (repository.ProductRevisionData, error) {
+	query := `SELECT r.product_id, r.product_code, r.revision, r.actor, r.data,
+	CAST(r.created_at AS CHAR) created_at
+	FROM product_revisions r WHERE r.id = ?`
+
+	return r.scanRevision(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r ProductRevisionRepositorySQL) scanRevision(row *sql.Row) (repository.ProductRevisionData, error) {
+	var revision repository.ProductRevisionData
+	var data sql.NullString
+	if err := row.Scan(&revision.ProductID, &revision.ProductCode, &revision.Revision, &revision.Actor,
+		&data, &revision.CreatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ProductRevisionData{}, entity.RevisionNotFoundErr
+		}
+		slog.Error("impossible to retrieve product revision", slog.Any("msg", err))
+		return repository.ProductRevisionData{}, err
+	}
+	product, err := unmarshalSnapshot(data)
+	if err != nil {
+		return repository.ProductRevisionData{}, err
+	}
+	revision.Product = *product
+	return revision, nil
+}
+
+type sqlExecQueryer interface {
+	sqlExecutor
+	sqlQueryer
+}
+
+// insertProductRevision numbers the revision after the latest one of the same
+// product id, so a product reusing a code freed by a soft delete starts over.
+// Inside a product write the product row lock serializes the numbering; a
+// concurrent standalone insert fails on the unique key instead.
+func insertProductRevision(ctx context.Context, executor sqlExecQueryer,
+	in repository.ProductRevisionData) (int64, error) {
+	data, err := marshalSnapshot(&in.Product)
+	if err != nil {
+		return 0, err
+	}
+
+	var revision int
+	revisionQuery := `SELECT COALESCE(MAX(r.revision), 0) + 1 FROM product_revisions r WHERE r.product_id = ?`
+	if err := executor.QueryRowContext(ctx, revisionQuery, in.ProductID).Scan(&revision); err != nil {
+		slog.Error("impossible to number product revision", slog.Any("msg", err))
+		return 0, err
+	}
+
+	query := `INSERT INTO product_revisions (product_id, product_code, revision, actor, data)
+	VALUES (?, ?, ?, ?, ?)`
+
+	insertResult, err := executor.ExecContext(ctx, query, in.ProductID, in.ProductCode, revision,
+		in.Actor, data)
+	if err != nil {
+		slog.Error("impossible to insert product revision", slog.Any("msg", err))
+		return 0, err
+	}
+	id, err := insertResult.LastInsertId()
+	if err != nil {
+		slog.Error("impossible to retrieve last inserted revision id", slog.Any("msg", err))
+		return 0, err
+	}
+	return id, nil
+}
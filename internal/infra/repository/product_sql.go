@@ -3,39 +3,102 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log/slog"
 	"strings"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/lbsti/eulabs-challenge/internal/core/entity"
 	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
 )
 
+const (
+	productFieldDeletedAt = "deletedAt"
+	mysqlDuplicateEntry   = 1062
+)
+
+var productColumns = map[string]string{
+	repository.ProductFieldID:           "p.id",
+	repository.ProductFieldTitle:        "p.title",
+	repository.ProductFieldDescription:  "p.description",
+	repository.ProductFieldCode:         "p.code",
+	repository.ProductFieldReference:    "p.reference",
+	repository.ProductFieldPriceInCents: "p.price_in_cents",
+	repository.ProductFieldCreatedAt:    "CAST(p.created_at AS CHAR) created_at",
+	repository.ProductFieldUpdatedAt:    "CAST(p.updated_at AS CHAR) updated_at",
+	productFieldDeletedAt:               "CAST(p.deleted_at AS CHAR) deleted_at",
+}
+
+type sqlQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 type ProductRepositorySQL struct {
-	db *sql.DB
+	db           *sql.DB
+	router       *database.Router
+	queryTimeout time.Duration
+	lock         string
 }
 
-func NewProductRepositorySQL(db *sql.DB) repository.ProductRepository {
-	return ProductRepositorySQL{
-		db: db,
+type ProductRepositorySQLOption func(*ProductRepositorySQL)
+
+func WithQueryTimeout(timeout time.Duration) ProductRepositorySQLOption {
+	return func(r *ProductRepositorySQL) {
+		r.queryTimeout = timeout
 	}
 }
 
+func NewProductRepositorySQL(router *database.Router, opts ...ProductRepositorySQLOption) repository.ProductRepository {
+	return newProductRepositorySQL(router, opts...)
+}
+
+func newProductRepositorySQL(router *database.Router, opts ...ProductRepositorySQLOption) ProductRepositorySQL {
+	r := ProductRepositorySQL{
+		db:     router.Primary(),
+		router: router,
+		lock:   ` FOR UPDATE`,
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r
+}
+
+func (r ProductRepositorySQL) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withQueryTimeout(ctx, r.queryTimeout)
+}
+
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 func (r ProductRepositorySQL) Insert(
 	ctx context.Context,
 	in repository.ProductRepositoryInput) (repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
 
-	datetime := time.Now()
-	datetimeFmt := datetime.Format(time.RFC3339)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+	defer tx.Rollback()
 
-	query := `INSERT INTO products (title, description, code, reference, price_in_cents, 
-	created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO products (title, description, code, reference, price_in_cents)
+	VALUES (?, ?, ?, ?, ?)`
 
-	insertResult, err := r.db.ExecContext(ctx, query, in.Title, in.Description,
-		in.Code, in.Reference, in.PriceInCents, datetimeFmt, datetimeFmt)
+	insertResult, err := tx.ExecContext(ctx, query, in.Title, in.Description,
+		in.Code, in.Reference, in.PriceInCents)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "Duplicate entry") {
+		if isDuplicateEntry(err) {
 			return repository.ProductRepositoryData{}, entity.DuplicatedProductCodeErr
 		}
 		slog.Error("impossible insert product", slog.Any("msg", err))
@@ -44,32 +107,65 @@ func (r ProductRepositorySQL) Insert(
 	id, err := insertResult.LastInsertId()
 	if err != nil {
 		slog.Error("impossible to retrieve last inserted product id", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+
+	product := repository.ProductRepositoryData{
+		ID:           id,
+		Title:        in.Title,
+		Description:  in.Description,
+		Code:         in.Code,
+		Reference:    in.Reference,
+		PriceInCents: in.PriceInCents,
+	}
+	timestampsQuery := `SELECT CAST(created_at AS CHAR), CAST(updated_at AS CHAR)
+	FROM products WHERE id = ?`
+	if err := tx.QueryRowContext(ctx, timestampsQuery, id).Scan(&product.CreatedAt,
+		&product.UpdatedAt); err != nil {
+		slog.Error("impossible to retrieve inserted product timestamps", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+
+	if err := writeProductChange(ctx, tx, repository.ProductAuditOperationCreate, nil, &product); err != nil {
+		return repository.ProductRepositoryData{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product insert", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
 	}
+	r.router.MarkWritten(ctx)
 
 	return repository.ProductRepositoryData{
 		ID:        id,
 		Reference: in.Reference,
-		CreatedAt: datetimeFmt,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
 	}, nil
 }
 
 func (r ProductRepositorySQL) GetByCode(ctx context.Context,
 	code string) (repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	return getProductByCode(ctx, r.router.Reader(ctx), code, "")
+}
+
+func getProductByCode(ctx context.Context, queryer sqlQueryer,
+	code, lock string) (repository.ProductRepositoryData, error) {
 
-	query := `SELECT p.id, p.title, p.description, p.price_in_cents,
+	query := `SELECT p.id, p.title, p.description, p.code, p.price_in_cents,
 	p.reference, 
 	CAST(p.created_at AS CHAR) created_at,
 	CAST(p.updated_at AS CHAR) updated_at 
-	FROM products p WHERE LOWER(p.code) = ?`
+	FROM products p WHERE LOWER(p.code) = ? AND p.deleted_at IS NULL` + lock
 
-	codeWithoutSpace := strings.ReplaceAll(code, " ", "")
-	codeLowerCase := strings.ToLower(codeWithoutSpace)
+	codeLowerCase := strings.ToLower(strings.ReplaceAll(code, " ", ""))
 
 	var id, price int64
-	var title, description, reference, createdAt, updatedAt string
+	var title, description, storedCode, reference, createdAt, updatedAt string
 
-	if err := r.db.QueryRowContext(ctx, query, codeLowerCase).Scan(&id, &title,
-		&description, &price, &reference, &createdAt, &updatedAt); err != nil {
+	if err := queryer.QueryRowContext(ctx, query, codeLowerCase).Scan(&id, &title,
+		&description, &storedCode, &price, &reference, &createdAt, &updatedAt); err != nil {
 		if err == sql.ErrNoRows {
 			return repository.ProductRepositoryData{}, entity.ProductNotFoundErr
 		}
@@ -80,7 +176,7 @@ func (r ProductRepositorySQL) GetByCode(ctx context.Context,
 	return repository.ProductRepositoryData{
 		Title:        title,
 		Description:  description,
-		Code:         codeWithoutSpace,
+		Code:         storedCode,
 		Reference:    reference,
 		PriceInCents: price,
 		CreatedAt:    createdAt,
@@ -89,48 +185,371 @@ func (r ProductRepositorySQL) GetByCode(ctx context.Context,
 	}, nil
 }
 
-func (r ProductRepositorySQL) DeleteByCode(ctx context.Context, code string) (bool, error) {
-	codeWithoutSpace := strings.ReplaceAll(code, " ", "")
-	codeLowerCase := strings.ToLower(codeWithoutSpace)
+func (r ProductRepositorySQL) GetByCodeWithFields(ctx context.Context,
+	code string, fields []string) (repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	if len(fields) == 0 {
+		return r.GetByCode(ctx, code)
+	}
+
+	selectedFields := projection(fields)
+	query := `SELECT ` + selectColumns(productColumns, selectedFields) + ` FROM products p WHERE LOWER(p.code) = ? AND p.deleted_at IS NULL`
 
-	query := `DELETE FROM products WHERE LOWER(code) = ?`
-	result, err := r.db.ExecContext(ctx, query, codeLowerCase)
+	codeLowerCase := strings.ToLower(strings.ReplaceAll(code, " ", ""))
 
+	var product repository.ProductRepositoryData
+	if err := r.router.Reader(ctx).QueryRowContext(ctx, query, codeLowerCase).Scan(
+		scanTargets(&product, selectedFields)...); err != nil {
+		if err == sql.ErrNoRows {
+			return repository.ProductRepositoryData{}, entity.ProductNotFoundErr
+		}
+		slog.Error("impossible to retrieve product", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+	return product, nil
+}
+
+func (r ProductRepositorySQL) DeleteByCode(ctx context.Context, code string) (bool, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		slog.Error("impossible to delete product", slog.Any("msg", err))
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
 		return false, err
 	}
+	defer tx.Rollback()
 
-	if affectedRows, err := result.RowsAffected(); err == nil {
-		if affectedRows > 0 {
-			return affectedRows > 0, nil
-		}
-		return false, entity.ProductNotFoundErr
-	} else {
+	product, err := getProductByCode(ctx, tx, code, r.lock)
+	if err != nil {
+		return false, err
+	}
+
+	query := `UPDATE products SET deleted_at = ? WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, query, time.Now().UTC(), product.ID); err != nil {
 		slog.Error("impossible to delete product", slog.Any("msg", err))
+		return false, err
 	}
 
+	if err := writeProductChange(ctx, tx, repository.ProductAuditOperationDelete, &product, nil); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product delete", slog.Any("msg", err))
+		return false, err
+	}
+	r.router.MarkWritten(ctx)
 	return true, nil
 }
 
 func (r ProductRepositorySQL) Update(ctx context.Context,
 	in repository.ProductRepositoryInput) error {
-	codeWithoutSpace := strings.ReplaceAll(in.Code, " ", "")
-	codeLowerCase := strings.ToLower(codeWithoutSpace)
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getProductByCode(ctx, tx, in.Code, r.lock)
+	if err != nil {
+		return err
+	}
 
 	query := `UPDATE products SET title = ?, description = ?, reference = ?,
 	 price_in_cents = ? 
-	 WHERE LOWER(code) = ?`
+	 WHERE id = ?`
+
+	if _, err := tx.ExecContext(ctx, query, in.Title, in.Description,
+		in.Reference, in.PriceInCents, before.ID); err != nil {
+		slog.Error("impossible to update product", slog.Any("msg", err))
+		return err
+	}
+
+	after, err := getProductByCode(ctx, tx, before.Code, "")
+	if err != nil {
+		return err
+	}
+	if err := writeProductChange(ctx, tx, updateOperation(in), &before, &after); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product update", slog.Any("msg", err))
+		return err
+	}
+	r.router.MarkWritten(ctx)
+	return nil
+}
+
+func (r ProductRepositorySQL) List(ctx context.Context,
+	filter repository.ProductRepositoryFilter) ([]repository.ProductRepositoryData, int64, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	return r.list(ctx, filter, `p.deleted_at IS NULL`, `p.id`, projection(filter.Fields))
+}
+
+func (r ProductRepositorySQL) ListDeleted(ctx context.Context,
+	filter repository.ProductRepositoryFilter) ([]repository.ProductRepositoryData, int64, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	selectedFields := append(append([]string{}, projection(filter.Fields)...), productFieldDeletedAt)
+	return r.list(ctx, filter, `p.deleted_at IS NOT NULL`, `p.deleted_at DESC, p.id`, selectedFields)
+}
 
-	result, err := r.db.ExecContext(ctx, query, in.Title, in.Description,
-		in.Reference, in.PriceInCents, codeLowerCase)
+func (r ProductRepositorySQL) list(ctx context.Context, filter repository.ProductRepositoryFilter,
+	scope, orderBy string, selectedFields []string) ([]repository.ProductRepositoryData, int64, error) {
 
-	if affectedRows, err := result.RowsAffected(); err == nil {
-		if affectedRows > 0 {
-			return nil
+	conditions := []string{scope}
+	var args []any
+
+	if code := strings.ToLower(strings.ReplaceAll(filter.Code, " ", "")); code != "" {
+		conditions = append(conditions, `LOWER(p.code) LIKE ?`)
+		args = append(args, escapeLike(code)+"%")
+	}
+	if title := strings.TrimSpace(filter.Title); title != "" {
+		conditions = append(conditions, `LOWER(p.title) LIKE ?`)
+		args = append(args, "%"+escapeLike(strings.ToLower(title))+"%")
+	}
+	if filter.MinPriceInCents > 0 {
+		conditions = append(conditions, `p.price_in_cents >= ?`)
+		args = append(args, filter.MinPriceInCents)
+	}
+	if filter.MaxPriceInCents > 0 {
+		conditions = append(conditions, `p.price_in_cents <= ?`)
+		args = append(args, filter.MaxPriceInCents)
+	}
+
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM products p` + where
+	reader := r.router.Reader(ctx)
+	if err := reader.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		slog.Error("impossible to count products", slog.Any("msg", err))
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + selectColumns(productColumns, selectedFields) + ` FROM products p` + where +
+		` ORDER BY ` + orderBy + ` LIMIT ? OFFSET ?`
+
+	rows, err := reader.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		slog.Error("impossible to list products", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	products, err := scanProducts(rows, selectedFields)
+	if err != nil {
+		slog.Error("impossible to list products", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+func (r ProductRepositorySQL) GetByCodes(ctx context.Context,
+	codes []string) ([]repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	if len(codes) == 0 {
+		return []repository.ProductRepositoryData{}, nil
+	}
+
+	placeholders := make([]string, len(codes))
+	args := make([]any, len(codes))
+	for index, code := range codes {
+		placeholders[index] = "?"
+		args[index] = strings.ToLower(strings.ReplaceAll(code, " ", ""))
+	}
+
+	selectedFields := projection(nil)
+	query := `SELECT ` + selectColumns(productColumns, selectedFields) +
+		` FROM products p WHERE p.deleted_at IS NULL AND LOWER(p.code) IN (` +
+		strings.Join(placeholders, ", ") + `)`
+
+	rows, err := r.router.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("impossible to retrieve products", slog.Any("msg", err))
+		return nil, err
+	}
+	products, err := scanProducts(rows, selectedFields)
+	if err != nil {
+		slog.Error("impossible to retrieve products", slog.Any("msg", err))
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r ProductRepositorySQL) RestoreByCode(ctx context.Context, code string) error {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	codeWithoutSpace := strings.ReplaceAll(code, " ", "")
+	codeLowerCase := strings.ToLower(codeWithoutSpace)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE products SET deleted_at = NULL
+	WHERE LOWER(code) = ? AND deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC LIMIT 1`
+
+	result, err := tx.ExecContext(ctx, query, codeLowerCase)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return entity.DuplicatedProductCodeErr
 		}
-	} else {
-		slog.Error("impossible to update product", slog.Any("msg", err))
+		slog.Error("impossible to restore product", slog.Any("msg", err))
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("impossible to restore product", slog.Any("msg", err))
+		return err
+	}
+	if affectedRows == 0 {
+		return entity.ProductNotFoundErr
 	}
-	return err
+
+	product, err := getProductByCode(ctx, tx, code, "")
+	if err != nil {
+		return err
+	}
+	if err := writeProductChange(ctx, tx, repository.ProductAuditOperationRestore, nil, &product); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product restore", slog.Any("msg", err))
+		return err
+	}
+	r.router.MarkWritten(ctx)
+	return nil
+}
+
+func (r ProductRepositorySQL) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	query := `DELETE FROM products WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	result, err := r.db.ExecContext(ctx, query, deletedBefore.UTC())
+	if err != nil {
+		slog.Error("impossible to purge products", slog.Any("msg", err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func writeProductChange(ctx context.Context, executor sqlExecQueryer, operation string,
+	before, after *repository.ProductRepositoryData) error {
+	product := after
+	if product == nil {
+		product = before
+	}
+	actor := usecase.ActorFromContext(ctx)
+	if err := insertProductAudit(ctx, executor, repository.ProductAuditData{
+		ProductCode: product.Code,
+		Operation:   operation,
+		Actor:       actor,
+		Before:      before,
+		After:       after,
+	}); err != nil {
+		return err
+	}
+	if _, err := insertProductRevision(ctx, executor, repository.ProductRevisionData{
+		ProductID:   product.ID,
+		ProductCode: product.Code,
+		Actor:       actor,
+		Product:     *product,
+	}); err != nil {
+		return err
+	}
+	return writeProductEvent(ctx, executor, operation, *product)
+}
+
+func updateOperation(in repository.ProductRepositoryInput) string {
+	if in.Operation == "" {
+		return repository.ProductAuditOperationUpdate
+	}
+	return in.Operation
+}
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
+
+func scanProducts(rows *sql.Rows, fields []string) ([]repository.ProductRepositoryData, error) {
+	defer rows.Close()
+
+	products := []repository.ProductRepositoryData{}
+	for rows.Next() {
+		var product repository.ProductRepositoryData
+		if err := rows.Scan(scanTargets(&product, fields)...); err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+func projection(fields []string) []string {
+	if len(fields) == 0 {
+		return repository.ProductFields
+	}
+	requested := map[string]bool{repository.ProductFieldCode: true}
+	for _, field := range fields {
+		requested[field] = true
+	}
+	selectedFields := []string{}
+	for _, field := range repository.ProductFields {
+		if requested[field] {
+			selectedFields = append(selectedFields, field)
+		}
+	}
+	return selectedFields
+}
+
+func selectColumns(productColumns map[string]string, fields []string) string {
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		columns = append(columns, productColumns[field])
+	}
+	return strings.Join(columns, ", ")
+}
+
+func scanTargets(product *repository.ProductRepositoryData, fields []string) []any {
+	targets := make([]any, 0, len(fields))
+	for _, field := range fields {
+		switch field {
+		case repository.ProductFieldID:
+			targets = append(targets, &product.ID)
+		case repository.ProductFieldTitle:
+			targets = append(targets, &product.Title)
+		case repository.ProductFieldDescription:
+			targets = append(targets, &product.Description)
+		case repository.ProductFieldCode:
+			targets = append(targets, &product.Code)
+		case repository.ProductFieldReference:
+			targets = append(targets, &product.Reference)
+		case repository.ProductFieldPriceInCents:
+			targets = append(targets, &product.PriceInCents)
+		case repository.ProductFieldCreatedAt:
+			targets = append(targets, &product.CreatedAt)
+		case repository.ProductFieldUpdatedAt:
+			targets = append(targets, &product.UpdatedAt)
+		case productFieldDeletedAt:
+			targets = append(targets, &product.DeletedAt)
+		}
+	}
+	return targets
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
 }
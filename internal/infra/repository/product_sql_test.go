@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/server"
+	gmssql "github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	migrate "github.com/lbsti/eulabs-challenge/db"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository/repositorytest"
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	gmsMultiClauseAlter       = regexp.MustCompile(`(?s)-- \+goose StatementBegin\nALTER TABLE (\w+)\n(.*?);\n-- \+goose StatementEnd`)
+	gmsSingleStatementTrigger = regexp.MustCompile(`FOR EACH ROW (SIGNAL [^;]*;)`)
+)
+
+// gmsMigrations rewrites the MySQL migrations into statements go-mysql-server
+// can parse: multi-clause ALTER TABLE statements become one ALTER per clause
+// and single-statement trigger bodies are wrapped in BEGIN ... END.
+func gmsMigrations(t *testing.T, migrations fs.FS) fs.FS {
+	files, err := fs.Glob(migrations, "*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewritten := fstest.MapFS{}
+	for _, file := range files {
+		content, err := fs.ReadFile(migrations, file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		migration := gmsMultiClauseAlter.ReplaceAllStringFunc(string(content), func(block string) string {
+			match := gmsMultiClauseAlter.FindStringSubmatch(block)
+			var statements []string
+			for _, clause := range strings.Split(match[2], ",\n") {
+				statements = append(statements, "-- +goose StatementBegin\nALTER TABLE "+match[1]+" "+
+					strings.TrimSpace(clause)+";\n-- +goose StatementEnd")
+			}
+			return strings.Join(statements, "\n\n")
+		})
+		migration = gmsSingleStatementTrigger.ReplaceAllString(migration, "FOR EACH ROW BEGIN $1 END;")
+		rewritten[file] = &fstest.MapFile{Data: []byte(migration)}
+	}
+	return rewritten
+}
+
+func newMySQLTestDB(t *testing.T) *sql.DB {
+	mysqlDB := memory.NewDatabase("eulabsdb")
+	mysqlDB.EnablePrimaryKeyIndexes()
+	provider := memory.NewDBProvider(mysqlDB)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mysqlServer, err := server.NewServer(server.Config{Protocol: "tcp", Listener: listener},
+		sqle.NewDefault(provider),
+		func(ctx context.Context, conn *mysql.Conn, addr string) (gmssql.Session, error) {
+			client := gmssql.Client{Address: addr, Capabilities: conn.Capabilities}
+			session := gmssql.NewBaseSessionWithClientServer(addr, client, conn.ConnectionID)
+			return memory.NewSession(session, provider), nil
+		}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go mysqlServer.Start()
+	t.Cleanup(func() { mysqlServer.Close() })
+
+	dbPool, err := database.NewDBPool(database.DBConfig{Host: "127.0.0.1",
+		Port: listener.Addr().(*net.TCPAddr).Port, User: "root", DBName: "eulabsdb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open(database.SQLDriverName(database.MySQLDriver), dbPool.GetDSN())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrate.RunMigrate(db, gmsMigrations(t, migrate.Migrations("")), database.MySQLDriver, "up",
+		migrate.MigrationsDir(database.MySQLDriver)); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestProductRepositorySQL_Contract(t *testing.T) {
+	repositorytest.RunProductRepositoryContract(t, func(t *testing.T) repository.ProductRepository {
+		return NewProductRepositorySQL(database.NewRouter(newMySQLTestDB(t), nil))
+	})
+}
+
+func TestProductRepositorySQL(t *testing.T) {
+	t.Run("Should write outbox events in the same transaction", shouldWriteMySQLOutboxEvents)
+	t.Run("Should write audit entries in the same transaction", shouldWriteMySQLAuditEntries)
+}
+
+func shouldWriteMySQLOutboxEvents(t *testing.T) {
+	db := newMySQLTestDB(t)
+	productRepo := NewProductRepositorySQL(database.NewRouter(db, nil))
+	outboxRepo := NewOutboxRepositorySQL(db)
+	ctx := context.Background()
+
+	_, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Nil(t, err)
+	_, err = productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "xsz-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Equal(t, entity.DuplicatedProductCodeErr, err)
+
+	events, err := outboxRepo.ListPending(ctx, 10)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, entity.ProductCreatedEvent, events[0].EventType)
+}
+
+func shouldWriteMySQLAuditEntries(t *testing.T) {
+	db := newMySQLTestDB(t)
+	productRepo := NewProductRepositorySQL(database.NewRouter(db, nil))
+	auditRepo := NewProductAuditRepositorySQL(db)
+	ctx := context.Background()
+
+	_, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Nil(t, err)
+	_, err = productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "xsz-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Equal(t, entity.DuplicatedProductCodeErr, err)
+	assert.Nil(t, productRepo.Update(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "XSZ-000741", Reference: "ref", PriceInCents: 1500}))
+
+	entries, total, err := auditRepo.ListByCode(ctx, repository.ProductAuditFilter{Code: "XSZ-000741", Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, repository.ProductAuditOperationUpdate, entries[0].Operation)
+	assert.Equal(t, int64(1000), entries[0].Before.PriceInCents)
+	assert.Equal(t, int64(1500), entries[0].After.PriceInCents)
+	assert.Equal(t, repository.ProductAuditOperationCreate, entries[1].Operation)
+}
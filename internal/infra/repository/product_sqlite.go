@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const sqliteTimestampFormat = "2006-01-02 15:04:05"
+
+type ProductRepositorySQLite struct {
+	ProductRepositorySQL
+}
+
+func NewProductRepositorySQLite(router *database.Router, opts ...ProductRepositorySQLOption) repository.ProductRepository {
+	productRepo := newProductRepositorySQL(router, opts...)
+	productRepo.lock = ""
+	return ProductRepositorySQLite{
+		ProductRepositorySQL: productRepo,
+	}
+}
+
+func (r ProductRepositorySQLite) Insert(
+	ctx context.Context,
+	in repository.ProductRepositoryInput) (repository.ProductRepositoryData, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO products (title, description, code, reference, price_in_cents)
+	VALUES (?, ?, ?, ?, ?) RETURNING id, CAST(created_at AS CHAR), CAST(updated_at AS CHAR)`
+
+	product := repository.ProductRepositoryData{
+		Title:        in.Title,
+		Description:  in.Description,
+		Code:         in.Code,
+		Reference:    in.Reference,
+		PriceInCents: in.PriceInCents,
+	}
+	if err := tx.QueryRowContext(ctx, query, in.Title, in.Description, in.Code, in.Reference,
+		in.PriceInCents).Scan(&product.ID, &product.CreatedAt, &product.UpdatedAt); err != nil {
+		if isUniqueConstraint(err) {
+			return repository.ProductRepositoryData{}, entity.DuplicatedProductCodeErr
+		}
+		slog.Error("impossible insert product", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+
+	if err := writeProductChange(ctx, tx, repository.ProductAuditOperationCreate, nil, &product); err != nil {
+		return repository.ProductRepositoryData{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product insert", slog.Any("msg", err))
+		return repository.ProductRepositoryData{}, err
+	}
+	r.router.MarkWritten(ctx)
+
+	return repository.ProductRepositoryData{
+		ID:        product.ID,
+		Reference: in.Reference,
+		CreatedAt: product.CreatedAt,
+		UpdatedAt: product.UpdatedAt,
+	}, nil
+}
+
+func (r ProductRepositorySQLite) DeleteByCode(ctx context.Context, code string) (bool, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return false, err
+	}
+	defer tx.Rollback()
+
+	product, err := getProductByCode(ctx, tx, code, r.lock)
+	if err != nil {
+		return false, err
+	}
+
+	query := `UPDATE products SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, query, product.ID); err != nil {
+		slog.Error("impossible to delete product", slog.Any("msg", err))
+		return false, err
+	}
+
+	if err := writeProductChange(ctx, tx, repository.ProductAuditOperationDelete, &product, nil); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product delete", slog.Any("msg", err))
+		return false, err
+	}
+	r.router.MarkWritten(ctx)
+	return true, nil
+}
+
+func (r ProductRepositorySQLite) RestoreByCode(ctx context.Context, code string) error {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	codeWithoutSpace := strings.ReplaceAll(code, " ", "")
+	codeLowerCase := strings.ToLower(codeWithoutSpace)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		slog.Error("impossible to begin transaction", slog.Any("msg", err))
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE products SET deleted_at = NULL
+	WHERE id = (SELECT id FROM products WHERE LOWER(code) = ? AND deleted_at IS NOT NULL
+	ORDER BY deleted_at DESC, id DESC LIMIT 1)`
+
+	result, err := tx.ExecContext(ctx, query, codeLowerCase)
+	if err != nil {
+		if isUniqueConstraint(err) {
+			return entity.DuplicatedProductCodeErr
+		}
+		slog.Error("impossible to restore product", slog.Any("msg", err))
+		return err
+	}
+
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("impossible to restore product", slog.Any("msg", err))
+		return err
+	}
+	if affectedRows == 0 {
+		return entity.ProductNotFoundErr
+	}
+
+	product, err := getProductByCode(ctx, tx, code, "")
+	if err != nil {
+		return err
+	}
+	if err := writeProductChange(ctx, tx, repository.ProductAuditOperationRestore, nil, &product); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		slog.Error("impossible to commit product restore", slog.Any("msg", err))
+		return err
+	}
+	r.router.MarkWritten(ctx)
+	return nil
+}
+
+func (r ProductRepositorySQLite) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := r.withQueryTimeout(ctx)
+	defer cancel()
+	query := `DELETE FROM products WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	result, err := r.db.ExecContext(ctx, query, deletedBefore.UTC().Format(sqliteTimestampFormat))
+	if err != nil {
+		slog.Error("impossible to purge products", slog.Any("msg", err))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func isUniqueConstraint(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	migrate "github.com/lbsti/eulabs-challenge/db"
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository/repositorytest"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/lbsti/eulabs-challenge/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSQLiteTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open(database.SQLDriverName(database.SQLiteDriver), "file::memory:?_pragma=foreign_keys(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := migrate.RunMigrate(db, migrate.Migrations(""), database.SQLiteDriver, "up",
+		migrate.MigrationsDir(database.SQLiteDriver)); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestProductRepositorySQLite(t *testing.T) {
+	t.Run("Should insert and retrieve a product", shouldInsertAndRetrieveSQLiteProduct)
+	t.Run("Should reject a duplicated active code", shouldRejectDuplicatedSQLiteProduct)
+	t.Run("Should soft delete, restore and purge a product", shouldDeleteRestoreAndPurgeSQLiteProduct)
+	t.Run("Should write outbox events in the same transaction", shouldWriteSQLiteOutboxEvents)
+	t.Run("Should write audit entries in the same transaction", shouldWriteSQLiteAuditEntries)
+	t.Run("Should write revisions keyed by product id", shouldWriteSQLiteRevisions)
+	t.Run("Should apply the query timeout", shouldApplySQLiteQueryTimeout)
+}
+
+func TestProductRepositorySQLite_Contract(t *testing.T) {
+	repositorytest.RunProductRepositoryContract(t, func(t *testing.T) repository.ProductRepository {
+		return NewProductRepositorySQLite(database.NewRouter(newSQLiteTestDB(t), nil))
+	})
+}
+
+func shouldInsertAndRetrieveSQLiteProduct(t *testing.T) {
+	productRepo := NewProductRepositorySQLite(database.NewRouter(newSQLiteTestDB(t), nil))
+	ctx := context.Background()
+
+	inserted, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), inserted.ID)
+	assert.NotEmpty(t, inserted.CreatedAt)
+
+	assert.Nil(t, productRepo.Update(ctx, repository.ProductRepositoryInput{Title: "Truck",
+		Description: "Toy truck", Code: "xsz-000741", Reference: "ref", PriceInCents: 2000}))
+
+	product, err := productRepo.GetByCode(ctx, "XSZ - 000741")
+	assert.Nil(t, err)
+	assert.Equal(t, "Truck", product.Title)
+	assert.Equal(t, int64(2000), product.PriceInCents)
+
+	products, total, err := productRepo.List(ctx, repository.ProductRepositoryFilter{
+		Title: "tru", Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "XSZ-000741", products[0].Code)
+}
+
+func shouldRejectDuplicatedSQLiteProduct(t *testing.T) {
+	productRepo := NewProductRepositorySQLite(database.NewRouter(newSQLiteTestDB(t), nil))
+	ctx := context.Background()
+	input := repository.ProductRepositoryInput{Title: "Toy", Description: "Toy car",
+		Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000}
+
+	_, err := productRepo.Insert(ctx, input)
+	assert.Nil(t, err)
+
+	input.Code = "xsz-000741"
+	_, err = productRepo.Insert(ctx, input)
+	assert.Equal(t, entity.DuplicatedProductCodeErr, err)
+}
+
+func shouldDeleteRestoreAndPurgeSQLiteProduct(t *testing.T) {
+	productRepo := NewProductRepositorySQLite(database.NewRouter(newSQLiteTestDB(t), nil))
+	ctx := context.Background()
+	input := repository.ProductRepositoryInput{Title: "Toy", Description: "Toy car",
+		Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000}
+
+	_, err := productRepo.Insert(ctx, input)
+	assert.Nil(t, err)
+	deleted, err := productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+	assert.True(t, deleted)
+
+	_, err = productRepo.GetByCode(ctx, "XSZ-000741")
+	assert.Equal(t, entity.ProductNotFoundErr, err)
+
+	_, err = productRepo.Insert(ctx, input)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.DuplicatedProductCodeErr, productRepo.RestoreByCode(ctx, "XSZ-000741"))
+
+	_, err = productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+	assert.Nil(t, productRepo.RestoreByCode(ctx, "XSZ-000741"))
+
+	trash, total, err := productRepo.ListDeleted(ctx, repository.ProductRepositoryFilter{Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.NotEmpty(t, trash[0].DeletedAt)
+
+	purged, err := productRepo.PurgeDeleted(ctx, time.Now().Add(time.Minute))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.Equal(t, entity.ProductNotFoundErr, productRepo.RestoreByCode(ctx, "XSZ-000741"))
+}
+
+func shouldWriteSQLiteOutboxEvents(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	productRepo := NewProductRepositorySQLite(database.NewRouter(db, nil))
+	outboxRepo := NewOutboxRepositorySQL(db)
+	ctx := context.Background()
+
+	_, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Nil(t, err)
+	_, err = productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+
+	events, err := outboxRepo.ListPending(ctx, 10)
+	assert.Nil(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, entity.ProductCreatedEvent, events[0].EventType)
+	assert.Equal(t, entity.ProductDeletedEvent, events[1].EventType)
+
+	assert.Nil(t, outboxRepo.MarkDelivered(ctx, events[0].ID))
+	events, err = outboxRepo.ListPending(ctx, 10)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+}
+
+func shouldWriteSQLiteAuditEntries(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	productRepo := NewProductRepositorySQLite(database.NewRouter(db, nil))
+	auditRepo := NewProductAuditRepositorySQL(db)
+	ctx := usecase.WithActor(context.Background(), "merchandiser@eulabs")
+
+	_, err := productRepo.Insert(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000})
+	assert.Nil(t, err)
+	assert.Nil(t, productRepo.Update(ctx, repository.ProductRepositoryInput{Title: "Toy",
+		Description: "Toy car", Code: "xsz-000741", Reference: "ref", PriceInCents: 1500,
+		Operation: repository.ProductAuditOperationRevert}))
+	_, err = productRepo.DeleteByCode(context.Background(), "XSZ-000741")
+	assert.Nil(t, err)
+
+	entries, total, err := auditRepo.ListByCode(ctx, repository.ProductAuditFilter{Code: "XSZ-000741", Limit: 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, repository.ProductAuditOperationDelete, entries[0].Operation)
+	assert.Equal(t, usecase.AnonymousActor, entries[0].Actor)
+	assert.Nil(t, entries[0].After)
+	assert.Equal(t, repository.ProductAuditOperationRevert, entries[1].Operation)
+	assert.Equal(t, "merchandiser@eulabs", entries[1].Actor)
+	assert.Equal(t, int64(1000), entries[1].Before.PriceInCents)
+	assert.Equal(t, int64(1500), entries[1].After.PriceInCents)
+	assert.Equal(t, repository.ProductAuditOperationCreate, entries[2].Operation)
+	assert.Nil(t, entries[2].Before)
+}
+
+func shouldWriteSQLiteRevisions(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	productRepo := NewProductRepositorySQLite(database.NewRouter(db, nil))
+	revisionRepo := NewProductRevisionRepositorySQL(db)
+	ctx := context.Background()
+	input := repository.ProductRepositoryInput{Title: "Toy", Description: "Toy car",
+		Code: "XSZ-000741", Reference: "ref", PriceInCents: 1000}
+
+	deleted, err := productRepo.Insert(ctx, input)
+	assert.Nil(t, err)
+	_, err = productRepo.DeleteByCode(ctx, "XSZ-000741")
+	assert.Nil(t, err)
+	reused, err := productRepo.Insert(ctx, input)
+	assert.Nil(t, err)
+
+	revision, err := revisionRepo.GetByRevision(ctx, deleted.ID, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, deleted.ID, revision.Product.ID)
+	revision, err = revisionRepo.GetByRevision(ctx, reused.ID, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, reused.ID, revision.ProductID)
+	_, err = revisionRepo.GetByRevision(ctx, reused.ID, 2)
+	assert.ErrorIs(t, err, entity.RevisionNotFoundErr)
+}
+
+func shouldApplySQLiteQueryTimeout(t *testing.T) {
+	productRepo := NewProductRepositorySQLite(database.NewRouter(newSQLiteTestDB(t), nil),
+		WithQueryTimeout(time.Nanosecond))
+
+	_, err := productRepo.GetByCode(context.Background(), "XSZ-000741")
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
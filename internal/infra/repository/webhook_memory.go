@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type WebhookRepositoryInMemory struct {
+	mu       sync.RWMutex
+	lastID   int64
+	webhooks map[int64]repository.WebhookData
+}
+
+func NewWebhookRepositoryInMemory() repository.WebhookRepository {
+	return &WebhookRepositoryInMemory{
+		webhooks: map[int64]repository.WebhookData{},
+	}
+}
+
+func (r *WebhookRepositoryInMemory) Insert(ctx context.Context,
+	in repository.WebhookInput) (repository.WebhookData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastID++
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	webhook := repository.WebhookData{
+		ID:         r.lastID,
+		URL:        in.URL,
+		Secret:     in.Secret,
+		EventTypes: slices.Clone(in.EventTypes),
+		Active:     in.Active,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	r.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (r *WebhookRepositoryInMemory) GetByID(ctx context.Context, id int64) (repository.WebhookData, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return repository.WebhookData{}, entity.WebhookNotFoundErr
+	}
+	return webhook, nil
+}
+
+func (r *WebhookRepositoryInMemory) List(ctx context.Context) ([]repository.WebhookData, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]repository.WebhookData, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	slices.SortFunc(webhooks, func(a, b repository.WebhookData) int {
+		return int(a.ID - b.ID)
+	})
+	return webhooks, nil
+}
+
+func (r *WebhookRepositoryInMemory) Update(ctx context.Context, id int64,
+	in repository.WebhookInput) (repository.WebhookData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return repository.WebhookData{}, entity.WebhookNotFoundErr
+	}
+	webhook.URL = in.URL
+	webhook.Secret = in.Secret
+	webhook.EventTypes = slices.Clone(in.EventTypes)
+	webhook.Active = in.Active
+	webhook.UpdatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	r.webhooks[id] = webhook
+	return webhook, nil
+}
+
+func (r *WebhookRepositoryInMemory) DeleteByID(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.webhooks[id]; !ok {
+		return entity.WebhookNotFoundErr
+	}
+	delete(r.webhooks, id)
+	return nil
+}
+
+type WebhookDeliveryRepositoryInMemory struct {
+	mu         sync.RWMutex
+	deliveries []repository.WebhookDeliveryData
+}
+
+func NewWebhookDeliveryRepositoryInMemory() repository.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepositoryInMemory{}
+}
+
+func (r *WebhookDeliveryRepositoryInMemory) Insert(ctx context.Context,
+	in repository.WebhookDeliveryData) (repository.WebhookDeliveryData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == in.WebhookID && delivery.EventID == in.EventID {
+			return repository.WebhookDeliveryData{}, entity.DuplicatedDeliveryErr
+		}
+	}
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	in.ID = int64(len(r.deliveries) + 1)
+	in.CreatedAt = now
+	in.UpdatedAt = now
+	r.deliveries = append(r.deliveries, in)
+	return in, nil
+}
+
+func (r *WebhookDeliveryRepositoryInMemory) GetByID(ctx context.Context,
+	id int64) (repository.WebhookDeliveryData, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id < 1 || id > int64(len(r.deliveries)) {
+		return repository.WebhookDeliveryData{}, entity.DeliveryNotFoundErr
+	}
+	return r.deliveries[id-1], nil
+}
+
+func (r *WebhookDeliveryRepositoryInMemory) Update(ctx context.Context,
+	in repository.WebhookDeliveryData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if in.ID < 1 || in.ID > int64(len(r.deliveries)) {
+		return entity.DeliveryNotFoundErr
+	}
+	delivery := &r.deliveries[in.ID-1]
+	delivery.Status = in.Status
+	delivery.Attempts = in.Attempts
+	delivery.LastError = in.LastError
+	delivery.NextAttemptAt = in.NextAttemptAt
+	delivery.UpdatedAt = time.Now().UTC().Format("2006-01-02 15:04:05")
+	return nil
+}
+
+func (r *WebhookDeliveryRepositoryInMemory) List(ctx context.Context,
+	filter repository.WebhookDeliveryFilter) ([]repository.WebhookDeliveryData, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []repository.WebhookDeliveryData{}
+	for index := len(r.deliveries) - 1; index >= 0; index-- {
+		if r.deliveries[index].Status == filter.Status {
+			matched = append(matched, r.deliveries[index])
+		}
+	}
+
+	total := int64(len(matched))
+	if filter.Offset >= len(matched) {
+		return []repository.WebhookDeliveryData{}, total, nil
+	}
+	end := len(matched)
+	if filter.Limit > 0 && filter.Offset+filter.Limit < end {
+		end = filter.Offset + filter.Limit
+	}
+	return matched[filter.Offset:end], total, nil
+}
+
+func (r *WebhookDeliveryRepositoryInMemory) ClaimDue(ctx context.Context, now time.Time,
+	leaseUntil time.Time, limit int) ([]repository.WebhookDeliveryData, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	claimed := []repository.WebhookDeliveryData{}
+	for index := range r.deliveries {
+		if len(claimed) == limit {
+			break
+		}
+		delivery := &r.deliveries[index]
+		if delivery.Status != repository.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.NextAttemptAt = leaseUntil
+		claimed = append(claimed, *delivery)
+	}
+	return claimed, nil
+}
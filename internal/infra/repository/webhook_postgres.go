@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	postgresWebhookColumns = `w.id, w.url, w.secret, w.event_types::text, w.active,
+	to_char(w.created_at, ` + postgresTimestampFormat + `) created_at,
+	to_char(w.updated_at, ` + postgresTimestampFormat + `) updated_at`
+	postgresDeliveryColumns = `d.id, d.webhook_id, d.event_id, d.event_type, d.payload::text, d.status,
+	d.attempts, COALESCE(d.last_error, ''), d.next_attempt_at,
+	to_char(d.created_at, ` + postgresTimestampFormat + `) created_at,
+	to_char(d.updated_at, ` + postgresTimestampFormat + `) updated_at`
+)
+
+type WebhookRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewWebhookRepositoryPostgres(db *sql.DB) repository.WebhookRepository {
+	return WebhookRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r WebhookRepositoryPostgres) Insert(ctx context.Context,
+	in repository.WebhookInput) (repository.WebhookData, error) {
+	eventTypes, err := json.Marshal(nonNilEventTypes(in.EventTypes))
+	if err != nil {
+		return repository.WebhookData{}, err
+	}
+
+	query := `INSERT INTO webhooks (url, secret, event_types, active) VALUES ($1, $2, $3, $4) RETURNING id`
+
+	var id int64
+	if err := r.db.QueryRowContext(ctx, query, in.URL, in.Secret, string(eventTypes),
+		in.Active).Scan(&id); err != nil {
+		slog.Error("impossible to insert webhook", slog.Any("msg", err))
+		return repository.WebhookData{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r WebhookRepositoryPostgres) GetByID(ctx context.Context, id int64) (repository.WebhookData, error) {
+	query := `SELECT ` + postgresWebhookColumns + ` FROM webhooks w WHERE w.id = $1`
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.WebhookData{}, entity.WebhookNotFoundErr
+		}
+		slog.Error("impossible to retrieve webhook", slog.Any("msg", err))
+		return repository.WebhookData{}, err
+	}
+	return webhook, nil
+}
+
+func (r WebhookRepositoryPostgres) List(ctx context.Context) ([]repository.WebhookData, error) {
+	query := `SELECT ` + postgresWebhookColumns + ` FROM webhooks w ORDER BY w.id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		slog.Error("impossible to list webhooks", slog.Any("msg", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []repository.WebhookData{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			slog.Error("impossible to list webhooks", slog.Any("msg", err))
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r WebhookRepositoryPostgres) Update(ctx context.Context, id int64,
+	in repository.WebhookInput) (repository.WebhookData, error) {
+	eventTypes, err := json.Marshal(nonNilEventTypes(in.EventTypes))
+	if err != nil {
+		return repository.WebhookData{}, err
+	}
+
+	query := `UPDATE webhooks SET url = $1, secret = $2, event_types = $3, active = $4,
+	updated_at = CURRENT_TIMESTAMP WHERE id = $5`
+	if _, err := r.db.ExecContext(ctx, query, in.URL, in.Secret, string(eventTypes),
+		in.Active, id); err != nil {
+		slog.Error("impossible to update webhook", slog.Any("msg", err))
+		return repository.WebhookData{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r WebhookRepositoryPostgres) DeleteByID(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		slog.Error("impossible to delete webhook", slog.Any("msg", err))
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("impossible to delete webhook", slog.Any("msg", err))
+		return err
+	}
+	if affectedRows == 0 {
+		return entity.WebhookNotFoundErr
+	}
+	return nil
+}
+
+type WebhookDeliveryRepositoryPostgres struct {
+	db *sql.DB
+}
+
+func NewWebhookDeliveryRepositoryPostgres(db *sql.DB) repository.WebhookDeliveryRepository {
+	return WebhookDeliveryRepositoryPostgres{
+		db: db,
+	}
+}
+
+func (r WebhookDeliveryRepositoryPostgres) Insert(ctx context.Context,
+	in repository.WebhookDeliveryData) (repository.WebhookDeliveryData, error) {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+
+	var id int64
+	if err := r.db.QueryRowContext(ctx, query, in.WebhookID, in.EventID, in.EventType,
+		string(in.Payload), in.Status, in.Attempts, in.NextAttemptAt.UnixMilli()).Scan(&id); err != nil {
+		if isUniqueViolation(err) {
+			return repository.WebhookDeliveryData{}, entity.DuplicatedDeliveryErr
+		}
+		slog.Error("impossible to insert webhook delivery", slog.Any("msg", err))
+		return repository.WebhookDeliveryData{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r WebhookDeliveryRepositoryPostgres) GetByID(ctx context.Context,
+	id int64) (repository.WebhookDeliveryData, error) {
+	query := `SELECT ` + postgresDeliveryColumns + ` FROM webhook_deliveries d WHERE d.id = $1`
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.WebhookDeliveryData{}, entity.DeliveryNotFoundErr
+		}
+		slog.Error("impossible to retrieve webhook delivery", slog.Any("msg", err))
+		return repository.WebhookDeliveryData{}, err
+	}
+	return delivery, nil
+}
+
+func (r WebhookDeliveryRepositoryPostgres) Update(ctx context.Context, in repository.WebhookDeliveryData) error {
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = $2, last_error = $3, next_attempt_at = $4,
+	updated_at = CURRENT_TIMESTAMP WHERE id = $5`
+	if _, err := r.db.ExecContext(ctx, query, in.Status, in.Attempts, in.LastError,
+		in.NextAttemptAt.UnixMilli(), in.ID); err != nil {
+		slog.Error("impossible to update webhook delivery", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func (r WebhookDeliveryRepositoryPostgres) List(ctx context.Context,
+	filter repository.WebhookDeliveryFilter) ([]repository.WebhookDeliveryData, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM webhook_deliveries d WHERE d.status = $1`
+	if err := r.db.QueryRowContext(ctx, countQuery, filter.Status).Scan(&total); err != nil {
+		slog.Error("impossible to count webhook deliveries", slog.Any("msg", err))
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + postgresDeliveryColumns + ` FROM webhook_deliveries d WHERE d.status = $1
+	ORDER BY d.id DESC LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		slog.Error("impossible to list webhook deliveries", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []repository.WebhookDeliveryData{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			slog.Error("impossible to list webhook deliveries", slog.Any("msg", err))
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, total, rows.Err()
+}
+
+func (r WebhookDeliveryRepositoryPostgres) ClaimDue(ctx context.Context, now time.Time,
+	leaseUntil time.Time, limit int) ([]repository.WebhookDeliveryData, error) {
+	query := `SELECT ` + postgresDeliveryColumns + ` FROM webhook_deliveries d
+	WHERE d.status = $1 AND d.next_attempt_at <= $2
+	ORDER BY d.next_attempt_at, d.id LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, repository.WebhookDeliveryPending, now.UnixMilli(), limit)
+	if err != nil {
+		slog.Error("impossible to list due webhook deliveries", slog.Any("msg", err))
+		return nil, err
+	}
+	due := []repository.WebhookDeliveryData{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			rows.Close()
+			slog.Error("impossible to list due webhook deliveries", slog.Any("msg", err))
+			return nil, err
+		}
+		due = append(due, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		slog.Error("impossible to list due webhook deliveries", slog.Any("msg", err))
+		return nil, err
+	}
+
+	leaseQuery := `UPDATE webhook_deliveries SET next_attempt_at = $1
+	WHERE id = $2 AND status = $3 AND next_attempt_at = $4`
+	claimed := []repository.WebhookDeliveryData{}
+	for _, delivery := range due {
+		result, err := r.db.ExecContext(ctx, leaseQuery, leaseUntil.UnixMilli(), delivery.ID,
+			repository.WebhookDeliveryPending, delivery.NextAttemptAt.UnixMilli())
+		if err != nil {
+			slog.Error("impossible to lease webhook delivery", slog.Any("msg", err))
+			return claimed, err
+		}
+		if leased, err := result.RowsAffected(); err != nil || leased == 0 {
+			continue
+		}
+		delivery.NextAttemptAt = leaseUntil
+		claimed = append(claimed, delivery)
+	}
+	return claimed, nil
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+type WebhookRepositorySQL struct {
+	db *sql.DB
+}
+
+func NewWebhookRepositorySQL(db *sql.DB) repository.WebhookRepository {
+	return WebhookRepositorySQL{
+		db: db,
+	}
+}
+
+func (r WebhookRepositorySQL) Insert(ctx context.Context,
+	in repository.WebhookInput) (repository.WebhookData, error) {
+	eventTypes, err := json.Marshal(nonNilEventTypes(in.EventTypes))
+	if err != nil {
+		return repository.WebhookData{}, err
+	}
+
+	query := `INSERT INTO webhooks (url, secret, event_types, active) VALUES (?, ?, ?, ?)`
+	insertResult, err := r.db.ExecContext(ctx, query, in.URL, in.Secret, string(eventTypes), in.Active)
+	if err != nil {
+		slog.Error("impossible to insert webhook", slog.Any("msg", err))
+		return repository.WebhookData{}, err
+	}
+	id, err := insertResult.LastInsertId()
+	if err != nil {
+		slog.Error("impossible to retrieve last inserted webhook id", slog.Any("msg", err))
+		return repository.WebhookData{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r WebhookRepositorySQL) GetByID(ctx context.Context, id int64) (repository.WebhookData, error) {
+	query := `SELECT w.id, w.url, w.secret, w.event_types, w.active,
+	CAST(w.created_at AS CHAR) created_at,
+	CAST(w.updated_at AS CHAR) updated_at
+	FROM webhooks w WHERE w.id = ?`
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.WebhookData{}, entity.WebhookNotFoundErr
+		}
+		slog.Error("impossible to retrieve webhook", slog.Any("msg", err))
+		return repository.WebhookData{}, err
+	}
+	return webhook, nil
+}
+
+func (r WebhookRepositorySQL) List(ctx context.Context) ([]repository.WebhookData, error) {
+	query := `SELECT w.id, w.url, w.secret, w.event_types, w.active,
+	CAST(w.created_at AS CHAR) created_at,
+	CAST(w.updated_at AS CHAR) updated_at
+	FROM webhooks w ORDER BY w.id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		slog.Error("impossible to list webhooks", slog.Any("msg", err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []repository.WebhookData{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			slog.Error("impossible to list webhooks", slog.Any("msg", err))
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r WebhookRepositorySQL) Update(ctx context.Context, id int64,
+	in repository.WebhookInput) (repository.WebhookData, error) {
+	eventTypes, err := json.Marshal(nonNilEventTypes(in.EventTypes))
+	if err != nil {
+		return repository.WebhookData{}, err
+	}
+
+	query := `UPDATE webhooks SET url = ?, secret = ?, event_types = ?, active = ? WHERE id = ?`
+	if _, err := r.db.ExecContext(ctx, query, in.URL, in.Secret, string(eventTypes),
+		in.Active, id); err != nil {
+		slog.Error("impossible to update webhook", slog.Any("msg", err))
+		return repository.WebhookData{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r WebhookRepositorySQL) DeleteByID(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = ?`, id)
+	if err != nil {
+		slog.Error("impossible to delete webhook", slog.Any("msg", err))
+		return err
+	}
+	affectedRows, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("impossible to delete webhook", slog.Any("msg", err))
+		return err
+	}
+	if affectedRows == 0 {
+		return entity.WebhookNotFoundErr
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(row rowScanner) (repository.WebhookData, error) {
+	var webhook repository.WebhookData
+	var eventTypes string
+	if err := row.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &eventTypes, &webhook.Active,
+		&webhook.CreatedAt, &webhook.UpdatedAt); err != nil {
+		return repository.WebhookData{}, err
+	}
+	if err := json.Unmarshal([]byte(eventTypes), &webhook.EventTypes); err != nil {
+		return repository.WebhookData{}, err
+	}
+	return webhook, nil
+}
+
+func nonNilEventTypes(eventTypes []string) []string {
+	if eventTypes == nil {
+		return []string{}
+	}
+	return eventTypes
+}
+
+type WebhookDeliveryRepositorySQL struct {
+	db *sql.DB
+}
+
+func NewWebhookDeliveryRepositorySQL(db *sql.DB) repository.WebhookDeliveryRepository {
+	return WebhookDeliveryRepositorySQL{
+		db: db,
+	}
+}
+
+func (r WebhookDeliveryRepositorySQL) Insert(ctx context.Context,
+	in repository.WebhookDeliveryData) (repository.WebhookDeliveryData, error) {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, attempts,
+	next_attempt_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	insertResult, err := r.db.ExecContext(ctx, query, in.WebhookID, in.EventID, in.EventType,
+		string(in.Payload), in.Status, in.Attempts, in.NextAttemptAt.UnixMilli())
+	if err != nil {
+		if isDuplicateEntry(err) || isUniqueConstraint(err) {
+			return repository.WebhookDeliveryData{}, entity.DuplicatedDeliveryErr
+		}
+		slog.Error("impossible to insert webhook delivery", slog.Any("msg", err))
+		return repository.WebhookDeliveryData{}, err
+	}
+	id, err := insertResult.LastInsertId()
+	if err != nil {
+		slog.Error("impossible to retrieve last inserted delivery id", slog.Any("msg", err))
+		return repository.WebhookDeliveryData{}, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r WebhookDeliveryRepositorySQL) GetByID(ctx context.Context,
+	id int64) (repository.WebhookDeliveryData, error) {
+	query := `SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	COALESCE(d.last_error, ''), d.next_attempt_at,
+	CAST(d.created_at AS CHAR) created_at,
+	CAST(d.updated_at AS CHAR) updated_at
+	FROM webhook_deliveries d WHERE d.id = ?`
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return repository.WebhookDeliveryData{}, entity.DeliveryNotFoundErr
+		}
+		slog.Error("impossible to retrieve webhook delivery", slog.Any("msg", err))
+		return repository.WebhookDeliveryData{}, err
+	}
+	return delivery, nil
+}
+
+func (r WebhookDeliveryRepositorySQL) Update(ctx context.Context, in repository.WebhookDeliveryData) error {
+	query := `UPDATE webhook_deliveries SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?
+	WHERE id = ?`
+	if _, err := r.db.ExecContext(ctx, query, in.Status, in.Attempts, in.LastError,
+		in.NextAttemptAt.UnixMilli(), in.ID); err != nil {
+		slog.Error("impossible to update webhook delivery", slog.Any("msg", err))
+		return err
+	}
+	return nil
+}
+
+func (r WebhookDeliveryRepositorySQL) List(ctx context.Context,
+	filter repository.WebhookDeliveryFilter) ([]repository.WebhookDeliveryData, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM webhook_deliveries d WHERE d.status = ?`
+	if err := r.db.QueryRowContext(ctx, countQuery, filter.Status).Scan(&total); err != nil {
+		slog.Error("impossible to count webhook deliveries", slog.Any("msg", err))
+		return nil, 0, err
+	}
+
+	query := `SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	COALESCE(d.last_error, ''), d.next_attempt_at,
+	CAST(d.created_at AS CHAR) created_at,
+	CAST(d.updated_at AS CHAR) updated_at
+	FROM webhook_deliveries d WHERE d.status = ? ORDER BY d.id DESC LIMIT ? OFFSET ?`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		slog.Error("impossible to list webhook deliveries", slog.Any("msg", err))
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []repository.WebhookDeliveryData{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			slog.Error("impossible to list webhook deliveries", slog.Any("msg", err))
+			return nil, 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, total, rows.Err()
+}
+
+func (r WebhookDeliveryRepositorySQL) ClaimDue(ctx context.Context, now time.Time,
+	leaseUntil time.Time, limit int) ([]repository.WebhookDeliveryData, error) {
+	query := `SELECT d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	COALESCE(d.last_error, ''), d.next_attempt_at,
+	CAST(d.created_at AS CHAR) created_at,
+	CAST(d.updated_at AS CHAR) updated_at
+	FROM webhook_deliveries d WHERE d.status = ? AND d.next_attempt_at <= ?
+	ORDER BY d.next_attempt_at, d.id LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, repository.WebhookDeliveryPending, now.UnixMilli(), limit)
+	if err != nil {
+		slog.Error("impossible to list due webhook deliveries", slog.Any("msg", err))
+		return nil, err
+	}
+	due := []repository.WebhookDeliveryData{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			rows.Close()
+			slog.Error("impossible to list due webhook deliveries", slog.Any("msg", err))
+			return nil, err
+		}
+		due = append(due, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		slog.Error("impossible to list due webhook deliveries", slog.Any("msg", err))
+		return nil, err
+	}
+
+	leaseQuery := `UPDATE webhook_deliveries SET next_attempt_at = ?
+	WHERE id = ? AND status = ? AND next_attempt_at = ?`
+	claimed := []repository.WebhookDeliveryData{}
+	for _, delivery := range due {
+		result, err := r.db.ExecContext(ctx, leaseQuery, leaseUntil.UnixMilli(), delivery.ID,
+			repository.WebhookDeliveryPending, delivery.NextAttemptAt.UnixMilli())
+		if err != nil {
+			slog.Error("impossible to lease webhook delivery", slog.Any("msg", err))
+			return claimed, err
+		}
+		if leased, err := result.RowsAffected(); err != nil || leased == 0 {
+			continue
+		}
+		delivery.NextAttemptAt = leaseUntil
+		claimed = append(claimed, delivery)
+	}
+	return claimed, nil
+}
+
+func scanDelivery(row rowScanner) (repository.WebhookDeliveryData, error) {
+	var delivery repository.WebhookDeliveryData
+	var payload string
+	var nextAttemptAt int64
+	if err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType,
+		&payload, &delivery.Status, &delivery.Attempts, &delivery.LastError, &nextAttemptAt,
+		&delivery.CreatedAt, &delivery.UpdatedAt); err != nil {
+		return repository.WebhookDeliveryData{}, err
+	}
+	delivery.Payload = []byte(payload)
+	delivery.NextAttemptAt = time.UnixMilli(nextAttemptAt)
+	return delivery, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookDeliveryRepositorySQL_ClaimDue(t *testing.T) {
+	t.Run("Should lease due deliveries on SQLite", shouldLeaseDueSQLiteDeliveries)
+	t.Run("Should lease due deliveries on MySQL", shouldLeaseDueMySQLDeliveries)
+	t.Run("Should lease due deliveries on Postgres", shouldLeaseDuePostgresDeliveries)
+}
+
+func shouldLeaseDueSQLiteDeliveries(t *testing.T) {
+	db := newSQLiteTestDB(t)
+	shouldLeaseDueDeliveries(t, NewWebhookRepositorySQL(db), NewWebhookDeliveryRepositorySQL(db))
+}
+
+func shouldLeaseDueMySQLDeliveries(t *testing.T) {
+	db := newMySQLTestDB(t)
+	shouldLeaseDueDeliveries(t, NewWebhookRepositorySQL(db), NewWebhookDeliveryRepositorySQL(db))
+}
+
+func shouldLeaseDuePostgresDeliveries(t *testing.T) {
+	db := newPostgresTestDB(t)
+	shouldLeaseDueDeliveries(t, NewWebhookRepositoryPostgres(db), NewWebhookDeliveryRepositoryPostgres(db))
+}
+
+func shouldLeaseDueDeliveries(t *testing.T, webhookRepo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository) {
+	ctx := context.Background()
+	webhook, err := webhookRepo.Insert(ctx, repository.WebhookInput{
+		URL: "https://example.com/hook", Secret: "s3cr3t", Active: true})
+	assert.Nil(t, err)
+	now := time.Now()
+	for index, nextAttemptAt := range []time.Time{now, now.Add(time.Hour)} {
+		_, err := deliveryRepo.Insert(ctx, repository.WebhookDeliveryData{WebhookID: webhook.ID,
+			EventID: "3f0b5a9e-4a7c-4a53-9a53-0b4c0cc1f2a" + strconv.Itoa(index), EventType: "product.updated",
+			Payload: []byte(`{}`), Status: repository.WebhookDeliveryPending, NextAttemptAt: nextAttemptAt})
+		assert.Nil(t, err)
+	}
+	_, err = deliveryRepo.Insert(ctx, repository.WebhookDeliveryData{WebhookID: webhook.ID,
+		EventID: "3f0b5a9e-4a7c-4a53-9a53-0b4c0cc1f2a0", EventType: "product.updated",
+		Payload: []byte(`{}`), Status: repository.WebhookDeliveryPending, NextAttemptAt: now})
+	assert.ErrorIs(t, err, entity.DuplicatedDeliveryErr)
+
+	leaseUntil := now.Add(time.Minute)
+	claimed, err := deliveryRepo.ClaimDue(ctx, now, leaseUntil, 10)
+	assert.Nil(t, err)
+	assert.Len(t, claimed, 1)
+	assert.Equal(t, int64(1), claimed[0].ID)
+	assert.Equal(t, leaseUntil.UnixMilli(), claimed[0].NextAttemptAt.UnixMilli())
+
+	claimed, err = deliveryRepo.ClaimDue(ctx, now, leaseUntil, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, claimed)
+
+	delivery, err := deliveryRepo.GetByID(ctx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, leaseUntil.UnixMilli(), delivery.NextAttemptAt.UnixMilli())
+}
@@ -0,0 +1,99 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+)
+
+const (
+	subscriberBufferSize = 64
+)
+
+type EventLog struct {
+	mu          sync.Mutex
+	capacity    int
+	epoch       string
+	sequence    int64
+	records     []usecase.ProductEventRecord
+	subscribers map[chan usecase.ProductEventRecord]struct{}
+}
+
+func NewEventLog(capacity int) *EventLog {
+	return &EventLog{
+		capacity:    max(capacity, 1),
+		epoch:       strconv.FormatInt(time.Now().UnixNano(), 36),
+		subscribers: map[chan usecase.ProductEventRecord]struct{}{},
+	}
+}
+
+func (l *EventLog) Emit(ctx context.Context, event usecase.ProductEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sequence++
+	record := usecase.ProductEventRecord{Epoch: l.epoch, Sequence: l.sequence, Event: event}
+	if len(l.records) == l.capacity {
+		copy(l.records, l.records[1:])
+		l.records[len(l.records)-1] = record
+	} else {
+		l.records = append(l.records, record)
+	}
+
+	for subscriber := range l.subscribers {
+		select {
+		case subscriber <- record:
+		default:
+			delete(l.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+func (l *EventLog) Epoch() string {
+	return l.epoch
+}
+
+func (l *EventLog) Subscribe(epoch string, after int64, replay bool) ([]usecase.ProductEventRecord,
+	<-chan usecase.ProductEventRecord, func(), *usecase.ProductEventRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	backlog := []usecase.ProductEventRecord{}
+	var reset *usecase.ProductEventRecord
+	if replay && !l.retains(epoch, after) {
+		reset = &usecase.ProductEventRecord{Epoch: l.epoch, Sequence: l.sequence}
+	} else if replay {
+		for _, record := range l.records {
+			if record.Sequence > after {
+				backlog = append(backlog, record)
+			}
+		}
+	}
+
+	subscriber := make(chan usecase.ProductEventRecord, subscriberBufferSize)
+	l.subscribers[subscriber] = struct{}{}
+	unsubscribe := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := l.subscribers[subscriber]; ok {
+			delete(l.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+	return backlog, subscriber, unsubscribe, reset
+}
+
+func (l *EventLog) retains(epoch string, after int64) bool {
+	if epoch != l.epoch || after > l.sequence {
+		return false
+	}
+	oldest := l.sequence + 1
+	if len(l.records) > 0 {
+		oldest = l.records[0].Sequence
+	}
+	return after >= oldest-1
+}
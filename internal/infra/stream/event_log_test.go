@@ -0,0 +1,88 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func productEvent(code string) usecase.ProductEvent {
+	return usecase.ProductEvent{Type: entity.ProductUpdatedEvent, Code: code}
+}
+
+func TestEventLog_Subscribe(t *testing.T) {
+	t.Run("Should replay only the retained events after the last event id", eventLogReplayBounded)
+	t.Run("Should reset when the last event id is outside the retained range", eventLogResetOutsideRetainedRange)
+	t.Run("Should not replay events for a live subscription", eventLogLiveOnly)
+	t.Run("Should close slow subscribers", eventLogCloseSlowSubscriber)
+}
+
+func eventLogReplayBounded(t *testing.T) {
+	eventLog := NewEventLog(3)
+	for _, code := range []string{"A", "B", "C", "D", "E"} {
+		eventLog.Emit(context.TODO(), productEvent(code))
+	}
+
+	backlog, _, unsubscribe, reset := eventLog.Subscribe(eventLog.Epoch(), 3, true)
+	defer unsubscribe()
+	assert.Nil(t, reset)
+	assert.Len(t, backlog, 2)
+	assert.Equal(t, int64(4), backlog[0].Sequence)
+	assert.Equal(t, "E", backlog[1].Event.Code)
+
+	backlog, _, unsubscribeRetained, reset := eventLog.Subscribe(eventLog.Epoch(), 2, true)
+	defer unsubscribeRetained()
+	assert.Nil(t, reset)
+	assert.Len(t, backlog, 3)
+	assert.Equal(t, "C", backlog[0].Event.Code)
+}
+
+func eventLogResetOutsideRetainedRange(t *testing.T) {
+	eventLog := NewEventLog(3)
+	for _, code := range []string{"A", "B", "C", "D", "E"} {
+		eventLog.Emit(context.TODO(), productEvent(code))
+	}
+
+	for _, position := range []struct {
+		epoch string
+		after int64
+	}{{eventLog.Epoch(), 1}, {eventLog.Epoch(), 6}, {"previous", 4}} {
+		backlog, _, unsubscribe, reset := eventLog.Subscribe(position.epoch, position.after, true)
+		unsubscribe()
+		assert.Empty(t, backlog)
+		assert.Equal(t, &usecase.ProductEventRecord{Epoch: eventLog.Epoch(), Sequence: 5}, reset)
+	}
+}
+
+func eventLogLiveOnly(t *testing.T) {
+	eventLog := NewEventLog(10)
+	eventLog.Emit(context.TODO(), productEvent("A"))
+
+	backlog, records, unsubscribe, reset := eventLog.Subscribe("", 0, false)
+	defer unsubscribe()
+	assert.Empty(t, backlog)
+	assert.Nil(t, reset)
+
+	eventLog.Emit(context.TODO(), productEvent("B"))
+	record := <-records
+	assert.Equal(t, int64(2), record.Sequence)
+	assert.Equal(t, "B", record.Event.Code)
+}
+
+func eventLogCloseSlowSubscriber(t *testing.T) {
+	eventLog := NewEventLog(10)
+	_, records, unsubscribe, _ := eventLog.Subscribe("", 0, false)
+	defer unsubscribe()
+
+	for index := 0; index <= subscriberBufferSize; index++ {
+		eventLog.Emit(context.TODO(), productEvent("A"))
+	}
+	received := 0
+	for range records {
+		received++
+	}
+	assert.Equal(t, subscriberBufferSize, received)
+}
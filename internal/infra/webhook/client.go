@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+)
+
+var ErrForbiddenTarget = errors.New("webhook target address is not allowed")
+
+// NewHTTPClient returns a client that refuses to connect to loopback, private,
+// link-local and reserved addresses outside allowedNetworks. The check runs on
+// the resolved address of every connection, so DNS answers and redirects
+// cannot reach internal services either.
+func NewHTTPClient(timeout time.Duration, allowedNetworks []netip.Prefix) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !entity.PermitsWebhookTarget(addrPort.Addr(), allowedNetworks) {
+				return fmt.Errorf("%s: %w", address, ErrForbiddenTarget)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Run("Should refuse to connect to a loopback address", httpClientLoopbackErr)
+	t.Run("Should connect to addresses inside allowed networks", httpClientAllowedNetwork)
+}
+
+func httpClientLoopbackErr(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPClient(time.Second, nil).Get(server.URL)
+	assert.ErrorIs(t, err, ErrForbiddenTarget)
+}
+
+func httpClientAllowedNetwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	response, err := NewHTTPClient(time.Second,
+		[]netip.Prefix{netip.MustParsePrefix("127.0.0.0/8")}).Get(server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	response.Body.Close()
+}
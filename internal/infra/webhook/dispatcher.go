@@ -0,0 +1,212 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+)
+
+const (
+	DeliveryHeader  = "X-Webhook-Delivery"
+	EventHeader     = "X-Webhook-Event"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+
+	maxErrorBodySize = 512
+	minDeliveryLease = time.Minute
+)
+
+type Dispatcher struct {
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	client       *http.Client
+	maxAttempts  int
+	backoff      time.Duration
+	workers      int
+	interval     time.Duration
+	lease        time.Duration
+	wake         chan struct{}
+}
+
+func NewDispatcher(webhookRepo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository, client *http.Client,
+	maxAttempts int, backoff time.Duration, workers int, interval time.Duration) *Dispatcher {
+	lease := minDeliveryLease
+	if client.Timeout*2 > lease {
+		lease = client.Timeout * 2
+	}
+	return &Dispatcher{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		client:       client,
+		maxAttempts:  maxAttempts,
+		backoff:      backoff,
+		workers:      workers,
+		interval:     interval,
+		lease:        lease,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Publish enqueues the deliveries of a product event relayed from the outbox, so webhooks only
+// see changes that were committed. The relay retries an event until every publisher accepts it,
+// so deliveries already enqueued for the event are kept instead of duplicated.
+func (d *Dispatcher) Publish(ctx context.Context, event repository.OutboxEventData) error {
+	webhooks, err := d.webhookRepo.List(ctx)
+	if err != nil {
+		slog.Error("impossible to list webhooks", slog.Any("msg", err), slog.String("event", event.EventID))
+		return err
+	}
+
+	enqueued := false
+	for _, webhookData := range webhooks {
+		webhook := entity.Webhook{Active: webhookData.Active, EventTypes: webhookData.EventTypes}
+		if !webhook.Accepts(event.EventType) {
+			continue
+		}
+		if _, err := d.deliveryRepo.Insert(ctx, repository.WebhookDeliveryData{
+			WebhookID:     webhookData.ID,
+			EventID:       event.EventID,
+			EventType:     event.EventType,
+			Payload:       event.Payload,
+			Status:        repository.WebhookDeliveryPending,
+			NextAttemptAt: time.Now(),
+		}); err != nil {
+			if errors.Is(err, entity.DuplicatedDeliveryErr) {
+				continue
+			}
+			slog.Error("impossible to enqueue webhook delivery", slog.Any("msg", err),
+				slog.String("event", event.EventID))
+			return err
+		}
+		enqueued = true
+	}
+	if enqueued {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Run delivers pending rows whose next attempt is due, including the ones left behind by a
+// previous process, with at most workers requests in flight. Each claimed row is leased in the
+// repository so other replicas skip it until the attempt finishes or the lease expires.
+func (d *Dispatcher) Run(ctx context.Context) {
+	if d.interval <= 0 || d.workers <= 0 {
+		slog.Info("webhook dispatcher disabled")
+		return
+	}
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	slots := make(chan struct{}, d.workers)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		if d.dispatch(ctx, slots, &wg) == d.workers {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, slots chan struct{}, wg *sync.WaitGroup) int {
+	now := time.Now()
+	deliveries, err := d.deliveryRepo.ClaimDue(ctx, now, now.Add(d.lease), d.workers)
+	if err != nil {
+		slog.Error("impossible to claim webhook deliveries", slog.Any("msg", err))
+	}
+	for _, delivery := range deliveries {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(delivery repository.WebhookDeliveryData) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			d.Deliver(ctx, delivery)
+		}(delivery)
+	}
+	return len(deliveries)
+}
+
+func (d *Dispatcher) Deliver(ctx context.Context,
+	delivery repository.WebhookDeliveryData) repository.WebhookDeliveryData {
+	delivery.Attempts++
+	if err := d.send(ctx, delivery); err != nil {
+		delivery.LastError = err.Error()
+		delivery.Status = repository.WebhookDeliveryPending
+		delivery.NextAttemptAt = time.Now().Add(d.backoff << (delivery.Attempts - 1))
+		if delivery.Attempts >= d.maxAttempts {
+			delivery.Status = repository.WebhookDeliveryDead
+		}
+		slog.Warn("webhook delivery failed", slog.Any("msg", err),
+			slog.Int64("delivery", delivery.ID), slog.Int("attempts", delivery.Attempts))
+	} else {
+		delivery.LastError = ""
+		delivery.Status = repository.WebhookDeliveryDelivered
+	}
+
+	if err := d.deliveryRepo.Update(ctx, delivery); err != nil {
+		slog.Error("impossible to update webhook delivery", slog.Any("msg", err))
+	}
+	return delivery
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery repository.WebhookDeliveryData) error {
+	webhookData, err := d.webhookRepo.GetByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookData.URL,
+		bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	request.Header.Set(EventHeader, delivery.EventType)
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, Sign(webhookData.Secret, timestamp, delivery.Payload))
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+		return fmt.Errorf("unexpected status %d: %s", response.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lbsti/eulabs-challenge/internal/core/entity"
+	"github.com/lbsti/eulabs-challenge/internal/core/repository"
+	infrarepository "github.com/lbsti/eulabs-challenge/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDispatcher(t *testing.T, url string,
+	eventTypes []string) (*Dispatcher, repository.WebhookDeliveryRepository) {
+	webhookRepo := infrarepository.NewWebhookRepositoryInMemory()
+	deliveryRepo := infrarepository.NewWebhookDeliveryRepositoryInMemory()
+	_, err := webhookRepo.Insert(context.TODO(), repository.WebhookInput{
+		URL:        url,
+		Secret:     "s3cr3t",
+		EventTypes: eventTypes,
+		Active:     true,
+	})
+	assert.NoError(t, err)
+	return NewDispatcher(webhookRepo, deliveryRepo, http.DefaultClient, 3, time.Millisecond,
+		2, time.Millisecond), deliveryRepo
+}
+
+func runDispatcher(t *testing.T, dispatcher *Dispatcher) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatcher.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func waitDelivery(t *testing.T, deliveryRepo repository.WebhookDeliveryRepository,
+	id int64) repository.WebhookDeliveryData {
+	var delivery repository.WebhookDeliveryData
+	assert.Eventually(t, func() bool {
+		delivery, _ = deliveryRepo.GetByID(context.TODO(), id)
+		return delivery.Status != "" && delivery.Status != repository.WebhookDeliveryPending
+	}, 5*time.Second, time.Millisecond)
+	return delivery
+}
+
+func productEvent() repository.OutboxEventData {
+	return repository.OutboxEventData{
+		EventID:       "3f0b5a9e-4a7c-4a53-9a53-0b4c0cc1f2a1",
+		EventType:     entity.ProductUpdatedEvent,
+		AggregateCode: "XSZ-000741",
+		Payload:       []byte(`{"id":"3f0b5a9e-4a7c-4a53-9a53-0b4c0cc1f2a1","code":"XSZ-000741"}`),
+	}
+}
+
+func TestDispatcher_Publish(t *testing.T) {
+	t.Run("Should deliver a signed event", dispatcherDeliverSigned)
+	t.Run("Should retry and deliver after failures", dispatcherRetrySuccess)
+	t.Run("Should move the delivery to dead letters after max attempts", dispatcherDeadLetter)
+	t.Run("Should skip webhooks not subscribed to the event type", dispatcherSkipEventType)
+	t.Run("Should not duplicate deliveries of an event published again", dispatcherPublishAgain)
+}
+
+func TestDispatcher_Run(t *testing.T) {
+	t.Run("Should resume pending deliveries left by a previous process", dispatcherResumePending)
+	t.Run("Should not exceed the configured workers", dispatcherBoundedWorkers)
+}
+
+func dispatcherDeliverSigned(t *testing.T) {
+	var signature, expected string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		signature = r.Header.Get(SignatureHeader)
+		expected = Sign("s3cr3t", timestamp, body)
+		assert.Equal(t, entity.ProductUpdatedEvent, r.Header.Get(EventHeader))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher, deliveryRepo := newTestDispatcher(t, server.URL, nil)
+	runDispatcher(t, dispatcher)
+	assert.NoError(t, dispatcher.Publish(context.TODO(), productEvent()))
+
+	delivery := waitDelivery(t, deliveryRepo, 1)
+	assert.Equal(t, repository.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, expected, signature)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+}
+
+func dispatcherRetrySuccess(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dispatcher, deliveryRepo := newTestDispatcher(t, server.URL, nil)
+	runDispatcher(t, dispatcher)
+	assert.NoError(t, dispatcher.Publish(context.TODO(), productEvent()))
+
+	delivery := waitDelivery(t, deliveryRepo, 1)
+	assert.Equal(t, repository.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Empty(t, delivery.LastError)
+}
+
+func dispatcherDeadLetter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+
+	dispatcher, deliveryRepo := newTestDispatcher(t, server.URL, nil)
+	runDispatcher(t, dispatcher)
+	assert.NoError(t, dispatcher.Publish(context.TODO(), productEvent()))
+	waitDelivery(t, deliveryRepo, 1)
+
+	deadLetters, total, err := deliveryRepo.List(context.TODO(), repository.WebhookDeliveryFilter{
+		Status: repository.WebhookDeliveryDead,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Equal(t, "unexpected status 500: boom", deadLetters[0].LastError)
+}
+
+func dispatcherSkipEventType(t *testing.T) {
+	dispatcher, deliveryRepo := newTestDispatcher(t, "http://localhost", []string{entity.ProductDeletedEvent})
+	assert.NoError(t, dispatcher.Publish(context.TODO(), productEvent()))
+
+	_, err := deliveryRepo.GetByID(context.TODO(), 1)
+	assert.ErrorIs(t, err, entity.DeliveryNotFoundErr)
+}
+
+func dispatcherPublishAgain(t *testing.T) {
+	dispatcher, deliveryRepo := newTestDispatcher(t, "http://localhost", nil)
+	assert.NoError(t, dispatcher.Publish(context.TODO(), productEvent()))
+	assert.NoError(t, dispatcher.Publish(context.TODO(), productEvent()))
+
+	_, total, err := deliveryRepo.List(context.TODO(), repository.WebhookDeliveryFilter{
+		Status: repository.WebhookDeliveryPending,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func dispatcherResumePending(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dispatcher, deliveryRepo := newTestDispatcher(t, server.URL, nil)
+	_, err := deliveryRepo.Insert(context.TODO(), repository.WebhookDeliveryData{
+		WebhookID: 1,
+		EventID:   productEvent().EventID,
+		EventType: productEvent().EventType,
+		Payload:   []byte(`{}`),
+		Status:    repository.WebhookDeliveryPending,
+		Attempts:  1,
+	})
+	assert.NoError(t, err)
+	runDispatcher(t, dispatcher)
+
+	delivery := waitDelivery(t, deliveryRepo, 1)
+	assert.Equal(t, repository.WebhookDeliveryDelivered, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func dispatcherBoundedWorkers(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			observed := peak.Load()
+			if current <= observed || peak.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dispatcher, deliveryRepo := newTestDispatcher(t, server.URL, nil)
+	for index := 0; index < 6; index++ {
+		event := productEvent()
+		event.EventID = strconv.Itoa(index)
+		assert.NoError(t, dispatcher.Publish(context.TODO(), event))
+	}
+	runDispatcher(t, dispatcher)
+
+	for id := int64(1); id <= 6; id++ {
+		assert.Equal(t, repository.WebhookDeliveryDelivered, waitDelivery(t, deliveryRepo, id).Status)
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}